@@ -0,0 +1,63 @@
+// Command okta-export writes the Terraform configuration of an existing org:
+// the okta_* resources of its objects and the import blocks bringing them
+// under management. The org is configured the same as the provider, by ENV
+// vars, e.g. OKTA_ORG_NAME, OKTA_BASE_URL and OKTA_API_TOKEN.
+//
+//	okta-export -out org.tf -kinds groups,group_rules,apps
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta"
+)
+
+func main() {
+	out := flag.String("out", "", "file the configuration is written to, standard output if blank")
+	kinds := flag.String("kinds", "", "comma separated kinds of objects to export, all of them if blank: "+strings.Join(okta.ExportKinds(), ", "))
+	flag.Parse()
+
+	if err := export(*out, *kinds); err != nil {
+		fmt.Fprintf(os.Stderr, "okta-export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func export(out, kinds string) error {
+	ctx := context.Background()
+	p := okta.Provider()
+	defer okta.Shutdown()
+	if diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{})); diags.HasError() {
+		return fmt.Errorf("failed to configure the provider: %s", diags[0].Summary)
+	}
+
+	var selected []string
+	if kinds != "" {
+		selected = strings.Split(kinds, ",")
+	}
+	export, err := okta.ExportOrg(ctx, p, selected)
+	if err != nil {
+		return err
+	}
+	for _, skipped := range export.Skipped {
+		fmt.Fprintf(os.Stderr, "skipped %s\n", skipped)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	_, err = w.Write(export.Config)
+	return err
+}
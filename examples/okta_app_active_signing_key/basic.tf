@@ -0,0 +1,26 @@
+resource "okta_app_saml" "test" {
+  label                    = "testAcc_replace_with_uuid"
+  sso_url                  = "http://google.com"
+  recipient                = "http://here.com"
+  destination              = "http://its-about-the-journey.com"
+  audience                 = "http://audience.com"
+  subject_name_id_template = "$${user.userName}"
+  subject_name_id_format   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+  response_signed          = true
+  signature_algorithm      = "RSA_SHA256"
+  digest_algorithm         = "SHA256"
+  authn_context_class_ref  = "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"
+}
+
+resource "okta_app_signing_key" "first" {
+  app_id = okta_app_saml.test.id
+}
+
+resource "okta_app_signing_key" "second" {
+  app_id = okta_app_saml.test.id
+}
+
+resource "okta_app_active_signing_key" "test" {
+  app_id = okta_app_saml.test.id
+  kid    = okta_app_signing_key.first.kid
+}
@@ -0,0 +1,27 @@
+resource "okta_app_saml" "test" {
+  label                    = "testAcc_replace_with_uuid"
+  sso_url                  = "http://google.com"
+  recipient                = "http://here.com"
+  destination              = "http://its-about-the-journey.com"
+  audience                 = "http://audience.com"
+  subject_name_id_template = "$${user.userName}"
+  subject_name_id_format   = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+  response_signed          = true
+  signature_algorithm      = "RSA_SHA256"
+  digest_algorithm         = "SHA256"
+  authn_context_class_ref  = "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"
+}
+
+resource "okta_app_csr" "test" {
+  app_id    = okta_app_saml.test.id
+  dns_names = ["example.com"]
+
+  subject {
+    common_name              = "SAML signing certificate"
+    country_name             = "US"
+    state_or_province_name   = "California"
+    locality_name            = "San Francisco"
+    organization_name        = "Example"
+    organizational_unit_name = "IT"
+  }
+}
@@ -0,0 +1,28 @@
+resource "okta_app_oauth" "test_app" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "service"
+  response_types = ["token"]
+  grant_types    = ["client_credentials"]
+}
+
+resource "okta_auth_server" "test" {
+  name      = "testAcc_replace_with_uuid"
+  audiences = ["api://testAcc"]
+}
+
+resource "okta_auth_server_scope" "test" {
+  auth_server_id = okta_auth_server.test.id
+  name           = "testAcc.read"
+  consent        = "IMPLICIT"
+}
+
+resource "okta_app_oauth_api_scope" "test_app_scopes" {
+  app_id         = okta_app_oauth.test_app.id
+  auth_server_id = okta_auth_server.test.id
+  scopes         = [okta_auth_server_scope.test.name]
+}
+
+data "okta_app_oauth_api_scopes" "test" {
+  app_id = okta_app_oauth_api_scope.test_app_scopes.app_id
+  issuer = okta_app_oauth_api_scope.test_app_scopes.issuer
+}
@@ -0,0 +1,3 @@
+data "okta_app_oauth_api_scopes" "example" {
+  app_id = "<app id>"
+}
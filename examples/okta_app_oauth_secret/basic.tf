@@ -0,0 +1,10 @@
+resource "okta_app_oauth" "test" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "service"
+  response_types = ["token"]
+  grant_types    = ["client_credentials"]
+}
+
+resource "okta_app_oauth_secret" "test" {
+  app_id = okta_app_oauth.test.id
+}
@@ -0,0 +1,16 @@
+resource "okta_app_oauth" "test" {
+  label          = "testAcc_replace_with_uuid"
+  type           = "service"
+  response_types = ["token"]
+  grant_types    = ["client_credentials"]
+}
+
+// Deactivated once the new secret is deployed
+resource "okta_app_oauth_secret" "test" {
+  app_id = okta_app_oauth.test.id
+  status = "INACTIVE"
+}
+
+resource "okta_app_oauth_secret" "test_new" {
+  app_id = okta_app_oauth.test.id
+}
@@ -0,0 +1,15 @@
+resource "okta_app_saml" "test" {
+  preconfigured_app = "okta_org2org"
+  label             = "testAcc_replace_with_uuid"
+  app_settings_json = <<JSON
+    {
+      "baseUrl": "https://example.okta.com"
+    }
+JSON
+}
+
+resource "okta_app_provisioning_connection" "test" {
+  app_id = okta_app_saml.test.id
+  token  = "secret-api-token"
+  status = "INACTIVE"
+}
@@ -0,0 +1,9 @@
+data "okta_expression" "valid" {
+  expression = "user.department==\"Engineering\" and isMemberOfGroupName(\"Admins\")"
+  context    = "group_rule"
+}
+
+data "okta_expression" "invalid" {
+  expression = "String.len(user.login, 1) == \"5\" AND appuser.login"
+  context    = "group_rule"
+}
@@ -2,6 +2,9 @@
 package main
 
 import (
+	"context"
+	"log"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/okta/terraform-provider-okta/okta"
@@ -18,7 +21,14 @@ func main() {
 	// this will be used in document generation.
 	schema.DescriptionKind = schema.StringMarkdown
 
+	// The plugin SDK and plugin framework resources are served by one muxed
+	// server.
+	server, err := okta.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatalf("[ERROR] failed to create the provider server: %v", err)
+	}
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: okta.Provider,
+		GRPCProviderFunc: server,
 	})
+	okta.Shutdown()
 }
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
@@ -17,19 +20,10 @@ import (
 	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
 	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/cache"
 )
 
-func (adt *AddHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Add("User-Agent", "Okta Terraform Provider")
-	return adt.T.RoundTrip(req)
-}
-
 type (
-	// AddHeaderTransport used to tack on default headers to outgoing requests
-	AddHeaderTransport struct {
-		T http.RoundTripper
-	}
-
 	// Config contains our provider schema values and Okta clients
 	Config struct {
 		orgName          string
@@ -40,7 +34,13 @@ type (
 		clientID         string
 		privateKey       string
 		privateKeyId     string
+		privateKeys      []string
+		keyPassphrase    string
+		dpop             bool
+		permissionAware  bool
 		scopes           []string
+		grantedScopes    []string
+		scopesWarned     *sync.Map // the types warnMissingScope warned about
 		retryCount       int
 		parallelism      int
 		backoff          bool
@@ -48,15 +48,65 @@ type (
 		maxWait          int
 		logLevel         int
 		requestTimeout   int
-		maxAPICapacity   int // experimental
+		maxAPICapacity   int    // experimental
+		apiStateFile     string // experimental
+		apiBucketsFile   string // experimental
+		usageReportFile  string
+		responseCache    string
+		responseCacheDir string
 		oktaClient       *sdk.Client
 		v3Client         *okta.APIClient
 		supplementClient *sdk.APISupplement
 		logger           hclog.Logger
 		classicOrg       bool
+		apiMutex         *apimutex.APIMutex
+		usage            *transport.UsageRecorder
+		cacheManager     cache.Cache
+		keySigner        *sdk.FallbackSigner
+		capabilities     *capabilities
+		pipeline         *transport.Pipeline
+		roundTripper     http.RoundTripper
 	}
 )
 
+// sharedConfig is the Config of a provider process. The plugin SDK provider
+// and the plugin framework provider are served side by side, see
+// ProviderServer, and share it so that they use the same clients, rate
+// limiting and caches. The first of them to be configured loads it, the other
+// gets the loaded Config, and has to be configured with the same settings.
+type sharedConfig struct {
+	lock   sync.Mutex
+	config *Config
+	// settings are the settings the Config was loaded with.
+	settings Config
+}
+
+// load loads and validates the Config, unless a Config was already loaded,
+// which is returned instead. Loading a Config with other settings than the
+// loaded one fails, the providers sharing it would talk to different orgs or
+// as different principals.
+func (s *sharedConfig) load(ctx context.Context, c *Config) (*Config, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.config != nil {
+		if !reflect.DeepEqual(s.settings, *c) {
+			return nil, fmt.Errorf("the provider is already configured with different settings, the plugin SDK and plugin framework providers have to be configured the same")
+		}
+		return s.config, nil
+	}
+	settings := *c
+	if err := c.loadAndValidate(ctx); err != nil {
+		return nil, err
+	}
+	// Discover if the Okta Org is Classic or OIE
+	if org, _, err := c.supplementClient.GetWellKnownOktaOrganization(ctx); err == nil {
+		c.classicOrg = (org.Pipeline == "v1") // v1 == Classic, idx == OIE
+	}
+	s.config = c
+	s.settings = settings
+	return c, nil
+}
+
 func (c *Config) loadAndValidate(ctx context.Context) error {
 	c.logger = providerLogger(c)
 
@@ -87,6 +137,17 @@ func (c *Config) loadAndValidate(ctx context.Context) error {
 		return err
 	}
 	c.v3Client = v3Client
+
+	if os.Getenv("OKTA_VCR_TF_ACC") == "" {
+		switch {
+		case c.hasPrivateKey():
+			if err := preflightScopes(c); err != nil {
+				return err
+			}
+		case c.permissionAware:
+			discoverCapabilities(ctx, c)
+		}
+	}
 	return nil
 }
 
@@ -102,66 +163,296 @@ func providerLogger(c *Config) hclog.Logger {
 	})
 }
 
-func oktaSDKClient(c *Config) (client *sdk.Client, err error) {
-	var httpClient *http.Client
-	logLevel := strings.ToLower(os.Getenv("TF_LOG"))
-	debugHttpRequests := (logLevel == "1" || logLevel == "debug" || logLevel == "trace")
-	if c.backoff {
-		retryableClient := retryablehttp.NewClient()
-		retryableClient.RetryWaitMin = time.Second * time.Duration(c.minWait)
-		retryableClient.RetryWaitMax = time.Second * time.Duration(c.maxWait)
-		retryableClient.RetryMax = c.retryCount
-		retryableClient.Logger = c.logger
-		if debugHttpRequests {
-			// Needed for pretty printing http protocol in a local developer environment, ignore deprecation warnings.
-			//lint:ignore SA1019 used in developer mode only
-			retryableClient.HTTPClient.Transport = logging.NewTransport("Okta", retryableClient.HTTPClient.Transport)
-		} else {
-			retryableClient.HTTPClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Okta", retryableClient.HTTPClient.Transport)
+// transportPipeline returns the ordered middleware stages, auth, user agent,
+// key fallback, cache, usage, retry, usage attempt, governor, dpop and
+// logging, shared by the v2 and v3
+// Okta clients. It is built once per config so both clients account against
+// the same api mutex. The governor sits inside of retry so that it sees, and
+// paces, every attempt, and every attempt gets a DPoP proof of its own.
+func (c *Config) transportPipeline() (*transport.Pipeline, error) {
+	if c.pipeline != nil {
+		return c.pipeline, nil
+	}
+	if c.logger == nil {
+		c.logger = providerLogger(c)
+	}
+
+	var authStage, keyFallbackStage, dpopStage transport.Middleware
+	switch {
+	case c.accessToken != "":
+		authStage = transport.HeaderMiddleware("Authorization", "Bearer "+c.accessToken)
+	case c.apiToken != "":
+		authStage = transport.HeaderMiddleware("Authorization", "SSWS "+c.apiToken)
+	case c.hasPrivateKey():
+		signer, err := c.privateKeySigner()
+		if err != nil {
+			return nil, err
 		}
-		retryableClient.ErrorHandler = errHandler
-		retryableClient.CheckRetry = checkRetry
-		httpClient = retryableClient.StandardClient()
-		c.logger.Info(fmt.Sprintf("running with backoff http client, wait min %d, wait max %d, retry max %d", retryableClient.RetryWaitMin, retryableClient.RetryWaitMax, retryableClient.RetryMax))
-	} else {
-		httpClient = cleanhttp.DefaultClient()
-		if debugHttpRequests {
-			// Needed for pretty printing http protocol in a local developer environment, ignore deprecation warnings.
-			//lint:ignore SA1019 used in developer mode only
-			httpClient.Transport = logging.NewTransport("Okta", httpClient.Transport)
-		} else {
-			httpClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Okta", httpClient.Transport)
+		keyFallbackStage = func(next http.RoundTripper) http.RoundTripper {
+			return transport.NewKeyFallbackTransport(next, signer, c.logger)
 		}
-		c.logger.Info("running with default http client")
+		if c.dpop {
+			prover, err := sdk.NewDPoPProver()
+			if err != nil {
+				return nil, err
+			}
+			c.logger.Info("running with DPoP bound access tokens")
+			dpopStage = func(next http.RoundTripper) http.RoundTripper {
+				return transport.NewDPoPTransport(next, prover)
+			}
+		}
+	}
+
+	// the api mutex classifies requests for both the governor and the usage
+	// report
+	governed := c.maxAPICapacity > 0 && c.maxAPICapacity < 100
+	if governed || c.usageReportFile != "" {
+		apiMutex, err := apimutex.NewAPIMutex(c.maxAPICapacity)
+		if err != nil {
+			return nil, err
+		}
+		if c.apiBucketsFile != "" {
+			mappings, err := apimutex.LoadBucketMappings(c.apiBucketsFile)
+			if err != nil {
+				return nil, err
+			}
+			c.logger.Info(fmt.Sprintf("overriding %d max_api_capacity bucket mapping(s) from %q", len(mappings), c.apiBucketsFile))
+			apiMutex.AddBuckets(mappings)
+		}
+		c.apiMutex = apiMutex
 	}
 
 	// adds transport governor to retryable or default client
-	if c.maxAPICapacity > 0 && c.maxAPICapacity < 100 {
+	var governorStage transport.Middleware
+	if governed {
 		c.logger.Info(fmt.Sprintf("running with experimental max_api_capacity configuration at %d%%", c.maxAPICapacity))
-		apiMutex, err := apimutex.NewAPIMutex(c.maxAPICapacity)
+		if c.apiStateFile != "" {
+			store, err := apimutex.NewFileStore(c.apiStateFile)
+			if err != nil {
+				return nil, err
+			}
+			c.logger.Info(fmt.Sprintf("sharing max_api_capacity status through state file %q", c.apiStateFile))
+			c.apiMutex.SetStore(store)
+		}
+		governorStage = func(next http.RoundTripper) http.RoundTripper {
+			return transport.NewGovernedTransport(next, c.apiMutex, transport.MaxInFlight(c.parallelism), c.logger)
+		}
+	}
+
+	var usageStage, usageAttemptStage transport.Middleware
+	if c.usageReportFile != "" {
+		c.logger.Info(fmt.Sprintf("writing API usage report to %q at shutdown", c.usageReportFile))
+		c.usage = transport.NewUsageRecorder(c.apiMutex)
+		usageStage = c.usage.RequestMiddleware()
+		usageAttemptStage = c.usage.AttemptMiddleware()
+		onShutdown(func() {
+			if err := c.usage.WriteReport(c.usageReportFile); err != nil {
+				c.logger.Error(fmt.Sprintf("failed to write API usage report to %q: %+v", c.usageReportFile, err))
+			}
+		})
+	}
+
+	var cacheStage transport.Middleware
+	if c.responseCache != "" {
+		c.logger.Info(fmt.Sprintf("running with %s response cache", c.responseCache))
+		responseCache, err := c.newResponseCache()
+		if err != nil {
+			return nil, err
+		}
+		c.cacheManager = responseCache
+		cacheStage = transport.CacheInvalidationMiddleware(responseCache)
+	}
+
+	var retryStage transport.Middleware
+	if c.backoff {
+		retryStage = func(next http.RoundTripper) http.RoundTripper {
+			retryableClient := retryablehttp.NewClient()
+			retryableClient.HTTPClient.Transport = next
+			retryableClient.RetryWaitMin = time.Second * time.Duration(c.minWait)
+			retryableClient.RetryWaitMax = time.Second * time.Duration(c.maxWait)
+			retryableClient.RetryMax = c.retryCount
+			retryableClient.Logger = c.logger
+			retryableClient.ErrorHandler = errHandler
+			retryableClient.CheckRetry = checkRetry
+			retryableClient.Backoff = retryBackoff
+			c.logger.Info(fmt.Sprintf("running with backoff http client, wait min %d, wait max %d, retry max %d", retryableClient.RetryWaitMin, retryableClient.RetryWaitMax, retryableClient.RetryMax))
+			return &retryablehttp.RoundTripper{Client: retryableClient}
+		}
+	} else {
+		c.logger.Info("running with default http client")
+	}
+
+	// requests and responses are only logged at debug and trace levels, below
+	// them nothing is buffered for the logs. VCR cassettes are redacted by the
+	// recorder's save filter.
+	var loggingStage transport.Middleware
+	if debugLogging("TF_LOG", "TF_LOG_PROVIDER", "TF_LOG_PROVIDER_OKTA") {
+		debugHttpRequests := debugLogging("TF_LOG")
+		loggingStage = func(next http.RoundTripper) http.RoundTripper {
+			// the SDK's logging transports log the bodies as they are, they
+			// log copies with the secrets redacted
+			return transport.NewRedactingLoggingTransport(next, transport.OktaSecrets, func(next http.RoundTripper) http.RoundTripper {
+				if debugHttpRequests {
+					// Needed for pretty printing http protocol in a local developer environment, ignore deprecation warnings.
+					//lint:ignore SA1019 used in developer mode only
+					return logging.NewTransport("Okta", next)
+				}
+				return logging.NewSubsystemLoggingHTTPTransport("Okta", next)
+			})
+		}
+	}
+
+	c.pipeline = transport.NewPipeline(
+		transport.Stage{Name: transport.StageAuth, Middleware: authStage},
+		transport.Stage{Name: transport.StageKeyFallback, Middleware: keyFallbackStage},
+		transport.Stage{Name: transport.StageCache, Middleware: cacheStage},
+		transport.Stage{Name: transport.StageUsage, Middleware: usageStage},
+		transport.Stage{Name: transport.StageRetry, Middleware: retryStage},
+		transport.Stage{Name: transport.StageUsageAttempt, Middleware: usageAttemptStage},
+		transport.Stage{Name: transport.StageGovernor, Middleware: governorStage},
+		transport.Stage{Name: transport.StageDPoP, Middleware: dpopStage},
+		transport.Stage{Name: transport.StageLogging, Middleware: loggingStage},
+	)
+	c.roundTripper = c.pipeline.RoundTripper(cleanhttp.DefaultPooledTransport())
+	return c.pipeline, nil
+}
+
+// debugLogging reports if any of the log level environment variables is at
+// the debug or trace level.
+func debugLogging(envs ...string) bool {
+	for _, env := range envs {
+		switch strings.ToLower(os.Getenv(env)) {
+		case "1", "debug", "trace":
+			return true
+		}
+	}
+	return false
+}
+
+// httpClient returns a new http client on top of the shared transport
+// pipeline. Each Okta client gets its own http client value so that tests can
+// swap out one client's transport without affecting the other.
+func (c *Config) httpClient() (*http.Client, error) {
+	if _, err := c.transportPipeline(); err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: c.roundTripper}, nil
+}
+
+var (
+	shutdownLock  sync.Mutex
+	shutdownHooks []func()
+)
+
+// onShutdown registers a hook to be run by Shutdown.
+func onShutdown(hook func()) {
+	shutdownLock.Lock()
+	defer shutdownLock.Unlock()
+	shutdownHooks = append(shutdownHooks, hook)
+}
+
+// Shutdown runs the shutdown hooks of the configured providers, writing API
+// usage reports and removing on disk response caches. It is called once the
+// plugin server stops.
+func Shutdown() {
+	shutdownLock.Lock()
+	defer shutdownLock.Unlock()
+	for _, hook := range shutdownHooks {
+		hook()
+	}
+	shutdownHooks = nil
+}
+
+// newResponseCache returns the response cache of the response_cache mode. The
+// cache lives as long as the provider process, which is a single plan or
+// apply, so its entries don't expire.
+func (c *Config) newResponseCache() (cache.Cache, error) {
+	switch c.responseCache {
+	case "memory":
+		return cache.NewGoCache(0, 0), nil
+	case "disk":
+		dir, err := os.MkdirTemp(c.responseCacheDir, "terraform-provider-okta-cache-")
 		if err != nil {
 			return nil, err
 		}
-		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.logger)
+		onShutdown(func() {
+			_ = os.RemoveAll(dir)
+		})
+		return cache.NewFileCache(dir, 0)
+	}
+	return nil, fmt.Errorf("unknown response_cache %q, expected memory or disk", c.responseCache)
+}
+
+func (c *Config) hasPrivateKey() bool {
+	return c.privateKey != "" || len(c.privateKeys) > 0
+}
+
+// privateKeySigner returns the signer of the private key flow. It signs with
+// private_key and falls back on the keys of private_keys, in order, when Okta
+// rejects a key. Each of them is a PEM, a JWK or JWK set, or a path to a file
+// of one. private_key_id is the kid of private_key, JWKs have their own.
+func (c *Config) privateKeySigner() (*sdk.FallbackSigner, error) {
+	if c.keySigner != nil {
+		return c.keySigner, nil
+	}
+	var keys []sdk.PrivateKey
+	for i, source := range append([]string{c.privateKey}, c.privateKeys...) {
+		if source == "" {
+			continue
+		}
+		sourceKeys, err := sdk.ReadPrivateKeys(source, c.keyPassphrase)
+		if err != nil {
+			if i == 0 {
+				return nil, fmt.Errorf("invalid private_key: %w", err)
+			}
+			return nil, fmt.Errorf("invalid private_keys.%d: %w", i-1, err)
+		}
+		for _, key := range sourceKeys {
+			if i == 0 && key.KeyID == "" {
+				key.KeyID = c.privateKeyId
+			}
+			keys = append(keys, key)
+		}
+	}
+	signer, err := sdk.NewFallbackSigner(keys, "")
+	if err != nil {
+		return nil, err
 	}
-	var orgUrl string
-	var disableHTTPS bool
+	c.keySigner = signer
+	return signer, nil
+}
+
+// orgURL returns the org url the clients connect to and if the https check
+// needs to be disabled because of an http proxy.
+func (c *Config) orgURL() (orgUrl string, disableHTTPS bool) {
 	if c.httpProxy != "" {
 		orgUrl = strings.TrimSuffix(c.httpProxy, "/")
 		disableHTTPS = strings.HasPrefix(orgUrl, "http://")
 	} else {
 		orgUrl = fmt.Sprintf("https://%v.%v", c.orgName, c.domain)
 	}
+	return
+}
+
+func oktaSDKClient(c *Config) (client *sdk.Client, err error) {
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	orgUrl, disableHTTPS := c.orgURL()
 
 	setters := []sdk.ConfigSetter{
 		sdk.WithOrgUrl(orgUrl),
-		sdk.WithCache(false),
+		sdk.WithCache(c.cacheManager != nil),
 		sdk.WithHttpClientPtr(httpClient),
 		sdk.WithRateLimitMaxBackOff(int64(c.maxWait)),
 		sdk.WithRequestTimeout(int64(c.requestTimeout)),
 		sdk.WithRateLimitMaxRetries(int32(c.retryCount)),
 		sdk.WithUserAgentExtra("okta-terraform/4.0.0"),
 	}
+	if c.cacheManager != nil {
+		setters = append(setters, sdk.WithCacheManager(c.cacheManager))
+	}
 
 	switch {
 	case c.accessToken != "":
@@ -176,10 +467,10 @@ func oktaSDKClient(c *Config) (client *sdk.Client, err error) {
 			sdk.WithToken(c.apiToken), sdk.WithAuthorizationMode("SSWS"),
 		)
 
-	case c.privateKey != "":
+	case c.hasPrivateKey():
 		setters = append(
 			setters,
-			sdk.WithPrivateKey(c.privateKey), sdk.WithPrivateKeyId(c.privateKeyId), sdk.WithScopes(c.scopes), sdk.WithClientId(c.clientID), sdk.WithAuthorizationMode("PrivateKey"),
+			sdk.WithPrivateKeySigner(c.keySigner), sdk.WithScopes(c.scopes), sdk.WithClientId(c.clientID), sdk.WithAuthorizationMode("PrivateKey"),
 		)
 	}
 
@@ -196,55 +487,13 @@ func oktaSDKClient(c *Config) (client *sdk.Client, err error) {
 
 // TODO switch to oktaSDKClient when migration complete
 func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
-	var httpClient *http.Client
-	logLevel := strings.ToLower(os.Getenv("TF_LOG"))
-	debugHttpRequests := (logLevel == "1" || logLevel == "debug" || logLevel == "trace")
-	if c.backoff {
-		retryableClient := retryablehttp.NewClient()
-		retryableClient.RetryWaitMin = time.Second * time.Duration(c.minWait)
-		retryableClient.RetryWaitMax = time.Second * time.Duration(c.maxWait)
-		retryableClient.RetryMax = c.retryCount
-		retryableClient.Logger = c.logger
-		if debugHttpRequests {
-			// Needed for pretty printing http protocol in a local developer environment, ignore deprecation warnings.
-			//lint:ignore SA1019 used in developer mode only
-			retryableClient.HTTPClient.Transport = logging.NewTransport("Okta", retryableClient.HTTPClient.Transport)
-		} else {
-			retryableClient.HTTPClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Okta", retryableClient.HTTPClient.Transport)
-		}
-		retryableClient.ErrorHandler = errHandler
-		retryableClient.CheckRetry = checkRetry
-		httpClient = retryableClient.StandardClient()
-		c.logger.Info(fmt.Sprintf("running with backoff http client, wait min %d, wait max %d, retry max %d", retryableClient.RetryWaitMin, retryableClient.RetryWaitMax, retryableClient.RetryMax))
-	} else {
-		httpClient = cleanhttp.DefaultClient()
-		if debugHttpRequests {
-			// Needed for pretty printing http protocol in a local developer environment, ignore deprecation warnings.
-			//lint:ignore SA1019 used in developer mode only
-			httpClient.Transport = logging.NewTransport("Okta", httpClient.Transport)
-		} else {
-			httpClient.Transport = logging.NewSubsystemLoggingHTTPTransport("Okta", httpClient.Transport)
-		}
-		c.logger.Info("running with default http client")
-	}
-
-	// adds transport governor to retryable or default client
-	if c.maxAPICapacity > 0 && c.maxAPICapacity < 100 {
-		c.logger.Info(fmt.Sprintf("running with experimental max_api_capacity configuration at %d%%", c.maxAPICapacity))
-		apiMutex, err := apimutex.NewAPIMutex(c.maxAPICapacity)
-		if err != nil {
-			return nil, err
-		}
-		httpClient.Transport = transport.NewGovernedTransport(httpClient.Transport, apiMutex, c.logger)
-	}
-	var orgUrl string
-	var disableHTTPS bool
-	if c.httpProxy != "" {
-		orgUrl = strings.TrimSuffix(c.httpProxy, "/")
-		disableHTTPS = strings.HasPrefix(orgUrl, "http://")
-	} else {
-		orgUrl = fmt.Sprintf("https://%v.%v", c.orgName, c.domain)
+	httpClient, err := c.httpClient()
+	if err != nil {
+		return nil, err
 	}
+	// the v2 request executor coalesces identical GETs itself
+	httpClient.Transport = transport.NewCoalescingTransport(httpClient.Transport)
+	orgUrl, disableHTTPS := c.orgURL()
 
 	setters := []okta.ConfigSetter{
 		okta.WithOrgUrl(orgUrl),
@@ -269,10 +518,10 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 			okta.WithToken(c.apiToken), okta.WithAuthorizationMode("SSWS"),
 		)
 
-	case c.privateKey != "":
+	case c.hasPrivateKey():
 		setters = append(
 			setters,
-			okta.WithPrivateKey(c.privateKey), okta.WithPrivateKeyId(c.privateKeyId), okta.WithScopes(c.scopes), okta.WithClientId(c.clientID), okta.WithAuthorizationMode("PrivateKey"),
+			okta.WithPrivateKeySigner(c.keySigner), okta.WithScopes(c.scopes), okta.WithClientId(c.clientID), okta.WithAuthorizationMode("PrivateKey"),
 		)
 	}
 
@@ -281,6 +530,11 @@ func oktaV3SDKClient(c *Config) (client *okta.APIClient, err error) {
 	}
 
 	config := okta.NewConfiguration(setters...)
+	// the v3 client takes the host name of the org url, an http proxy's
+	// port would be lost
+	if u, err := url.Parse(orgUrl); err == nil && u.Port() != "" {
+		config.Host = u.Host
+	}
 	client = okta.NewAPIClient(config)
 	return
 }
@@ -326,3 +580,12 @@ func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, erro
 	}
 	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
 }
+
+// retryBackoff retries a concurrent rate limit violation shortly, it clears as
+// soon as requests in flight complete, and backs off the others exponentially.
+func retryBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if sdk.IsConcurrencyLimitResponse(resp) {
+		return time.Second * sdk.ConcurrencyLimitBackoff
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+}
@@ -2,13 +2,27 @@ package okta
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
+	"github.com/okta/terraform-provider-okta/sdk/cache"
 )
 
 func TestConfigLoadAndValidate(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
 	tests := []struct {
 		name         string
 		accessToken  string
@@ -23,9 +37,12 @@ func TestConfigLoadAndValidate(t *testing.T) {
 		// NOTE: don't test apiToken, it causes a hit to the wire with a "GET
 		//       /api/v1/users/me" and the test tokens are scrubbed for this test
 		// {"api_token = pass", "", "apiToken", "", "", "", nil, false},
-		{"client_id, private_key, scopes = pass", "", "", "clientID", "privateKey", "", []string{"scope1", "scope2"}, false},
-		{"client_id, private_key, private_key_id, scopes = pass", "", "", "clientID", "privateKey", "privateKeyID", []string{"scope1", "scope2"}, false},
+		{"client_id, private_key, scopes = pass", "", "", "clientID", privateKey, "", []string{"scope1", "scope2"}, false},
+		{"client_id, private_key, private_key_id, scopes = pass", "", "", "clientID", privateKey, "privateKeyID", []string{"scope1", "scope2"}, false},
+		{"client_id, invalid private_key, scopes = fail", "", "", "clientID", "privateKey", "", []string{"scope1", "scope2"}, true},
+		{"client_id, private_key, ungranted scopes = fail", "", "", "clientID", privateKey, "", []string{"scope1", "scope3"}, true},
 	}
+	tokenServer := newTokenServer(t, "scope1", "scope2")
 
 	for _, test := range tests {
 		config := Config{
@@ -37,6 +54,7 @@ func TestConfigLoadAndValidate(t *testing.T) {
 			privateKey:   test.privateKey,
 			privateKeyId: test.privateKeyID,
 			scopes:       test.scopes,
+			httpProxy:    tokenServer.URL,
 			logLevel:     int(hclog.Warn),
 		}
 		err := config.loadAndValidate(context.TODO())
@@ -50,3 +68,238 @@ func TestConfigLoadAndValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigSharedTransport(t *testing.T) {
+	clearLogLevels(t)
+	config := Config{
+		orgName:        "test",
+		domain:         "okta.com",
+		accessToken:    "accessToken",
+		backoff:        true,
+		maxAPICapacity: 50,
+		logLevel:       int(hclog.Warn),
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	if config.apiMutex == nil {
+		t.Fatal("expected the config to have an api mutex")
+	}
+	v2Transport := config.oktaClient.GetConfig().HttpClient.Transport
+	if v2Transport != config.roundTripper {
+		t.Errorf("expected v2 client to use the shared transport pipeline")
+	}
+	// the v3 client coalesces GETs on top of the shared pipeline
+	if _, ok := config.v3Client.GetConfig().HTTPClient.Transport.(*transport.CoalescingTransport); !ok {
+		t.Errorf("expected v3 client to coalesce requests on top of the shared transport pipeline")
+	}
+	expected := []string{"auth", "retry", "governor"}
+	if stages := config.pipeline.Stages(); strings.Join(stages, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected pipeline stages %v, got %v", expected, stages)
+	}
+}
+
+func TestConfigUsageReport(t *testing.T) {
+	clearLogLevels(t)
+	reportFile := filepath.Join(t.TempDir(), "usage.json")
+	config := Config{
+		orgName:         "test",
+		domain:          "okta.com",
+		accessToken:     "accessToken",
+		usageReportFile: reportFile,
+		logLevel:        int(hclog.Warn),
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	expected := []string{"auth", "usage", "usage-attempt"}
+	if stages := config.pipeline.Stages(); strings.Join(stages, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected pipeline stages %v, got %v", expected, stages)
+	}
+	Shutdown()
+	if _, err := os.Stat(reportFile); err != nil {
+		t.Errorf("expected usage report to be written: %+v", err)
+	}
+}
+
+func TestConfigResponseCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	config := Config{
+		orgName:          "test",
+		domain:           "okta.com",
+		accessToken:      "accessToken",
+		responseCache:    "disk",
+		responseCacheDir: cacheDir,
+		logLevel:         int(hclog.Warn),
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	if _, ok := config.oktaClient.GetConfig().CacheManager.(cache.FileCache); !ok {
+		t.Errorf("expected the okta client to use the disk cache, got %T", config.oktaClient.GetConfig().CacheManager)
+	}
+	Shutdown()
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("expected the disk cache to be removed at shutdown, found %d entries", len(entries))
+	}
+}
+
+func TestConfigDPoP(t *testing.T) {
+	clearLogLevels(t)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	config := Config{
+		orgName:    "test",
+		domain:     "okta.com",
+		clientID:   "clientID",
+		privateKey: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})),
+		scopes:     []string{"okta.users.read"},
+		dpop:       true,
+		httpProxy:  newTokenServer(t, "okta.users.read").URL,
+		logLevel:   int(hclog.Warn),
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	expected := []string{"key-fallback", "dpop"}
+	if stages := config.pipeline.Stages(); strings.Join(stages, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected pipeline stages %v, got %v", expected, stages)
+	}
+}
+
+func TestConfigPreflightScopes(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	tokenServer := newTokenServer(t, "okta.users.read", "okta.groups.manage")
+	newConfig := func(permissionAware bool) Config {
+		return Config{
+			orgName:         "test",
+			domain:          "okta.com",
+			clientID:        "clientID",
+			privateKey:      privateKey,
+			scopes:          []string{"okta.users.read", "okta.groups.manage"},
+			httpProxy:       tokenServer.URL,
+			logLevel:        int(hclog.Warn),
+			permissionAware: permissionAware,
+		}
+	}
+
+	config := newConfig(true)
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	if config.capabilities == nil || !config.capabilities.granted {
+		t.Fatal("expected the capabilities of the granted scopes")
+	}
+	if !config.capabilities.canManage(permGroups) || !config.capabilities.canRead(permUsers) || config.capabilities.canManage(permUsers) {
+		t.Errorf("expected to manage groups and only read users, got %s", config.capabilities.source)
+	}
+
+	// the granted scopes are only enforced with permission_aware
+	config = newConfig(false)
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	if config.capabilities != nil {
+		t.Errorf("didn't expect capabilities without permission_aware, got %s", config.capabilities.source)
+	}
+	if strings.Join(config.grantedScopes, ",") != "okta.users.read,okta.groups.manage" {
+		t.Errorf("expected the granted scopes, got %v", config.grantedScopes)
+	}
+}
+
+func TestConfigPreflightScopesUnknown(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	// a token response without the granted scopes
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token_type":"Bearer","expires_in":3600,"access_token":"token"}`))
+	}))
+	defer server.Close()
+	config := Config{
+		orgName:         "test",
+		domain:          "okta.com",
+		clientID:        "clientID",
+		privateKey:      string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})),
+		scopes:          []string{"okta.users.read"},
+		httpProxy:       server.URL,
+		logLevel:        int(hclog.Warn),
+		permissionAware: true,
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	if config.capabilities != nil || len(config.grantedScopes) != 0 {
+		t.Errorf("expected the configured scopes to not be trusted, got %+v and %v", config.capabilities, config.grantedScopes)
+	}
+}
+
+func TestConfigDebugLogging(t *testing.T) {
+	for _, env := range []string{"TF_LOG", "TF_LOG_PROVIDER", "TF_LOG_PROVIDER_OKTA"} {
+		clearLogLevels(t)
+		t.Setenv(env, "DEBUG")
+		config := Config{
+			orgName:     "test",
+			domain:      "okta.com",
+			accessToken: "accessToken",
+			logLevel:    int(hclog.Warn),
+		}
+		if err := config.loadAndValidate(context.TODO()); err != nil {
+			t.Fatalf("did not expect error but received error: %+v", err)
+		}
+		expected := []string{"auth", "logging"}
+		if stages := config.pipeline.Stages(); strings.Join(stages, ",") != strings.Join(expected, ",") {
+			t.Errorf("%s: expected pipeline stages %v, got %v", env, expected, stages)
+		}
+	}
+
+	clearLogLevels(t)
+	t.Setenv("TF_LOG", "INFO")
+	if debugLogging("TF_LOG", "TF_LOG_PROVIDER", "TF_LOG_PROVIDER_OKTA") {
+		t.Error("didn't expect debug logging at the info level")
+	}
+	t.Setenv("TF_LOG", "JSON")
+	if debugLogging("TF_LOG", "TF_LOG_PROVIDER", "TF_LOG_PROVIDER_OKTA") {
+		t.Error("didn't expect debug logging of the json log format")
+	}
+}
+
+// clearLogLevels unsets the log levels the transport pipeline depends on for
+// the duration of the test.
+func clearLogLevels(t *testing.T) {
+	for _, env := range []string{"TF_LOG", "TF_LOG_PROVIDER", "TF_LOG_PROVIDER_OKTA"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+}
+
+// newTokenServer returns a server of the private key flow's token endpoint
+// that grants the scopes and rejects requests of any other scope.
+func newTokenServer(t *testing.T, granted ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for _, scope := range strings.Fields(r.URL.Query().Get("scope")) {
+			if !contains(granted, scope) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprintf(w, `{"error":"invalid_scope","error_description":"The following scopes are not allowed: %s"}`, scope)
+				return
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":3600,"access_token":"token","scope":%q}`, r.URL.Query().Get("scope"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRetryBackoff(t *testing.T) {
+	response := func(remaining string) *http.Response {
+		header := http.Header{}
+		header.Set("X-Rate-Limit-Remaining", remaining)
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Header: header}
+	}
+	if got := retryBackoff(time.Second, 30*time.Second, 4, response("540")); got != time.Second {
+		t.Errorf("expected a concurrent rate limit violation to be retried after a second, got %s", got)
+	}
+	if got := retryBackoff(time.Second, 30*time.Second, 4, response("0")); got != 16*time.Second {
+		t.Errorf("expected other 429s to back off exponentially, got %s", got)
+	}
+}
@@ -0,0 +1,104 @@
+package okta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func dataSourceAppOAuthAPIScopes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppOAuthAPIScopesRead,
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the application.",
+			},
+			"issuer": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Issuer of the grants to list, all the grants of the application are listed if not set.",
+			},
+			"grants": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Scope consent grants of the application.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"scope_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"issuer": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_by_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_by_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAppOAuthAPIScopesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	grants, _, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, appID, nil)
+	if err != nil {
+		return diag.Errorf("failed to get application scope consent grants: %v", err)
+	}
+	if issuer, ok := d.GetOk("issuer"); ok {
+		grants = filterOAuthApiScopes(grants, issuer.(string))
+	}
+	arr := make([]map[string]interface{}, len(grants))
+	for i := range grants {
+		arr[i] = flattenOAuthApiScopeGrant(grants[i])
+	}
+	d.SetId(appID)
+	_ = d.Set("grants", arr)
+	return nil
+}
+
+func flattenOAuthApiScopeGrant(grant *sdk.OAuth2ScopeConsentGrant) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":       grant.Id,
+		"scope_id": grant.ScopeId,
+		"issuer":   grant.Issuer,
+		"status":   grant.Status,
+		"source":   grant.Source,
+	}
+	if grant.Created != nil {
+		m["created"] = grant.Created.UTC().String()
+	}
+	if grant.CreatedBy != nil {
+		m["created_by_id"] = grant.CreatedBy.Id
+		m["created_by_type"] = grant.CreatedBy.Type
+	}
+	return m
+}
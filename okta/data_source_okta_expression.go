@@ -0,0 +1,75 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+)
+
+// dataSourceExpression formats and lints an Okta Expression Language
+// expression without calling Okta.
+func dataSourceExpression() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceExpressionRead,
+		Schema: map[string]*schema.Schema{
+			"expression": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Okta Expression Language expression to format and lint",
+			},
+			"context": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          expression.AnyContext.String(),
+				ValidateDiagFunc: stringInSlice(expression.ContextNames()),
+				Description:      "Where the expression is evaluated, which decides the variables it can use",
+			},
+			"formatted": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The expression in canonical form, empty if it doesn't parse",
+			},
+			"valid": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the expression has no errors",
+			},
+			"errors": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Syntax and type errors of the expression",
+			},
+			"warnings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Likely mistakes in the expression, e.g. unknown functions or variables",
+			},
+		},
+	}
+}
+
+func dataSourceExpressionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	src := d.Get("expression").(string)
+	exprContext, _ := expression.ParseContext(d.Get("context").(string))
+	errs, warnings := []string{}, []string{}
+	for _, p := range expression.Check(src, exprContext) {
+		if p.Severity == expression.SeverityWarning {
+			warnings = append(warnings, p.String())
+		} else {
+			errs = append(errs, p.String())
+		}
+	}
+	formatted, _ := expression.Format(src)
+	d.SetId(fmt.Sprintf("%d", crc32.ChecksumIEEE([]byte(exprContext.String()+":"+src))))
+	_ = d.Set("formatted", formatted)
+	_ = d.Set("valid", len(errs) == 0)
+	_ = d.Set("errors", errs)
+	_ = d.Set("warnings", warnings)
+	return nil
+}
@@ -0,0 +1,61 @@
+package okta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccDataSourceOktaExpression_read(t *testing.T) {
+	mgr := newFixtureManager(expressionLint, t.Name())
+	config := mgr.GetFixtures("datasource.tf", t)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.okta_expression.valid", "formatted", `user.department == "Engineering" AND isMemberOfGroupName("Admins")`),
+					resource.TestCheckResourceAttr("data.okta_expression.valid", "valid", "true"),
+					resource.TestCheckResourceAttr("data.okta_expression.valid", "errors.#", "0"),
+					resource.TestCheckResourceAttr("data.okta_expression.valid", "warnings.#", "0"),
+					resource.TestCheckResourceAttr("data.okta_expression.invalid", "valid", "false"),
+					resource.TestCheckResourceAttr("data.okta_expression.invalid", "errors.#", "1"),
+					resource.TestCheckResourceAttr("data.okta_expression.invalid", "warnings.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceExpressionRead(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceExpression().Schema, map[string]interface{}{
+		"expression": `String.len(user.login, 1) == "5" AND appuser.login`,
+		"context":    "group_rule",
+	})
+	if diags := dataSourceExpressionRead(context.Background(), d, nil); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Id() == "" {
+		t.Error("id isn't set")
+	}
+	if got := d.Get("formatted").(string); got != `String.len(user.login, 1) == "5" AND appuser.login` {
+		t.Errorf("formatted = %q", got)
+	}
+	if d.Get("valid").(bool) {
+		t.Error("expression is valid")
+	}
+	errs := d.Get("errors").([]interface{})
+	if len(errs) != 1 || errs[0] != "String.len takes 1 argument, not 2 at column 8" {
+		t.Errorf("errors = %q", errs)
+	}
+	warnings := d.Get("warnings").([]interface{})
+	if len(warnings) != 2 || warnings[1] != `"appuser" isn't a variable of group_rule expressions at column 38` {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
@@ -142,7 +142,7 @@ func dataSourceUserRead(ctx context.Context, d *schema.ResourceData, m interface
 
 	if val := d.Get("skip_groups"); val != nil {
 		if skip, ok := val.(bool); ok && !skip {
-			err = setAllGroups(ctx, d, client)
+			err = setAllGroups(ctx, d, m)
 			if err != nil {
 				return diag.Errorf("failed to set user's groups: %v", err)
 			}
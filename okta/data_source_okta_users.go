@@ -106,24 +106,27 @@ func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diag.Errorf("failed to list users: %v", err)
 	}
 	d.SetId(id)
-	includeGroups := d.Get("include_groups").(bool)
-	includeRoles := d.Get("include_roles").(bool)
+	includeGroups := d.Get("include_groups").(bool) && !skipForbiddenRead(ctx, m, permGroups, "group_memberships")
+	includeRoles := d.Get("include_roles").(bool) && !skipForbiddenRead(ctx, m, permRoles, "admin_roles")
 	arr := make([]map[string]interface{}, len(users))
 	for i, user := range users {
 		rawMap := flattenUser(user, []string{})
 		rawMap["id"] = user.Id
 		if includeGroups {
-			groups, err := getGroupsForUser(ctx, user.Id, client)
-			if err != nil {
+			groups, resp, err := getGroupsForUser(ctx, user.Id, client)
+			if err := suppressErrorOnForbidden(ctx, "group_memberships", m, resp, err); err != nil {
 				return diag.Errorf("failed to list users: %v", err)
 			}
+			// a forbidden read is forbidden for every user
+			includeGroups = groups != nil
 			rawMap["group_memberships"] = groups
 		}
 		if includeRoles {
-			roles, _, err := getAdminRoles(ctx, user.Id, client)
-			if err != nil {
+			roles, resp, err := getAdminRoles(ctx, user.Id, client)
+			if err := suppressErrorOnForbidden(ctx, "admin_roles", m, resp, err); err != nil {
 				return diag.Errorf("failed to set user's admin roles: %v", err)
 			}
+			includeRoles = err == nil
 			rawMap["admin_roles"] = roles
 		}
 		arr[i] = rawMap
@@ -0,0 +1,828 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// OrgExport is the Terraform configuration of the objects of an org, see
+// ExportOrg.
+type OrgExport struct {
+	// Config is the HCL of the okta_* resources of the objects and the
+	// import blocks bringing them under management.
+	Config []byte
+	// Skipped are the objects, or kinds of objects, that couldn't be
+	// exported and why.
+	Skipped []string
+}
+
+// exportObject is an object of the org and the resource managing it.
+type exportObject struct {
+	resource string
+	// id is the ID of the object other resources refer to it by.
+	id string
+	// importID is the ID terraform imports the resource by, e.g.
+	// <auth_server_id>/<id> for nested objects.
+	importID string
+	name     string
+	address  string
+}
+
+// exportRecord is an exported object and its resource as read.
+type exportRecord struct {
+	object   *exportObject
+	resource *schema.Resource
+	data     *schema.ResourceData
+}
+
+// exportKinds are the kinds of objects ExportOrg walks, in the order their
+// resources are written.
+var exportKinds = []struct {
+	name string
+	list func(ctx context.Context, m interface{}) ([]*exportObject, error)
+}{
+	{"groups", exportGroups},
+	{"group_rules", exportGroupRules},
+	{"apps", exportApps},
+	{"policies", exportPolicies},
+	{"auth_servers", exportAuthServers},
+	{"idps", exportIdps},
+	{"schemas", exportSchemas},
+	{"hooks", exportHooks},
+	{"network_zones", exportNetworkZones},
+	{"branding", exportBranding},
+}
+
+// ExportKinds returns the kinds of objects ExportOrg can export.
+func ExportKinds() []string {
+	kinds := make([]string, len(exportKinds))
+	for i, kind := range exportKinds {
+		kinds[i] = kind.name
+	}
+	return kinds
+}
+
+// ExportOrg walks the org the provider is configured for and returns the
+// configuration of the okta_* resources of its objects, with import blocks,
+// see https://developer.hashicorp.com/terraform/language/import. Each
+// resource is imported and read the same as by terraform import and an
+// attribute referring to another exported object refers to its resource.
+// Kinds limits the export to kinds of objects, all of them if empty.
+func ExportOrg(ctx context.Context, p *schema.Provider, kinds []string) (*OrgExport, error) {
+	m := p.Meta()
+	if _, ok := m.(*Config); !ok {
+		return nil, fmt.Errorf("the provider isn't configured")
+	}
+	selected := map[string]bool{}
+	for _, kind := range kinds {
+		if !contains(ExportKinds(), kind) {
+			return nil, fmt.Errorf("unknown kind %q, expecting one of %s", kind, strings.Join(ExportKinds(), ", "))
+		}
+		selected[kind] = true
+	}
+
+	export := &OrgExport{}
+	var objects []*exportObject
+	for _, kind := range exportKinds {
+		if len(selected) > 0 && !selected[kind.name] {
+			continue
+		}
+		found, err := kind.list(ctx, m)
+		if err != nil {
+			export.Skipped = append(export.Skipped, fmt.Sprintf("%s: failed to list: %v", kind.name, err))
+			continue
+		}
+		objects = append(objects, found...)
+	}
+
+	// The objects are read before any is written, so that an object can
+	// refer to one written after it. Only the objects that are written are
+	// referred to, a reference to a skipped one would be left dangling.
+	var read []*exportRecord
+	for _, o := range objects {
+		r := p.ResourcesMap[o.resource]
+		d, err := exportRead(ctx, r, m, o.importID)
+		if err != nil {
+			export.Skipped = append(export.Skipped, fmt.Sprintf("%s %q: %v", o.resource, o.importID, err))
+			continue
+		}
+		if d == nil {
+			continue
+		}
+		read = append(read, &exportRecord{object: o, resource: r, data: d})
+	}
+
+	names := map[string]bool{}
+	refs := map[string]string{}
+	for _, record := range read {
+		o := record.object
+		name := exportResourceName(o.name)
+		for i := 2; names[o.resource+"."+name]; i++ {
+			name = fmt.Sprintf("%s_%d", exportResourceName(o.name), i)
+		}
+		names[o.resource+"."+name] = true
+		o.address = o.resource + "." + name
+		if oktaIDRegexp.MatchString(o.id) {
+			refs[o.id] = o.address
+		}
+	}
+
+	f := hclwrite.NewEmptyFile()
+	for _, record := range read {
+		o, r, d := record.object, record.resource, record.data
+		body := f.Body()
+		if len(body.Blocks()) > 0 {
+			body.AppendNewline()
+		}
+		imp := body.AppendNewBlock("import", nil).Body()
+		imp.SetAttributeTraversal("to", exportTraversal(o.address))
+		imp.SetAttributeValue("id", cty.StringVal(o.importID))
+		body.AppendNewline()
+		res := body.AppendNewBlock("resource", strings.SplitN(o.address, ".", 2)).Body()
+		values := map[string]interface{}{}
+		for k := range r.Schema {
+			values[k] = d.Get(k)
+		}
+		writeExportBody(res, r.Schema, values, refs, o.id)
+	}
+	export.Config = f.Bytes()
+	return export, nil
+}
+
+// oktaIDRegexp matches the IDs of Okta objects. Only those are referred to,
+// the ID of e.g. a schema property is its name and too likely a value.
+var oktaIDRegexp = regexp.MustCompile(`^[0-9A-Za-z]{20}$`)
+
+var exportNameRegexp = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// exportResourceName returns a terraform resource name of the object name.
+func exportResourceName(name string) string {
+	name = strings.Trim(exportNameRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_-")
+	if name == "" {
+		return "unnamed"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+func exportTraversal(address string, attrs ...string) hcl.Traversal {
+	parts := append(strings.Split(address, "."), attrs...)
+	traversal := hcl.Traversal{hcl.TraverseRoot{Name: parts[0]}}
+	for _, part := range parts[1:] {
+		traversal = append(traversal, hcl.TraverseAttr{Name: part})
+	}
+	return traversal
+}
+
+// exportRead imports and reads the resource the same as terraform import, it
+// returns nil if the object is gone.
+func exportRead(ctx context.Context, r *schema.Resource, m interface{}, importID string) (d *schema.ResourceData, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			d, err = nil, fmt.Errorf("failed to read: %v", recovered)
+		}
+	}()
+	if r == nil || r.Importer == nil {
+		return nil, fmt.Errorf("the resource can't be imported")
+	}
+	d = r.Data(nil)
+	d.SetId(importID)
+	imported := []*schema.ResourceData{d}
+	switch {
+	case r.Importer.StateContext != nil:
+		imported, err = r.Importer.StateContext(ctx, d, m)
+	case r.Importer.State != nil:
+		imported, err = r.Importer.State(d, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to import: %w", err)
+	}
+	if len(imported) == 0 {
+		return nil, nil
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), m)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed to read: %s", diags[0].Summary)
+	}
+	if state == nil || state.ID == "" {
+		return nil, nil
+	}
+	return r.Data(state), nil
+}
+
+// writeExportBody writes the attributes and blocks of the resource, or of a
+// nested block, that are configured: required, or optional and not their
+// default. Computed only, deprecated and sensitive attributes aren't
+// written, a sensitive one leaves a comment instead.
+func writeExportBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}, refs map[string]string, self string) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var blocks []string
+	for _, k := range keys {
+		v := exportValue(values[k])
+		if !isExported(s[k], v) {
+			continue
+		}
+		if _, ok := s[k].Elem.(*schema.Resource); ok {
+			blocks = append(blocks, k)
+			continue
+		}
+		if s[k].Sensitive {
+			body.AppendUnstructuredTokens(hclwrite.Tokens{{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte(fmt.Sprintf("# %s is sensitive and isn't exported\n", k)),
+			}})
+			continue
+		}
+		body.SetAttributeRaw(k, exportTokens(v, refs, self))
+	}
+	for _, k := range blocks {
+		elem := s[k].Elem.(*schema.Resource)
+		for _, element := range exportValue(values[k]).([]interface{}) {
+			nested, ok := element.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			block := hclwrite.NewBlock(k, nil)
+			writeExportBody(block.Body(), elem.Schema, nested, refs, self)
+			if len(block.Body().Attributes()) > 0 || len(block.Body().Blocks()) > 0 {
+				body.AppendBlock(block)
+			}
+		}
+	}
+}
+
+// exportValue returns the value with the elements of a set as a list.
+func exportValue(v interface{}) interface{} {
+	if set, ok := v.(*schema.Set); ok {
+		return set.List()
+	}
+	return v
+}
+
+func isExported(s *schema.Schema, v interface{}) bool {
+	if (s.Computed && !s.Optional && !s.Required) || s.Deprecated != "" || v == nil {
+		return false
+	}
+	if s.Required {
+		return true
+	}
+	// a blank string is unset, JSON attributes read as an empty object when
+	// unset
+	if value, ok := v.(string); ok && (value == "" || value == "{}") {
+		return false
+	}
+	if def, err := s.DefaultValue(); err == nil && def != nil {
+		return fmt.Sprint(exportValue(def)) != fmt.Sprint(v)
+	}
+	switch value := v.(type) {
+	case string:
+		return true
+	case bool:
+		return value
+	case int:
+		return value != 0
+	case float64:
+		return value != 0
+	case []interface{}:
+		return len(value) > 0
+	case map[string]interface{}:
+		return len(value) > 0
+	}
+	return true
+}
+
+// exportTokens returns the tokens of the value, a string that is the ID of
+// another exported object is a reference to its resource.
+func exportTokens(v interface{}, refs map[string]string, self string) hclwrite.Tokens {
+	switch value := v.(type) {
+	case string:
+		if address, ok := refs[value]; ok && value != self {
+			return hclwrite.TokensForTraversal(exportTraversal(address, "id"))
+		}
+		return hclwrite.TokensForValue(cty.StringVal(value))
+	case bool:
+		return hclwrite.TokensForValue(cty.BoolVal(value))
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(value)))
+	case float64:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(value))
+	case []interface{}:
+		elems := make([]hclwrite.Tokens, len(value))
+		for i, element := range value {
+			elems[i] = exportTokens(element, refs, self)
+		}
+		return hclwrite.TokensForTuple(elems)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]hclwrite.ObjectAttrTokens, len(keys))
+		for i, k := range keys {
+			attrs[i] = hclwrite.ObjectAttrTokens{
+				Name:  hclwrite.TokensForValue(cty.StringVal(k)),
+				Value: exportTokens(value[k], refs, self),
+			}
+		}
+		return hclwrite.TokensForObject(attrs)
+	}
+	return hclwrite.TokensForValue(cty.StringVal(fmt.Sprint(v)))
+}
+
+func exportGroups(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	groups, err := listGroups(ctx, getOktaClientFromMetadata(m), &query.Params{Filter: `type eq "OKTA_GROUP"`, Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, g := range groups {
+		objects = append(objects, &exportObject{resource: group, id: g.Id, importID: g.Id, name: g.Profile.Name})
+	}
+	return objects, nil
+}
+
+func exportGroupRules(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	rules, err := listGroupRules(ctx, getOktaClientFromMetadata(m), &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, r := range rules {
+		objects = append(objects, &exportObject{resource: groupRule, id: r.Id, importID: r.Id, name: r.Name})
+	}
+	return objects, nil
+}
+
+// exportInternalApps are the apps of the org itself, e.g. the admin console.
+var exportInternalApps = []string{"saasure", "okta_enduser", "okta_browser_plugin", "okta_flow_sso"}
+
+func exportApps(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	apps, err := listApps(ctx, getOktaClientFromMetadata(m), nil, defaultPaginationLimit)
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, app := range apps {
+		if contains(exportInternalApps, app.Name) {
+			continue
+		}
+		var resource string
+		switch app.SignOnMode {
+		case "OPENID_CONNECT":
+			resource = appOAuth
+		case "SAML_2_0", "SAML_1_1":
+			resource = appSaml
+		case "BOOKMARK":
+			resource = appBookmark
+		case "BASIC_AUTH":
+			resource = appBasicAuth
+		case "AUTO_LOGIN":
+			resource = appAutoLogin
+		case "SECURE_PASSWORD_STORE":
+			resource = appSecurePasswordStore
+		case "BROWSER_PLUGIN":
+			resource = appSwa
+			if app.Name == "template_swa3field" {
+				resource = appThreeField
+				break
+			}
+			// the shared credentials of a SWA app only tell by its scheme
+			plugin := sdk.NewBrowserPluginApplication()
+			if _, _, err := getOktaClientFromMetadata(m).Application.GetApplication(ctx, app.Id, plugin, nil); err != nil {
+				return nil, err
+			}
+			if plugin.Credentials != nil && plugin.Credentials.Scheme == "SHARED_USERNAME_AND_PASSWORD" {
+				resource = appSharedCredentials
+			}
+		default:
+			continue
+		}
+		objects = append(objects, &exportObject{resource: resource, id: app.Id, importID: app.Id, name: app.Label})
+	}
+	return objects, nil
+}
+
+// exportPolicyResources are the resources of the policies and of their rules
+// by policy type. The IdP discovery policy only has a default policy.
+var exportPolicyResources = []struct {
+	policyType string
+	policy     string
+	rule       string
+}{
+	{sdk.PasswordPolicyType, policyPassword, policyRulePassword},
+	{sdk.SignOnPolicyType, policySignOn, policyRuleSignOn},
+	{sdk.MfaPolicyType, policyMfa, policyRuleMfa},
+	{sdk.ProfileEnrollmentPolicyType, policyProfileEnrollment, policyRuleProfileEnrollment},
+	{sdk.AccessPolicyType, appSignOnPolicy, appSignOnPolicyRule},
+	{sdk.IdpDiscoveryType, "", policyRuleIdpDiscovery},
+}
+
+func exportPolicies(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	var objects []*exportObject
+	for _, resources := range exportPolicyResources {
+		policies, err := listPolicies(ctx, getOktaClientFromMetadata(m), &query.Params{Type: resources.policyType, Limit: defaultPaginationLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			resource := resources.policy
+			if policy.System != nil && *policy.System {
+				// only the default password and MFA policies have resources
+				switch resources.policyType {
+				case sdk.PasswordPolicyType:
+					resource = policyPasswordDefault
+				case sdk.MfaPolicyType:
+					resource = policyMfaDefault
+				default:
+					resource = ""
+				}
+			}
+			if resource != "" {
+				objects = append(objects, &exportObject{resource: resource, id: policy.Id, importID: policy.Id, name: policy.Name})
+			}
+			rules, err := listPolicyRules(ctx, getAPISupplementFromMetadata(m), policy.Id)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range rules {
+				if rule.System != nil && *rule.System {
+					continue
+				}
+				objects = append(objects, &exportObject{resource: resources.rule, id: rule.Id, importID: policy.Id + "/" + rule.Id, name: policy.Name + "_" + rule.Name})
+			}
+		}
+	}
+	return objects, nil
+}
+
+func exportAuthServers(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	client := getOktaClientFromMetadata(m)
+	servers, err := listAuthServers(ctx, client, &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, s := range servers {
+		resource := authServer
+		if s.Name == "default" {
+			resource = authServerDefault
+		}
+		objects = append(objects, &exportObject{resource: resource, id: s.Id, importID: s.Id, name: s.Name})
+
+		scopes, err := listAuthServerScopes(ctx, client, s.Id, &query.Params{Limit: defaultPaginationLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, scope := range scopes {
+			if scope.System != nil && *scope.System {
+				continue
+			}
+			objects = append(objects, &exportObject{resource: authServerScope, id: scope.Id, importID: s.Id + "/" + scope.Id, name: s.Name + "_" + scope.Name})
+		}
+		claims, err := listAuthServerClaims(ctx, client, s.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range claims {
+			if claim.System != nil && *claim.System {
+				continue
+			}
+			objects = append(objects, &exportObject{resource: authServerClaim, id: claim.Id, importID: s.Id + "/" + claim.Id, name: s.Name + "_" + claim.Name})
+		}
+		policies, err := listAuthServerPolicies(ctx, client, s.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			objects = append(objects, &exportObject{resource: authServerPolicy, id: policy.Id, importID: s.Id + "/" + policy.Id, name: s.Name + "_" + policy.Name})
+			rules, err := listAuthServerPolicyRules(ctx, client, s.Id, policy.Id)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range rules {
+				objects = append(objects, &exportObject{resource: authServerPolicyRule, id: rule.Id, importID: s.Id + "/" + policy.Id + "/" + rule.Id, name: s.Name + "_" + policy.Name + "_" + rule.Name})
+			}
+		}
+	}
+	return objects, nil
+}
+
+func exportIdps(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	providers, err := listIdps(ctx, getOktaClientFromMetadata(m), &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, idp := range providers {
+		resource := idpSocial
+		switch idp.Type {
+		case "OIDC":
+			resource = idpOidc
+		case saml2Idp:
+			resource = idpSaml
+		}
+		objects = append(objects, &exportObject{resource: resource, id: idp.Id, importID: idp.Id, name: idp.Name})
+	}
+	return objects, nil
+}
+
+// exportSchemas exports the custom properties of the default user type's
+// schema and of the group schema.
+func exportSchemas(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	client := getOktaClientFromMetadata(m)
+	var objects []*exportObject
+	userSchema, _, err := client.UserSchema.GetUserSchema(ctx, "default")
+	if err != nil {
+		return nil, err
+	}
+	if userSchema.Definitions != nil && userSchema.Definitions.Custom != nil {
+		for index := range userSchema.Definitions.Custom.Properties {
+			objects = append(objects, &exportObject{resource: userSchemaProperty, id: index, importID: index, name: "user_" + index})
+		}
+	}
+	groupSchema, _, err := client.GroupSchema.GetGroupSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if groupSchema.Definitions != nil && groupSchema.Definitions.Custom != nil {
+		for index := range groupSchema.Definitions.Custom.Properties {
+			objects = append(objects, &exportObject{resource: groupSchemaProperty, id: index, importID: index, name: "group_" + index})
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].name < objects[j].name
+	})
+	return objects, nil
+}
+
+func exportHooks(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	client := getOktaClientFromMetadata(m)
+	eventHooks, err := listEventHooks(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, hook := range eventHooks {
+		objects = append(objects, &exportObject{resource: eventHook, id: hook.Id, importID: hook.Id, name: hook.Name})
+	}
+	inlineHooks, err := listInlineHooks(ctx, client, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range inlineHooks {
+		objects = append(objects, &exportObject{resource: inlineHook, id: hook.Id, importID: hook.Id, name: hook.Name})
+	}
+	return objects, nil
+}
+
+func exportNetworkZones(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	zones, err := listNetworkZones(ctx, getOktaClientFromMetadata(m), &query.Params{Limit: defaultPaginationLimit})
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, zone := range zones {
+		if zone.System != nil && *zone.System {
+			continue
+		}
+		objects = append(objects, &exportObject{resource: networkZone, id: zone.Id, importID: zone.Id, name: zone.Name})
+	}
+	return objects, nil
+}
+
+func exportBranding(ctx context.Context, m interface{}) ([]*exportObject, error) {
+	client := getOktaV3ClientFromMetadata(m)
+	brands, _, err := client.CustomizationApi.ListBrands(ctx).Execute()
+	if err != nil {
+		return nil, err
+	}
+	var objects []*exportObject
+	for _, b := range brands {
+		objects = append(objects, &exportObject{resource: brand, id: b.GetId(), importID: b.GetId(), name: "brand_" + b.GetId()})
+		themes, _, err := client.CustomizationApi.ListBrandThemes(ctx, b.GetId()).Execute()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range themes {
+			objects = append(objects, &exportObject{resource: theme, id: t.GetId(), importID: b.GetId() + "/" + t.GetId(), name: "theme_" + t.GetId()})
+		}
+	}
+	return objects, nil
+}
+
+// The list functions below return the objects of all the pages.
+
+func listGroupRules(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.GroupRule, error) {
+	rules, resp, err := client.Group.ListGroupRules(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextRules []*sdk.GroupRule
+		resp, err = resp.Next(ctx, &nextRules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, nextRules...)
+	}
+	return rules, nil
+}
+
+func listPolicies(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.Policy, error) {
+	policies, resp, err := client.Policy.ListPolicies(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*sdk.Policy, len(policies))
+	for i := range policies {
+		result[i] = policies[i].(*sdk.Policy)
+	}
+	for resp.HasNextPage() {
+		var nextPolicies []*sdk.Policy
+		resp, err = resp.Next(ctx, &nextPolicies)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nextPolicies...)
+	}
+	return result, nil
+}
+
+func listAuthServers(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.AuthorizationServer, error) {
+	servers, resp, err := client.AuthorizationServer.ListAuthorizationServers(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextServers []*sdk.AuthorizationServer
+		resp, err = resp.Next(ctx, &nextServers)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, nextServers...)
+	}
+	return servers, nil
+}
+
+func listPolicyRules(ctx context.Context, client *sdk.APISupplement, policyID string) ([]sdk.SdkPolicyRule, error) {
+	rules, resp, err := client.ListPolicyRules(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextRules []sdk.SdkPolicyRule
+		resp, err = resp.Next(ctx, &nextRules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, nextRules...)
+	}
+	return rules, nil
+}
+
+func listAuthServerScopes(ctx context.Context, client *sdk.Client, authServerID string, qp *query.Params) ([]*sdk.OAuth2Scope, error) {
+	scopes, resp, err := client.AuthorizationServer.ListOAuth2Scopes(ctx, authServerID, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextScopes []*sdk.OAuth2Scope
+		resp, err = resp.Next(ctx, &nextScopes)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, nextScopes...)
+	}
+	return scopes, nil
+}
+
+func listAuthServerClaims(ctx context.Context, client *sdk.Client, authServerID string) ([]*sdk.OAuth2Claim, error) {
+	claims, resp, err := client.AuthorizationServer.ListOAuth2Claims(ctx, authServerID)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextClaims []*sdk.OAuth2Claim
+		resp, err = resp.Next(ctx, &nextClaims)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, nextClaims...)
+	}
+	return claims, nil
+}
+
+func listAuthServerPolicies(ctx context.Context, client *sdk.Client, authServerID string) ([]*sdk.AuthorizationServerPolicy, error) {
+	policies, resp, err := client.AuthorizationServer.ListAuthorizationServerPolicies(ctx, authServerID)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextPolicies []*sdk.AuthorizationServerPolicy
+		resp, err = resp.Next(ctx, &nextPolicies)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, nextPolicies...)
+	}
+	return policies, nil
+}
+
+func listAuthServerPolicyRules(ctx context.Context, client *sdk.Client, authServerID, policyID string) ([]*sdk.AuthorizationServerPolicyRule, error) {
+	rules, resp, err := client.AuthorizationServer.ListAuthorizationServerPolicyRules(ctx, authServerID, policyID)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextRules []*sdk.AuthorizationServerPolicyRule
+		resp, err = resp.Next(ctx, &nextRules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, nextRules...)
+	}
+	return rules, nil
+}
+
+func listIdps(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.IdentityProvider, error) {
+	providers, resp, err := client.IdentityProvider.ListIdentityProviders(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextProviders []*sdk.IdentityProvider
+		resp, err = resp.Next(ctx, &nextProviders)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, nextProviders...)
+	}
+	return providers, nil
+}
+
+func listEventHooks(ctx context.Context, client *sdk.Client) ([]*sdk.EventHook, error) {
+	hooks, resp, err := client.EventHook.ListEventHooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextHooks []*sdk.EventHook
+		resp, err = resp.Next(ctx, &nextHooks)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, nextHooks...)
+	}
+	return hooks, nil
+}
+
+func listInlineHooks(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.InlineHook, error) {
+	hooks, resp, err := client.InlineHook.ListInlineHooks(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextHooks []*sdk.InlineHook
+		resp, err = resp.Next(ctx, &nextHooks)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, nextHooks...)
+	}
+	return hooks, nil
+}
+
+func listNetworkZones(ctx context.Context, client *sdk.Client, qp *query.Params) ([]*sdk.NetworkZone, error) {
+	zones, resp, err := client.NetworkZone.ListNetworkZones(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+	for resp.HasNextPage() {
+		var nextZones []*sdk.NetworkZone
+		resp, err = resp.Next(ctx, &nextZones)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, nextZones...)
+	}
+	return zones, nil
+}
@@ -0,0 +1,165 @@
+package okta
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/emulator"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+func TestExportOrg(t *testing.T) {
+	server := emulator.NewServer()
+	defer server.Close()
+	ctx, client, err := sdk.NewClient(context.TODO(),
+		sdk.WithOrgUrl(server.URL),
+		sdk.WithToken("token"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create the client: %v", err)
+	}
+	admins, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "Admins", Description: "Org admins"}})
+	if err != nil {
+		t.Fatalf("failed to create the group: %v", err)
+	}
+	if _, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "admins!"}}); err != nil {
+		t.Fatalf("failed to create the group: %v", err)
+	}
+	_, _, err = client.Group.CreateGroupRule(ctx, sdk.GroupRule{
+		Name: "Engineering",
+		Type: "group_rule",
+		Conditions: &sdk.GroupRuleConditions{
+			Expression: &sdk.GroupRuleExpression{Type: "urn:okta:expression:1.0", Value: `user.department=="Engineering"`},
+		},
+		Actions: &sdk.GroupRuleAction{AssignUserToGroups: &sdk.GroupRuleGroupAssignment{GroupIds: []string{admins.Id}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create the group rule: %v", err)
+	}
+
+	p := Provider()
+	diags := p.Configure(context.TODO(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"org_name":   "emulator",
+		"base_url":   "example.com",
+		"api_token":  "token",
+		"http_proxy": server.URL,
+	}))
+	if diags.HasError() {
+		t.Fatalf("failed to configure the provider: %+v", diags)
+	}
+
+	if _, err := ExportOrg(context.TODO(), p, []string{"users"}); err == nil {
+		t.Errorf("expected an unknown kind to fail")
+	}
+	export, err := ExportOrg(context.TODO(), p, []string{"groups", "group_rules", "hooks"})
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	config := string(export.Config)
+	for _, expected := range []string{
+		"import {\n  to = okta_group.admins\n  id = \"" + admins.Id + "\"\n}",
+		"resource \"okta_group\" \"admins\" {\n  description = \"Org admins\"\n  name        = \"Admins\"\n}",
+		"resource \"okta_group\" \"admins_2\" {\n  name = \"admins!\"\n}",
+		"resource \"okta_group_rule\" \"engineering\" {",
+		"group_assignments = [okta_group.admins.id]",
+		"expression_value  = \"user.department==\\\"Engineering\\\"\"",
+	} {
+		if !strings.Contains(config, expected) {
+			t.Errorf("expected the export to contain %q, got\n%s", expected, config)
+		}
+	}
+	if strings.Contains(config, "Everyone") {
+		t.Errorf("expected the built-in Everyone group not to be exported, got\n%s", config)
+	}
+	if len(export.Skipped) != 1 || !strings.HasPrefix(export.Skipped[0], "hooks: failed to list") {
+		t.Errorf("expected the hooks the emulator doesn't have to be skipped, got %v", export.Skipped)
+	}
+
+	// the groups aren't exported, the rule keeps their IDs
+	export, err = ExportOrg(context.TODO(), p, []string{"group_rules"})
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	config = string(export.Config)
+	if expected := "group_assignments = [\"" + admins.Id + "\"]"; !strings.Contains(config, expected) {
+		t.Errorf("expected the export to contain %q, got\n%s", expected, config)
+	}
+	if strings.Contains(config, "okta_group.admins") {
+		t.Errorf("expected no reference to a group that isn't exported, got\n%s", config)
+	}
+}
+
+func TestExportListPages(t *testing.T) {
+	server := emulator.NewServer()
+	defer server.Close()
+	ctx, client, err := sdk.NewClient(context.TODO(),
+		sdk.WithOrgUrl(server.URL),
+		sdk.WithToken("token"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create the client: %v", err)
+	}
+	for _, name := range []string{"one", "two", "three"} {
+		_, _, err := client.Group.CreateGroupRule(ctx, sdk.GroupRule{
+			Name: name,
+			Type: "group_rule",
+			Conditions: &sdk.GroupRuleConditions{
+				Expression: &sdk.GroupRuleExpression{Type: "urn:okta:expression:1.0", Value: `user.department=="` + name + `"`},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create the group rule: %v", err)
+		}
+	}
+	rules, err := listGroupRules(ctx, client, &query.Params{Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to list the group rules: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Errorf("expected the 3 group rules of all the pages, got %d", len(rules))
+	}
+	all, err := listPolicies(ctx, client, &query.Params{Type: sdk.PasswordPolicyType})
+	if err != nil {
+		t.Fatalf("failed to list the policies: %v", err)
+	}
+	paged, err := listPolicies(ctx, client, &query.Params{Type: sdk.PasswordPolicyType, Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to list the policies: %v", err)
+	}
+	if len(all) == 0 || len(paged) != len(all) {
+		t.Errorf("expected the %d policies of all the pages, got %d", len(all), len(paged))
+	}
+	for _, name := range []string{"one", "two", "three"} {
+		if _, _, err := client.AuthorizationServer.CreateOAuth2Scope(ctx, "default", sdk.OAuth2Scope{Name: name}); err != nil {
+			t.Fatalf("failed to create the scope: %v", err)
+		}
+	}
+	scopes, err := listAuthServerScopes(ctx, client, "default", &query.Params{Limit: 1})
+	if err != nil {
+		t.Fatalf("failed to list the scopes: %v", err)
+	}
+	if len(scopes) != 3 {
+		t.Errorf("expected the 3 scopes of all the pages, got %d", len(scopes))
+	}
+}
+
+func TestExportResourceName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Admins":          "admins",
+		"Sales & Support": "sales_support",
+		"1Password":       "_1password",
+		"--":              "unnamed",
+		"app-one_two":     "app-one_two",
+	} {
+		if got := exportResourceName(name); got != expected {
+			t.Errorf("expected resource name %q of %q, got %q", expected, name, got)
+		}
+	}
+}
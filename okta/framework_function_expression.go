@@ -0,0 +1,133 @@
+package okta
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+)
+
+// formatExpressionFunction is provider::okta::format_expression, which
+// formats an Okta Expression Language expression without calling Okta, the
+// same as the formatted attribute of the okta_expression data source.
+type formatExpressionFunction struct{}
+
+var _ function.Function = formatExpressionFunction{}
+
+func newFormatExpressionFunction() function.Function {
+	return formatExpressionFunction{}
+}
+
+func (f formatExpressionFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "format_expression"
+}
+
+func (f formatExpressionFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Formats an Okta Expression Language expression",
+		Description: "Returns the expression in canonical form, with a space around operators and after commas, and upper case AND, OR and NOT. It fails if the expression doesn't parse.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "expression",
+				Description: "Okta Expression Language expression to format",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f formatExpressionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var src string
+	resp.Error = req.Arguments.Get(ctx, &src)
+	if resp.Error != nil {
+		return
+	}
+	formatted, err := expression.Format(src)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "failed to parse the expression: "+err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, formatted)
+}
+
+// lintExpressionFunction is provider::okta::lint_expression, which type
+// checks an Okta Expression Language expression without calling Okta, the
+// same as the valid, errors and warnings attributes of the okta_expression
+// data source.
+type lintExpressionFunction struct{}
+
+var _ function.Function = lintExpressionFunction{}
+
+func newLintExpressionFunction() function.Function {
+	return lintExpressionFunction{}
+}
+
+// lintExpressionResultTypes are the attributes of the result of
+// lint_expression.
+var lintExpressionResultTypes = map[string]attr.Type{
+	"valid":    types.BoolType,
+	"errors":   types.ListType{ElemType: types.StringType},
+	"warnings": types.ListType{ElemType: types.StringType},
+}
+
+func (f lintExpressionFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "lint_expression"
+}
+
+func (f lintExpressionFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Lints an Okta Expression Language expression",
+		Description: "Returns an object of whether the expression has no errors, its syntax and type errors, and its likely mistakes, e.g. unknown functions or variables the context doesn't have.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "expression",
+				Description: "Okta Expression Language expression to lint",
+			},
+			function.StringParameter{
+				Name:        "context",
+				Description: "Where the expression is evaluated, which decides the variables it can use: " + strings.Join(expression.ContextNames(), ", "),
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: lintExpressionResultTypes,
+		},
+	}
+}
+
+func (f lintExpressionFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var src, contextName string
+	resp.Error = req.Arguments.Get(ctx, &src, &contextName)
+	if resp.Error != nil {
+		return
+	}
+	exprContext, ok := expression.ParseContext(contextName)
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(1, "the context must be one of "+strings.Join(expression.ContextNames(), ", "))
+		return
+	}
+	errs, warnings := []string{}, []string{}
+	for _, p := range expression.Check(src, exprContext) {
+		if p.Severity == expression.SeverityWarning {
+			warnings = append(warnings, p.String())
+		} else {
+			errs = append(errs, p.String())
+		}
+	}
+	errList, diags := types.ListValueFrom(ctx, types.StringType, errs)
+	warningList, warningDiags := types.ListValueFrom(ctx, types.StringType, warnings)
+	diags.Append(warningDiags...)
+	result, resultDiags := types.ObjectValue(lintExpressionResultTypes, map[string]attr.Value{
+		"valid":    types.BoolValue(len(errs) == 0),
+		"errors":   errList,
+		"warnings": warningList,
+	})
+	diags.Append(resultDiags...)
+	if diags.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diags)
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
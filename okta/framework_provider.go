@@ -0,0 +1,211 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// unknownConfigValue is the plugin SDK's value of an unknown attribute of a
+// terraform.ResourceConfig.
+const unknownConfigValue = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+// frameworkProvider is the plugin framework provider, serving the resources
+// and data sources ported from the plugin SDK. It shares the Config of the
+// plugin SDK provider, see sharedConfig, and declares the same provider
+// schema, which is derived from the plugin SDK provider's so that they can't
+// drift apart.
+type frameworkProvider struct {
+	shared *sharedConfig
+}
+
+var (
+	_ provider.Provider              = &frameworkProvider{}
+	_ provider.ProviderWithFunctions = &frameworkProvider{}
+)
+
+func newFrameworkProvider(shared *sharedConfig) provider.Provider {
+	return &frameworkProvider{shared: shared}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "okta"
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema, err := schema.NewGRPCProviderServer(newProvider(p.shared)).GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to get the provider schema", err.Error())
+		return
+	}
+	s := fwschema.Schema{Attributes: map[string]fwschema.Attribute{}}
+	for _, a := range sdkSchema.Provider.Block.Attributes {
+		attr, err := frameworkAttribute(a)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to get the provider schema", err.Error())
+			return
+		}
+		s.Attributes[a.Name] = attr
+	}
+	resp.Schema = s
+}
+
+// frameworkAttribute returns the plugin framework attribute of a plugin SDK
+// provider attribute, a string, number or bool or a list or set of strings.
+func frameworkAttribute(a *tfprotov5.SchemaAttribute) (fwschema.Attribute, error) {
+	var description, markdownDescription, deprecation string
+	if a.DescriptionKind == tfprotov5.StringKindMarkdown {
+		markdownDescription = a.Description
+	} else {
+		description = a.Description
+	}
+	if a.Deprecated {
+		deprecation = "deprecated"
+	}
+	switch {
+	case a.Type.Is(tftypes.String):
+		return fwschema.StringAttribute{
+			Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive,
+			Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecation,
+		}, nil
+	case a.Type.Is(tftypes.Number):
+		return fwschema.Int64Attribute{
+			Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive,
+			Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecation,
+		}, nil
+	case a.Type.Is(tftypes.Bool):
+		return fwschema.BoolAttribute{
+			Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive,
+			Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecation,
+		}, nil
+	case a.Type.Is(tftypes.List{ElementType: tftypes.String}):
+		return fwschema.ListAttribute{
+			ElementType: types.StringType,
+			Required:    a.Required, Optional: a.Optional, Sensitive: a.Sensitive,
+			Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecation,
+		}, nil
+	case a.Type.Is(tftypes.Set{ElementType: tftypes.String}):
+		return fwschema.SetAttribute{
+			ElementType: types.StringType,
+			Required:    a.Required, Optional: a.Optional, Sensitive: a.Sensitive,
+			Description: description, MarkdownDescription: markdownDescription, DeprecationMessage: deprecation,
+		}, nil
+	}
+	return nil, fmt.Errorf("attribute %s has the unsupported type %s", a.Name, a.Type)
+}
+
+// Configure configures the plugin SDK provider with the same configuration,
+// which loads the shared Config, or gets it if it's already loaded.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	raw, err := frameworkConfigValue(req.Config.Raw)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read the provider configuration", err.Error())
+		return
+	}
+	config, _ := raw.(map[string]interface{})
+	sdkProvider := newProvider(p.shared)
+	diags := sdkProvider.Configure(ctx, terraform.NewResourceConfigRaw(config))
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			resp.Diagnostics.AddError(d.Summary, d.Detail)
+		} else {
+			resp.Diagnostics.AddWarning(d.Summary, d.Detail)
+		}
+	}
+	if diags.HasError() {
+		return
+	}
+	resp.DataSourceData = sdkProvider.Meta()
+	resp.ResourceData = sdkProvider.Meta()
+}
+
+// frameworkConfigValue returns the value of the configuration, or of an
+// attribute of it, the same as the plugin SDK reads it. Null attributes are
+// left out and unknown ones are unknownConfigValue.
+func frameworkConfigValue(v tftypes.Value) (interface{}, error) {
+	if !v.IsKnown() {
+		return unknownConfigValue, nil
+	}
+	if v.IsNull() {
+		return nil, nil
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		err := v.As(&s)
+		return s, err
+	case v.Type().Is(tftypes.Number):
+		var n big.Float
+		if err := v.As(&n); err != nil {
+			return nil, err
+		}
+		i, _ := n.Int64()
+		return int(i), nil
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		err := v.As(&b)
+		return b, err
+	case v.Type().Is(tftypes.List{}), v.Type().Is(tftypes.Set{}):
+		var elements []tftypes.Value
+		if err := v.As(&elements); err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, 0, len(elements))
+		for _, element := range elements {
+			value, err := frameworkConfigValue(element)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case v.Type().Is(tftypes.Object{}):
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil {
+			return nil, err
+		}
+		object := map[string]interface{}{}
+		for name, attr := range attrs {
+			value, err := frameworkConfigValue(attr)
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				object[name] = value
+			}
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// DataSources are the data sources ported to the plugin framework.
+func (p *frameworkProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// Resources are the resources ported to the plugin framework.
+func (p *frameworkProvider) Resources(context.Context) []func() resource.Resource {
+	return nil
+}
+
+// Functions are the provider-defined functions, which need the plugin
+// framework.
+func (p *frameworkProvider) Functions(context.Context) []func() function.Function {
+	return []func() function.Function{
+		newFormatExpressionFunction,
+		newLintExpressionFunction,
+	}
+}
@@ -20,6 +20,7 @@ type APIMutex struct {
 	capacity int
 	status   map[string]*APIStatus
 	buckets  map[string]string
+	store    Store
 }
 
 // APIStatus is used to hold rate limit information from Okta's API, see:
@@ -49,6 +50,7 @@ func NewAPIMutex(capacity int) (*APIMutex, error) {
 // HasCapacity approximates if there is capacity below the api mutex's maximum
 // capacity threshold.
 func (m *APIMutex) HasCapacity(method, endPoint string) bool {
+	m.syncStore(m.bucketKey(method, endPoint), false)
 	status := m.get(method, endPoint)
 
 	// if the status hasn't been updated recently assume there is capacity
@@ -66,28 +68,54 @@ func (m *APIMutex) HasCapacity(method, endPoint string) bool {
 // and intelligently accounts for new values regardless of parallelism.
 func (m *APIMutex) Update(method, endPoint string, limit, remaining int, reset int64) {
 	m.lock.Lock()
-	defer m.lock.Unlock()
-
 	status := m.get(method, endPoint)
-	if reset > status.reset {
-		// reset value greater than current reset implies we are in a new Okta API
-		// one minute window. set/reset values.
-		status.reset = reset
-		status.remaining = remaining
-		status.limit = limit
+	status.merge(limit, remaining, reset)
+	m.lock.Unlock()
+
+	m.syncStore(m.bucketKey(method, endPoint), true)
+}
+
+// SetStore sets the store the api mutex shares its status with other provider
+// processes through and loads the status the store currently holds.
+func (m *APIMutex) SetStore(store Store) {
+	m.store = store
+	m.syncStore("", false)
+}
+
+// syncStore exchanges status with the store, if there is one. When push is
+// true the bucket's status is written to the store. The store is best effort,
+// failing to read or write it leaves the api mutex with its own accounting.
+func (m *APIMutex) syncStore(bucket string, push bool) {
+	if m.store == nil {
 		return
 	}
-
-	if reset <= (status.reset - 60) {
-		// these values are from the previous one minute window, ignore
+	var update map[string]APIStatus
+	if push {
+		m.lock.Lock()
+		if status, ok := m.status[bucket]; ok {
+			update = map[string]APIStatus{bucket: *status}
+		}
+		m.lock.Unlock()
+	}
+	statuses, err := m.store.Sync(update)
+	if err != nil {
 		return
 	}
 
-	if remaining < status.remaining {
-		status.remaining = remaining
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for key, stored := range statuses {
+		if status, ok := m.status[key]; ok {
+			status.merge(stored.limit, stored.remaining, stored.reset)
+		}
 	}
 }
 
+// Capacity Returns the capacity percentage of the api mutex.
+func (m *APIMutex) Capacity() int {
+	return m.capacity
+}
+
 // Status Returns the APIStatus for the given method + endpoint combination.
 func (m *APIMutex) Status(method, endPoint string) *APIStatus {
 	return m.get(method, endPoint)
@@ -95,25 +123,39 @@ func (m *APIMutex) Status(method, endPoint string) *APIStatus {
 
 // Class Returns the api endpoint class.
 func (m *APIMutex) Class(method, endPoint string) string {
-	path := reOktaID.ReplaceAllString(endPoint, "ID")
-	return m.normalizedKey(method, path)
+	return m.normalizedKey(method, classPath(endPoint))
 }
 
 // Bucket Returns the rate limit bucket the api endpoint falls into.
 func (m *APIMutex) Bucket(method, endPoint string) string {
-	path := reOktaID.ReplaceAllString(endPoint, "ID")
-	key := m.normalizedKey(method, path)
-	bucket, ok := m.buckets[key]
-	if !ok {
-		return "/"
-	}
-	return bucket
+	return m.bucketKey(method, endPoint)
 }
 
 func (m *APIMutex) normalizedKey(method, endPoint string) string {
 	return fmt.Sprintf("%s %s", method, endPoint)
 }
 
+// merge accounts for new values of the status.
+func (s *APIStatus) merge(limit, remaining int, reset int64) {
+	if reset > s.reset {
+		// reset value greater than current reset implies we are in a new Okta API
+		// one minute window. set/reset values.
+		s.reset = reset
+		s.remaining = remaining
+		s.limit = limit
+		return
+	}
+
+	if reset <= (s.reset - 60) {
+		// these values are from the previous one minute window, ignore
+		return
+	}
+
+	if remaining < s.remaining {
+		s.remaining = remaining
+	}
+}
+
 // Reset returns the current reset value of the api status object.
 func (s *APIStatus) Reset() int64 {
 	return s.reset
@@ -132,10 +174,26 @@ func (s *APIStatus) Remaining() int {
 var reOktaID = regexp.MustCompile(`[\w]{20}`)
 
 func (m *APIMutex) get(method, endPoint string) *APIStatus {
+	return m.status[m.bucketKey(method, endPoint)]
+}
+
+// bucketKey returns the key of the status the method and endpoint are
+// accounted under.
+func (m *APIMutex) bucketKey(method, endPoint string) string {
+	key := m.normalizedKey(method, classPath(endPoint))
+	bucket, ok := m.buckets[key]
+	if !ok {
+		return "/"
+	}
+	return bucket
+}
+
+// classPath returns the endpoint with its Okta IDs replaced by "ID".
+func classPath(endPoint string) string {
 	// The important point here is the replace all is performing this
 	// transformation for the bucket lookup /api/v1/users/abcdefghij0123456789
 	// to /api/v1/users/ID .
-	path := reOktaID.ReplaceAllStringFunc(endPoint, func(element string) string {
+	return reOktaID.ReplaceAllStringFunc(endPoint, func(element string) string {
 		// Any path elements, like "authorizationServers", which are 20
 		// characters long should be handled here.
 		switch element {
@@ -145,12 +203,6 @@ func (m *APIMutex) get(method, endPoint string) *APIStatus {
 			return "ID"
 		}
 	})
-	key := m.normalizedKey(method, path)
-	bucket, ok := m.buckets[key]
-	if !ok {
-		return m.status["/"]
-	}
-	return m.status[bucket]
 }
 
 func (m *APIMutex) initRateLimitLookup() {
@@ -102,6 +102,9 @@ func TestGetRegex(t *testing.T) {
 		if result != amu.status[test.expectedBucket] {
 			t.Fatalf("expected endpoint \"%s %s\" to be in status bucket %q", test.method, test.endPoint, test.expectedBucket)
 		}
+		if bucket := amu.Bucket(test.method, test.endPoint); bucket != test.expectedBucket {
+			t.Fatalf("expected endpoint \"%s %s\" to be in bucket %q, got %q", test.method, test.endPoint, test.expectedBucket, bucket)
+		}
 	}
 }
 
@@ -0,0 +1,55 @@
+package apimutex
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BucketMapping maps the method and path of an endpoint to its rate limit
+// bucket. Path parameters can be written as in Okta's documentation, e.g.
+// /api/v1/users/{userId}, or as ID.
+type BucketMapping struct {
+	Method string `yaml:"method" json:"method"`
+	Path   string `yaml:"path" json:"path"`
+	Bucket string `yaml:"bucket" json:"bucket"`
+}
+
+var rePathParam = regexp.MustCompile(`{[^}]+}`)
+
+// LoadBucketMappings reads bucket mappings from a YAML, or JSON, file that is
+// a list of method, path, and bucket objects.
+func LoadBucketMappings(path string) ([]BucketMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mappings []BucketMapping
+	if err := yaml.Unmarshal(data, &mappings); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit bucket mappings %q: %w", path, err)
+	}
+	for i, mapping := range mappings {
+		if mapping.Method == "" || mapping.Path == "" || mapping.Bucket == "" {
+			return nil, fmt.Errorf("rate limit bucket mapping %d in %q requires method, path, and bucket", i, path)
+		}
+	}
+	return mappings, nil
+}
+
+// AddBuckets adds the bucket mappings to the api mutex, overriding the
+// generated mappings for the same method and path.
+func (m *APIMutex) AddBuckets(mappings []BucketMapping) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, mapping := range mappings {
+		path := rePathParam.ReplaceAllString(mapping.Path, "ID")
+		key := m.normalizedKey(strings.ToUpper(mapping.Method), path)
+		m.buckets[key] = mapping.Bucket
+		if _, ok := m.status[mapping.Bucket]; !ok {
+			m.status[mapping.Bucket] = &APIStatus{}
+		}
+	}
+}
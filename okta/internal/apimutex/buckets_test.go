@@ -0,0 +1,57 @@
+package apimutex
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddBucketsFromFile(t *testing.T) {
+	amu, err := NewAPIMutex(50)
+	if err != nil {
+		t.Fatalf("api mutex constructor had error %+v", err)
+	}
+	endPoint := "/api/v1/brandNewThings/abcdefghij0123456789"
+	if bucket := amu.Bucket(http.MethodGet, endPoint); bucket != "/" {
+		t.Fatalf("expected unknown endpoint to be in the catch-all bucket, got %q", bucket)
+	}
+
+	path := filepath.Join(t.TempDir(), "buckets.yaml")
+	content := `
+- method: get
+  path: /api/v1/brandNewThings/{thingId}
+  bucket: /api/v1/brandNewThings
+- method: GET
+  path: /api/v1/users
+  bucket: /api/v1/brandNewThings
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mappings, err := LoadBucketMappings(path)
+	if err != nil {
+		t.Fatalf("loading bucket mappings had error %+v", err)
+	}
+	amu.AddBuckets(mappings)
+
+	if bucket := amu.Bucket(http.MethodGet, endPoint); bucket != "/api/v1/brandNewThings" {
+		t.Fatalf("expected endpoint to be in its mapped bucket, got %q", bucket)
+	}
+	// overridden generated mapping shares the status of the new bucket
+	amu.Update(http.MethodGet, endPoint, 100, 10, time.Now().Unix()+60)
+	if remaining := amu.Status(http.MethodGet, "/api/v1/users").Remaining(); remaining != 10 {
+		t.Fatalf("expected overridden endpoint to share the mapped bucket's status, got %d remaining", remaining)
+	}
+}
+
+func TestLoadBucketMappingsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buckets.json")
+	if err := os.WriteFile(path, []byte(`[{"method": "GET", "path": "/api/v1/things"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBucketMappings(path); err == nil {
+		t.Fatalf("expected error for mapping without a bucket")
+	}
+}
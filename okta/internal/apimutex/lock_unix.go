@@ -0,0 +1,23 @@
+//go:build !windows
+
+package apimutex
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile places an advisory lock on the file, exclusive for writers and
+// shared for readers, and returns the function that releases it.
+func lockFile(f *os.File, exclusive bool) (func(), error) {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(f.Fd()), how); err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
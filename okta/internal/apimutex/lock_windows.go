@@ -0,0 +1,41 @@
+//go:build windows
+
+package apimutex
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// lockFile serializes access to the file with a sidecar lock file as there is
+// no flock on windows. A lock file older than lockStale is considered to have
+// been left behind by a crashed process and is removed.
+func lockFile(f *os.File, _ bool) (func(), error) {
+	const (
+		lockStale   = 10 * time.Second
+		lockTimeout = 30 * time.Second
+	)
+	name := f.Name() + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		lock, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			_ = lock.Close()
+			return func() {
+				_ = os.Remove(name)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(name); err == nil && time.Since(info.ModTime()) > lockStale {
+			_ = os.Remove(name)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for api mutex state file lock")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
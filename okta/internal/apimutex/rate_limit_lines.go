@@ -17,9 +17,13 @@
 package apimutex
 
 // Generated code. DO NOT EDIT.
-// To run this generator one must have access to the Okta monolith codebase.
 // cd scripts/generate_rate_limits
-// go run main --mapping /path/to/monolith/components/tests/api/webapp/src/test/resources/ratelimits/FILE.txt
+// go run main.go --format FORMAT --mappings /path/to/FILE [--merge]
+// Formats are monolith, the ratelimits test resources of the Okta monolith;
+// csv and yaml, method, path, and bucket rows from Okta's public rate limit
+// documentation; and openapi, Okta's OpenAPI spec with buckets inferred from
+// the existing lines, which is always merged into them. Buckets can also be overridden at runtime with the
+// provider's max_api_capacity_buckets_file.
 
 // PATH METHOD BUCKET
 var rateLimitLines = []string{
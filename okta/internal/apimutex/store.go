@@ -0,0 +1,111 @@
+package apimutex
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// Store shares api status between concurrent provider processes on the same
+// machine so that each process knows about the rate limit consumption of the
+// others.
+type Store interface {
+	// Sync merges the given statuses, keyed by rate limit bucket, into the
+	// store and returns all of the statuses the store holds.
+	Sync(statuses map[string]APIStatus) (map[string]APIStatus, error)
+}
+
+// FileStore is a Store backed by a JSON state file. Access to the file is
+// serialized between processes with a file lock. Statuses whose one minute
+// window has passed are expired from the file.
+type FileStore struct {
+	path string
+	now  func() time.Time
+}
+
+type storedStatus struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"`
+}
+
+// NewFileStore returns a file store for the state file at the given path, the
+// file is created if it doesn't exist.
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("api mutex state file path is required")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return &FileStore{path: path, now: time.Now}, nil
+}
+
+// Sync merges the statuses into the state file and returns what the state file
+// holds. The state file is only rewritten if there are statuses to merge.
+func (s *FileStore) Sync(statuses map[string]APIStatus) (map[string]APIStatus, error) {
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	unlock, err := lockFile(f, len(statuses) > 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	stored := map[string]storedStatus{}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		// a corrupt state file is started over
+		if err := json.Unmarshal(data, &stored); err != nil {
+			stored = map[string]storedStatus{}
+		}
+	}
+
+	result := map[string]APIStatus{}
+	now := s.now().Unix()
+	for bucket, ss := range stored {
+		if ss.Reset < now {
+			// the status' one minute window has passed
+			continue
+		}
+		result[bucket] = APIStatus{limit: ss.Limit, remaining: ss.Remaining, reset: ss.Reset}
+	}
+	if len(statuses) == 0 {
+		return result, nil
+	}
+
+	for bucket, status := range statuses {
+		current := result[bucket]
+		current.merge(status.limit, status.remaining, status.reset)
+		result[bucket] = current
+	}
+
+	stored = map[string]storedStatus{}
+	for bucket, status := range result {
+		stored[bucket] = storedStatus{Limit: status.limit, Remaining: status.remaining, Reset: status.reset}
+	}
+	data, err = json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(0); err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteAt(data, 0); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package apimutex
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStoreSharesStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_mutex.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("file store constructor had error %+v", err)
+	}
+
+	// two api mutexes standing in for two provider processes
+	first, _ := NewAPIMutex(50)
+	first.SetStore(store)
+	second, _ := NewAPIMutex(50)
+	second.SetStore(store)
+
+	endPoint := "/api/v1/users"
+	reset := time.Now().Unix() + 60
+	first.Update(http.MethodGet, endPoint, 90, 44, reset)
+	if first.HasCapacity(http.MethodGet, endPoint) {
+		t.Fatalf("first api mutex shouldn't have capacity, 50%% threshold, 90 limit, 44 remaining")
+	}
+	if second.HasCapacity(http.MethodGet, endPoint) {
+		t.Fatalf("second api mutex shouldn't have capacity, it should have read the first's status from the store")
+	}
+	status := second.Status(http.MethodGet, endPoint)
+	if status.Remaining() != 44 || status.Limit() != 90 || status.Reset() != reset {
+		t.Fatalf("expected second api mutex status to be 44 remaining of 90 reset at %d, got %+v", reset, status)
+	}
+
+	// a third process starting up knows about the current window
+	third, _ := NewAPIMutex(50)
+	third.SetStore(store)
+	if third.Status(http.MethodGet, endPoint).Remaining() != 44 {
+		t.Fatalf("expected third api mutex to load the status from the store")
+	}
+}
+
+func TestFileStoreExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_mutex.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("file store constructor had error %+v", err)
+	}
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	reset := now.Unix() + 30
+	if _, err := store.Sync(map[string]APIStatus{"/api/v1/users": {limit: 600, remaining: 10, reset: reset}}); err != nil {
+		t.Fatalf("sync had error %+v", err)
+	}
+	statuses, _ := store.Sync(nil)
+	if _, ok := statuses["/api/v1/users"]; !ok {
+		t.Fatalf("expected status to be held by the store before its reset")
+	}
+
+	store.now = func() time.Time { return now.Add(31 * time.Second) }
+	statuses, _ = store.Sync(nil)
+	if _, ok := statuses["/api/v1/users"]; ok {
+		t.Fatalf("expected status to be expired from the store after its reset")
+	}
+}
@@ -0,0 +1,218 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// collection is a list endpoint of the emulated API. Items of a collection
+// live under its path, e.g. the users collection /api/v1/users has items at
+// /api/v1/users/{id}.
+type collection struct {
+	// pattern is the path of the collection, {id} matches any segment.
+	pattern string
+	// kind names the object in not found errors.
+	kind string
+	// prefix starts the IDs the emulator gives created items.
+	prefix string
+	// status is the status of created items, none if empty.
+	status string
+	// link is the pattern of the collection of the linked items for a
+	// collection of assignments, whose items are keyed by the ID of the item
+	// they link and may be created with a PUT.
+	link string
+	// resolve lists the linked items themselves rather than the assignments.
+	resolve bool
+}
+
+// collections are the emulated endpoints. Patterns with more literal segments
+// come first, so that /api/v1/groups/rules isn't taken for a group.
+var collections = []collection{
+	{pattern: "/api/v1/groups/rules", kind: "GroupRule", prefix: "0pr", status: "INACTIVE"},
+	{pattern: "/api/v1/users", kind: "User", prefix: "00u", status: "ACTIVE"},
+	{pattern: "/api/v1/users/{id}/roles", kind: "Role", prefix: "ra1", status: "ACTIVE"},
+	{pattern: "/api/v1/groups", kind: "Group", prefix: "00g"},
+	{pattern: "/api/v1/groups/{id}/users", kind: "User", link: "/api/v1/users", resolve: true},
+	{pattern: "/api/v1/groups/{id}/roles", kind: "Role", prefix: "ra1", status: "ACTIVE"},
+	{pattern: "/api/v1/apps", kind: "AppInstance", prefix: "0oa", status: "ACTIVE"},
+	{pattern: "/api/v1/apps/{id}/users", kind: "AppUser", link: "/api/v1/users"},
+	{pattern: "/api/v1/apps/{id}/groups", kind: "ApplicationGroupAssignment", link: "/api/v1/groups"},
+	{pattern: "/api/v1/apps/{id}/grants", kind: "OAuth2ScopeConsentGrant", prefix: "oag", status: "ACTIVE"},
+	{pattern: "/api/v1/policies", kind: "Policy", prefix: "00p", status: "ACTIVE"},
+	{pattern: "/api/v1/policies/{id}/rules", kind: "PolicyRule", prefix: "0pr", status: "ACTIVE"},
+	{pattern: "/api/v1/authorizationServers", kind: "AuthorizationServer", prefix: "aus", status: "ACTIVE"},
+	{pattern: "/api/v1/authorizationServers/{id}/scopes", kind: "OAuth2Scope", prefix: "scp"},
+	{pattern: "/api/v1/authorizationServers/{id}/claims", kind: "OAuth2Claim", prefix: "ocl", status: "ACTIVE"},
+	{pattern: "/api/v1/authorizationServers/{id}/policies", kind: "AuthorizationServerPolicy", prefix: "00p", status: "ACTIVE"},
+	{pattern: "/api/v1/authorizationServers/{id}/policies/{id}/rules", kind: "AuthorizationServerPolicyRule", prefix: "0pr", status: "ACTIVE"},
+}
+
+// userGroups lists the groups a user is a member of, which the emulator works
+// out from the group memberships rather than keeping.
+var userGroups = collection{pattern: "/api/v1/users/{id}/groups", kind: "Group"}
+
+// AdminUserID is the ID of the seeded super admin, the caller of the API.
+const AdminUserID = "00u0emulatoradmin000"
+
+// EveryoneGroupID is the ID of the seeded Everyone group.
+const EveryoneGroupID = "00g0emulatoreveryone"
+
+// seed adds the objects every Okta org starts with.
+func (s *Server) seed() {
+	s.put("/api/v1/users/"+AdminUserID, object{
+		"id":     AdminUserID,
+		"status": "ACTIVE",
+		"type":   object{"id": "oty0emulatordefault0"},
+		"profile": object{
+			"login":     "admin@example.com",
+			"email":     "admin@example.com",
+			"firstName": "Emulator",
+			"lastName":  "Admin",
+		},
+	})
+	s.put("/api/v1/users/"+AdminUserID+"/roles/ra10emulatorsuper00", object{
+		"id":             "ra10emulatorsuper00",
+		"type":           "SUPER_ADMIN",
+		"label":          "Super Organization Administrator",
+		"status":         "ACTIVE",
+		"assignmentType": "USER",
+	})
+	s.put("/api/v1/groups/"+EveryoneGroupID, object{
+		"id":          EveryoneGroupID,
+		"type":        "BUILT_IN",
+		"objectClass": []interface{}{"okta:user_group"},
+		"profile":     object{"name": "Everyone", "description": "All users in your organization"},
+	})
+	for i, policyType := range []string{"OKTA_SIGN_ON", "PASSWORD", "MFA_ENROLL", "ACCESS_POLICY", "PROFILE_ENROLLMENT", "IDP_DISCOVERY"} {
+		id := "00p0emulatordefault" + string(rune('0'+i))
+		s.put("/api/v1/policies/"+id, object{
+			"id":       id,
+			"type":     policyType,
+			"name":     "Default Policy",
+			"status":   "ACTIVE",
+			"system":   true,
+			"priority": 1,
+		})
+	}
+	s.put("/api/v1/authorizationServers/default", object{
+		"id":          "default",
+		"name":        "default",
+		"description": "Default Authorization Server",
+		"audiences":   []interface{}{"api://default"},
+		"status":      "ACTIVE",
+	})
+}
+
+// created sets what Okta sets on an object it creates in the collection.
+func (s *Server) created(coll *collection, obj object, activate bool) {
+	id, _ := obj["id"].(string)
+	switch coll.pattern {
+	case "/api/v1/users":
+		if !activate {
+			obj["status"] = "STAGED"
+		}
+		if credentials, ok := obj["credentials"].(map[string]interface{}); ok {
+			delete(credentials, "password")
+		}
+		groupIDs, _ := obj["groupIds"].([]interface{})
+		for _, groupID := range groupIDs {
+			s.put(fmt.Sprintf("/api/v1/groups/%v/users/%s", groupID, id), object{"id": id})
+		}
+		delete(obj, "groupIds")
+	case "/api/v1/groups":
+		setDefault(obj, "type", "OKTA_GROUP")
+		setDefault(obj, "objectClass", []interface{}{"okta:user_group"})
+	case "/api/v1/apps":
+		if !activate {
+			obj["status"] = "INACTIVE"
+		}
+		if obj["signOnMode"] == "OPENID_CONNECT" {
+			credentials := ensureObject(obj, "credentials")
+			oauthClient := ensureObject(credentials, "oauthClient")
+			setDefault(oauthClient, "client_id", id)
+			if method, _ := oauthClient["token_endpoint_auth_method"].(string); method == "" || method == "client_secret_basic" || method == "client_secret_post" {
+				setDefault(oauthClient, "client_secret", "secret-"+id)
+			}
+		}
+	case "/api/v1/policies", "/api/v1/policies/{id}/rules":
+		if !activate {
+			obj["status"] = "INACTIVE"
+		}
+		setDefault(obj, "system", false)
+	case "/api/v1/authorizationServers":
+		obj["issuer"] = s.URL + "/oauth2/" + id
+		setDefault(obj, "issuerMode", "ORG_URL")
+		setDefault(obj, "credentials", object{"signing": object{"kid": "kid-" + id, "rotationMode": "AUTO"}})
+	case "/api/v1/apps/{id}/grants":
+		obj["source"] = "ADMIN"
+		obj["createdBy"] = object{"id": AdminUserID, "type": "User"}
+	case "/api/v1/users/{id}/roles", "/api/v1/groups/{id}/roles":
+		assignmentType := "USER"
+		if coll.pattern == "/api/v1/groups/{id}/roles" {
+			assignmentType = "GROUP"
+		}
+		obj["assignmentType"] = assignmentType
+	}
+}
+
+// lifecycleStatus is the status an object of the collection has after the
+// lifecycle operation.
+func lifecycleStatus(coll *collection, operation string) (string, bool) {
+	switch operation {
+	case "activate", "unsuspend", "unlock", "reactivate":
+		return "ACTIVE", true
+	case "deactivate":
+		if coll.pattern == "/api/v1/users" {
+			return "DEPROVISIONED", true
+		}
+		return "INACTIVE", true
+	case "suspend":
+		return "SUSPENDED", true
+	}
+	return "", false
+}
+
+// createDenied returns why Okta refuses to create the object in the
+// collection, if it does. A scope is granted to an app once per issuer.
+func (s *Server) createDenied(coll *collection, collPath string, obj object) (status int, errorCode, summary string) {
+	if coll.pattern == "/api/v1/apps/{id}/grants" {
+		for _, e := range s.children(collPath) {
+			if e.value["scopeId"] == obj["scopeId"] && e.value["issuer"] == obj["issuer"] {
+				return http.StatusBadRequest, "E0000001", "Api validation failed: scopeId"
+			}
+		}
+	}
+	return 0, "", ""
+}
+
+// deleteDenied returns why Okta refuses to delete the object, if it does.
+// Users are deactivated by their first delete, apps have to be deactivated
+// before they are deleted.
+func deleteDenied(coll *collection, obj object) (status int, errorCode, summary string, deactivate bool) {
+	switch coll.pattern {
+	case "/api/v1/users":
+		if obj["status"] != "DEPROVISIONED" {
+			return 0, "", "", true
+		}
+	case "/api/v1/apps":
+		if obj["status"] == "ACTIVE" {
+			return 403, "E0000056", "Delete application forbidden.", false
+		}
+	}
+	return 0, "", "", false
+}
+
+func setDefault(obj object, key string, value interface{}) {
+	if _, ok := obj[key]; !ok {
+		obj[key] = value
+	}
+}
+
+func ensureObject(obj object, key string) object {
+	if value, ok := obj[key].(map[string]interface{}); ok {
+		return value
+	}
+	value := object{}
+	obj[key] = value
+	return value
+}
@@ -0,0 +1,515 @@
+// Package emulator is a stateful, in memory fake of the Okta management API
+// for tests that can't reach an org. It covers users, groups, apps, group
+// rules, policies and authorization servers, with their assignments and
+// sub-resources, see collections. The provider reaches it through its
+// http_proxy argument, which also turns off the https check.
+package emulator
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type object = map[string]interface{}
+
+type entry struct {
+	seq   int
+	value object
+}
+
+// Server is an Okta API emulator on a local httptest server.
+type Server struct {
+	*httptest.Server
+	lock    sync.Mutex
+	seq     int
+	entries map[string]*entry
+}
+
+// NewServer starts an emulator of an org with a super admin, the Everyone
+// group, the default policies and the default authorization server.
+func NewServer() *Server {
+	s := &Server{
+		entries: map[string]*entry{},
+	}
+	s.Server = httptest.NewServer(s)
+	s.seed()
+	return s
+}
+
+// Get returns a copy of the object at the API path, for the assertions of a
+// test.
+func (s *Server) Get(path string) (map[string]interface{}, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e, ok := s.entries[path]
+	if !ok {
+		return nil, false
+	}
+	return copyObject(e.value), true
+}
+
+// ServeHTTP serves the emulated API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// the rate limit never runs out
+	w.Header().Set("X-Rate-Limit-Limit", "10000")
+	w.Header().Set("X-Rate-Limit-Remaining", "10000")
+	w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+
+	switch {
+	case r.URL.Path == "/.well-known/okta-organization":
+		writeJSON(w, http.StatusOK, object{"id": "00o0emulator00000000", "pipeline": "idx", "_links": object{"organization": object{"href": s.URL}}})
+		return
+	case strings.HasSuffix(r.URL.Path, "/v1/token") && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusOK, object{"token_type": "Bearer", "expires_in": 3600, "access_token": randomID(""), "scope": r.URL.Query().Get("scope")})
+		return
+	case r.Header.Get("Authorization") == "":
+		writeError(w, http.StatusUnauthorized, "E0000011", "Invalid token provided")
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == "/api/v1/users/me" || strings.HasPrefix(path, "/api/v1/users/me/") {
+		path = "/api/v1/users/" + AdminUserID + strings.TrimPrefix(path, "/api/v1/users/me")
+	}
+	coll, collPath, id, operation := match(path)
+	switch {
+	case coll == nil:
+		writeError(w, http.StatusNotFound, "E0000022", "The endpoint does not support the provided HTTP method")
+	case coll.pattern == "/api/v1/users" && id != "":
+		s.serveItem(w, r, coll, collPath, s.userID(id), operation)
+	case id != "":
+		s.serveItem(w, r, coll, collPath, id, operation)
+	case r.Method == http.MethodGet:
+		s.list(w, r, coll, collPath)
+	case r.Method == http.MethodPost:
+		s.create(w, r, coll, collPath)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "E0000022", "The endpoint does not support the provided HTTP method")
+	}
+}
+
+func (s *Server) serveItem(w http.ResponseWriter, r *http.Request, coll *collection, collPath, id, operation string) {
+	if !s.parentsExist(collPath) {
+		writeError(w, http.StatusNotFound, "E0000007", "Not found: Resource not found: "+collPath)
+		return
+	}
+	path := collPath + "/" + id
+	e, exists := s.entries[path]
+	if operation != "" {
+		status, ok := lifecycleStatus(coll, operation)
+		switch {
+		case r.Method != http.MethodPost || !ok:
+			writeError(w, http.StatusNotFound, "E0000022", "The endpoint does not support the provided HTTP method")
+		case !exists:
+			s.notFound(w, coll, id)
+		default:
+			e.value["status"] = status
+			e.value["lastUpdated"] = now()
+			writeJSON(w, http.StatusOK, object{})
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			s.notFound(w, coll, id)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.render(coll, e.value))
+	case http.MethodPut, http.MethodPost:
+		body, err := readObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.")
+			return
+		}
+		if !exists {
+			if coll.link == "" || r.Method != http.MethodPut {
+				s.notFound(w, coll, id)
+				return
+			}
+			s.link(w, coll, collPath, id, body)
+			return
+		}
+		if r.Method == http.MethodPost {
+			merge(e.value, body)
+		} else {
+			for _, key := range []string{"id", "created", "status", "system", "issuer"} {
+				if value, ok := e.value[key]; ok {
+					body[key] = value
+				}
+			}
+			e.value = body
+		}
+		e.value["lastUpdated"] = now()
+		writeJSON(w, http.StatusOK, s.render(coll, e.value))
+	case http.MethodDelete:
+		if !exists {
+			s.notFound(w, coll, id)
+			return
+		}
+		status, errorCode, summary, deactivate := deleteDenied(coll, e.value)
+		switch {
+		case status != 0:
+			writeError(w, status, errorCode, summary)
+		case deactivate:
+			newStatus, _ := lifecycleStatus(coll, "deactivate")
+			e.value["status"] = newStatus
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			s.delete(coll, path, id)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "E0000022", "The endpoint does not support the provided HTTP method")
+	}
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, coll *collection, collPath string) {
+	if !s.parentsExist(collPath) {
+		writeError(w, http.StatusNotFound, "E0000007", "Not found: Resource not found: "+collPath)
+		return
+	}
+	items := []object{}
+	if coll == &userGroups {
+		items = s.groupsOfUser(collPath)
+	} else {
+		for _, e := range s.children(collPath) {
+			items = append(items, s.render(coll, e.value))
+		}
+	}
+
+	query := r.URL.Query()
+	items = filterItems(items, query)
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if after := query.Get("after"); after != "" {
+		for i, item := range items {
+			if item["id"] == after {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+		next := url.Values{}
+		for key, values := range query {
+			next[key] = values
+		}
+		next.Set("after", fmt.Sprint(items[limit-1]["id"]))
+		w.Header().Add("Link", fmt.Sprintf(`<%s%s?%s>; rel="next"`, s.URL, collPath, next.Encode()))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, coll *collection, collPath string) {
+	if !s.parentsExist(collPath) {
+		writeError(w, http.StatusNotFound, "E0000007", "Not found: Resource not found: "+collPath)
+		return
+	}
+	body, err := readObject(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "E0000003", "The request body was not well-formed.")
+		return
+	}
+	if coll.link != "" {
+		id, _ := body["id"].(string)
+		s.link(w, coll, collPath, id, body)
+		return
+	}
+	if status, errorCode, summary := s.createDenied(coll, collPath, body); status != 0 {
+		writeError(w, status, errorCode, summary)
+		return
+	}
+	id := randomID(coll.prefix)
+	body["id"] = id
+	body["created"] = now()
+	body["lastUpdated"] = body["created"]
+	if coll.status != "" {
+		body["status"] = coll.status
+	}
+	s.created(coll, body, r.URL.Query().Get("activate") != "false")
+	s.put(collPath+"/"+id, body)
+	writeJSON(w, http.StatusOK, s.render(coll, body))
+}
+
+// link assigns the item of the linked collection to the collection.
+func (s *Server) link(w http.ResponseWriter, coll *collection, collPath, id string, body object) {
+	if _, ok := s.entries[coll.link+"/"+id]; !ok {
+		s.notFound(w, coll, id)
+		return
+	}
+	body["id"] = id
+	setDefault(body, "created", now())
+	body["lastUpdated"] = now()
+	s.put(collPath+"/"+id, body)
+	if coll.resolve {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// delete removes the object with its sub-resources and its assignments.
+func (s *Server) delete(coll *collection, path, id string) {
+	for key := range s.entries {
+		if key == path || strings.HasPrefix(key, path+"/") {
+			delete(s.entries, key)
+		}
+	}
+	collPattern := coll.pattern
+	for key := range s.entries {
+		linkColl, _, linkID, _ := match(key)
+		if linkColl != nil && linkColl.link == collPattern && linkID == id {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (s *Server) put(path string, value object) {
+	if e, ok := s.entries[path]; ok {
+		e.value = value
+		return
+	}
+	s.seq++
+	setDefault(value, "created", now())
+	setDefault(value, "lastUpdated", value["created"])
+	s.entries[path] = &entry{seq: s.seq, value: value}
+}
+
+// children returns the items directly under the collection path in the
+// order they were created.
+func (s *Server) children(collPath string) []*entry {
+	var children []*entry
+	for key, e := range s.entries {
+		if rest := strings.TrimPrefix(key, collPath+"/"); rest != key && !strings.Contains(rest, "/") {
+			children = append(children, e)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].seq < children[j].seq })
+	return children
+}
+
+// render returns the object as the API returns it, the linked objects of a
+// resolving collection.
+func (s *Server) render(coll *collection, value object) object {
+	if coll.resolve {
+		if e, ok := s.entries[coll.link+"/"+fmt.Sprint(value["id"])]; ok {
+			return copyObject(e.value)
+		}
+	}
+	return copyObject(value)
+}
+
+// groupsOfUser returns the groups of the user of the
+// /api/v1/users/{id}/groups path.
+func (s *Server) groupsOfUser(collPath string) []object {
+	userID := strings.Split(strings.TrimPrefix(collPath, "/api/v1/users/"), "/")[0]
+	groups := []object{}
+	for _, e := range s.children("/api/v1/groups") {
+		id := fmt.Sprint(e.value["id"])
+		if _, ok := s.entries["/api/v1/groups/"+id+"/users/"+userID]; ok || id == EveryoneGroupID {
+			groups = append(groups, copyObject(e.value))
+		}
+	}
+	return groups
+}
+
+// userID resolves a user's login to the user's ID, Okta accepts either.
+func (s *Server) userID(id string) string {
+	if !strings.Contains(id, "@") {
+		return id
+	}
+	for _, e := range s.children("/api/v1/users") {
+		if profile, ok := e.value["profile"].(map[string]interface{}); ok && profile["login"] == id {
+			return fmt.Sprint(e.value["id"])
+		}
+	}
+	return id
+}
+
+// parentsExist reports if the objects a collection path is nested under
+// exist.
+func (s *Server) parentsExist(collPath string) bool {
+	segments := strings.Split(strings.TrimPrefix(collPath, "/"), "/")
+	for i := len(segments) - 1; i > 3; i-- {
+		parent := "/" + strings.Join(segments[:i], "/")
+		if coll, _, id, _ := match(parent); coll != nil && id != "" {
+			if _, ok := s.entries[parent]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *Server) notFound(w http.ResponseWriter, coll *collection, id string) {
+	writeError(w, http.StatusNotFound, "E0000007", fmt.Sprintf("Not found: Resource not found: %s (%s)", id, coll.kind))
+}
+
+// match returns the collection of the path, the path of the collection, the
+// ID of the item and the lifecycle operation on the item, if the path is of
+// an item.
+func match(path string) (coll *collection, collPath, id, operation string) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) == 5 && matchSegments(strings.Split(strings.TrimPrefix(userGroups.pattern, "/"), "/"), segments) {
+		return &userGroups, path, "", ""
+	}
+	for i := range collections {
+		pattern := strings.Split(strings.TrimPrefix(collections[i].pattern, "/"), "/")
+		if len(segments) < len(pattern) || !matchSegments(pattern, segments[:len(pattern)]) {
+			continue
+		}
+		rest := segments[len(pattern):]
+		collPath = "/" + strings.Join(segments[:len(pattern)], "/")
+		switch {
+		case len(rest) == 0:
+			return &collections[i], collPath, "", ""
+		case len(rest) == 1:
+			return &collections[i], collPath, rest[0], ""
+		case len(rest) == 3 && rest[1] == "lifecycle":
+			return &collections[i], collPath, rest[0], rest[2]
+		}
+	}
+	return nil, "", "", ""
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for i, segment := range pattern {
+		if segment != "{id}" && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// filterItems applies the q, type and filter or search query parameters. The
+// filter and search expressions are attribute eq "value" clauses joined with
+// and.
+func filterItems(items []object, query url.Values) []object {
+	var clauses [][2]string
+	for _, param := range []string{"filter", "search"} {
+		expression := query.Get(param)
+		if expression == "" {
+			continue
+		}
+		for _, clause := range strings.Split(expression, " and ") {
+			parts := strings.SplitN(strings.TrimSpace(clause), " eq ", 2)
+			if len(parts) == 2 {
+				clauses = append(clauses, [2]string{parts[0], strings.Trim(parts[1], `"`)})
+			}
+		}
+	}
+	if policyType := query.Get("type"); policyType != "" {
+		clauses = append(clauses, [2]string{"type", policyType})
+	}
+	q := strings.ToLower(query.Get("q"))
+
+	filtered := []object{}
+	for _, item := range items {
+		keep := true
+		for _, clause := range clauses {
+			if fmt.Sprint(attribute(item, clause[0])) != clause[1] {
+				keep = false
+			}
+		}
+		if keep && q != "" {
+			keep = false
+			for _, attr := range []string{"profile.name", "profile.login", "profile.firstName", "profile.lastName", "profile.email", "label", "name"} {
+				if value, ok := attribute(item, attr).(string); ok && strings.HasPrefix(strings.ToLower(value), q) {
+					keep = true
+				}
+			}
+		}
+		if keep {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// attribute returns the value at the dotted attribute path of the object.
+func attribute(obj object, path string) interface{} {
+	var value interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+// merge deep merges the partial object into the object, as Okta's partial
+// updates do.
+func merge(obj, partial object) {
+	for key, value := range partial {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if existing, ok := obj[key].(map[string]interface{}); ok {
+				merge(existing, nested)
+				continue
+			}
+		}
+		obj[key] = value
+	}
+}
+
+func copyObject(obj object) object {
+	data, _ := json.Marshal(obj)
+	var c object
+	_ = json.Unmarshal(data, &c)
+	return c
+}
+
+func readObject(r *http.Request) (object, error) {
+	body := object{}
+	if r.Body == nil || r.ContentLength == 0 {
+		return body, nil
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && err.Error() == "EOF" {
+		err = nil
+	}
+	return body, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, errorCode, summary string) {
+	writeJSON(w, status, object{
+		"errorCode":    errorCode,
+		"errorSummary": summary,
+		"errorLink":    errorCode,
+		"errorId":      randomID("oae"),
+		"errorCauses":  []interface{}{},
+	})
+}
+
+// randomID returns a 20 character Okta style ID with the prefix.
+func randomID(prefix string) string {
+	b := make([]byte, 10)
+	_, _ = rand.Read(b)
+	return (prefix + hex.EncodeToString(b))[:20]
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
@@ -0,0 +1,238 @@
+package emulator
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+func newTestClient(t *testing.T) (*Server, context.Context, *sdk.Client) {
+	s := NewServer()
+	t.Cleanup(s.Close)
+	ctx, client, err := sdk.NewClient(context.TODO(),
+		sdk.WithOrgUrl(s.URL),
+		sdk.WithToken("token"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create the client: %v", err)
+	}
+	return s, ctx, client
+}
+
+func TestUsersAndGroups(t *testing.T) {
+	s, ctx, client := newTestClient(t)
+
+	group, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: "testAcc"}})
+	if err != nil {
+		t.Fatalf("failed to create the group: %v", err)
+	}
+	if group.Id == "" || group.Type != "OKTA_GROUP" {
+		t.Errorf("expected a created OKTA_GROUP, got %+v", group)
+	}
+	user, _, err := client.User.CreateUser(ctx, sdk.CreateUserRequest{
+		Profile:  &sdk.UserProfile{"login": "testAcc@example.com", "email": "testAcc@example.com"},
+		GroupIds: []string{group.Id},
+	}, query.NewQueryParams(query.WithActivate(false)))
+	if err != nil {
+		t.Fatalf("failed to create the user: %v", err)
+	}
+	if user.Status != "STAGED" {
+		t.Errorf("expected a user created without activation to be STAGED, got %s", user.Status)
+	}
+	got, _, err := client.User.GetUser(ctx, "testAcc@example.com")
+	if err != nil || got.Id != user.Id {
+		t.Errorf("expected the user by login, got %+v, %v", got, err)
+	}
+	me, _, err := client.User.GetUser(ctx, "me")
+	if err != nil || me.Id != AdminUserID {
+		t.Errorf("expected the admin as me, got %+v, %v", me, err)
+	}
+
+	members, _, err := client.Group.ListGroupUsers(ctx, group.Id, nil)
+	if err != nil || len(members) != 1 || members[0].Id != user.Id {
+		t.Errorf("expected the user as the group's member, got %+v, %v", members, err)
+	}
+	groups, _, err := client.User.ListUserGroups(ctx, user.Id)
+	if err != nil || len(groups) != 2 {
+		t.Errorf("expected the user in Everyone and the group, got %+v, %v", groups, err)
+	}
+	groups, _, err = client.Group.ListGroups(ctx, query.NewQueryParams(query.WithQ("test")))
+	if err != nil || len(groups) != 1 || groups[0].Id != group.Id {
+		t.Errorf("expected the group by q, got %+v, %v", groups, err)
+	}
+
+	// a user is deprovisioned by the first delete and gone after the second
+	if _, err := client.User.DeactivateOrDeleteUser(ctx, user.Id, nil); err != nil {
+		t.Fatalf("failed to delete the user: %v", err)
+	}
+	if obj, _ := s.Get("/api/v1/users/" + user.Id); obj["status"] != "DEPROVISIONED" {
+		t.Errorf("expected the user to be deprovisioned, got %v", obj["status"])
+	}
+	if _, err := client.User.DeactivateOrDeleteUser(ctx, user.Id, nil); err != nil {
+		t.Fatalf("failed to delete the user: %v", err)
+	}
+	_, resp, err := client.User.GetUser(ctx, user.Id)
+	if err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected the deleted user not to be found, got %v", err)
+	}
+	if _, ok := s.Get("/api/v1/groups/" + group.Id + "/users/" + user.Id); ok {
+		t.Error("expected the deleted user's membership to be removed")
+	}
+}
+
+func TestGroupRules(t *testing.T) {
+	_, ctx, client := newTestClient(t)
+
+	rule, _, err := client.Group.CreateGroupRule(ctx, sdk.GroupRule{Name: "testAcc", Type: "group_rule"})
+	if err != nil {
+		t.Fatalf("failed to create the group rule: %v", err)
+	}
+	if rule.Status != "INACTIVE" {
+		t.Errorf("expected a created rule to be INACTIVE, got %s", rule.Status)
+	}
+	if _, err := client.Group.ActivateGroupRule(ctx, rule.Id); err != nil {
+		t.Fatalf("failed to activate the group rule: %v", err)
+	}
+	rule, _, err = client.Group.GetGroupRule(ctx, rule.Id, nil)
+	if err != nil || rule.Status != "ACTIVE" {
+		t.Errorf("expected the rule to be ACTIVE, got %+v, %v", rule, err)
+	}
+	groups, _, err := client.Group.ListGroups(ctx, nil)
+	if err != nil || len(groups) != 1 {
+		t.Errorf("expected the rule not to be listed as a group, got %+v, %v", groups, err)
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	_, ctx, client := newTestClient(t)
+
+	policies, _, err := client.Policy.ListPolicies(ctx, query.NewQueryParams(query.WithType("PASSWORD")))
+	if err != nil || len(policies) != 1 {
+		t.Fatalf("expected the default password policy, got %+v, %v", policies, err)
+	}
+	created, _, err := client.Policy.CreatePolicy(ctx, &sdk.Policy{Name: "testAcc", Type: "PASSWORD"}, nil)
+	if err != nil {
+		t.Fatalf("failed to create the policy: %v", err)
+	}
+	policy := created.(*sdk.Policy)
+	if _, _, err := client.Policy.CreatePolicyRule(ctx, policy.Id, sdk.SdkPolicyRule{Name: "testAcc", Type: "PASSWORD"}); err != nil {
+		t.Fatalf("failed to create the policy rule: %v", err)
+	}
+	rules, _, err := client.Policy.ListPolicyRules(ctx, policy.Id)
+	if err != nil || len(rules) != 1 || rules[0].Status != "ACTIVE" {
+		t.Errorf("expected an active rule, got %+v, %v", rules, err)
+	}
+	_, resp, err := client.Policy.ListPolicyRules(ctx, "00pmissing")
+	if err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected the rules of a missing policy not to be found, got %v", err)
+	}
+}
+
+func TestAuthorizationServers(t *testing.T) {
+	_, ctx, client := newTestClient(t)
+
+	server, _, err := client.AuthorizationServer.CreateAuthorizationServer(ctx, sdk.AuthorizationServer{Name: "testAcc", Audiences: []string{"api://testAcc"}})
+	if err != nil {
+		t.Fatalf("failed to create the authorization server: %v", err)
+	}
+	if server.Issuer == "" || server.Credentials == nil {
+		t.Errorf("expected an issuer and signing credentials, got %+v", server)
+	}
+	if _, _, err := client.AuthorizationServer.CreateOAuth2Scope(ctx, server.Id, sdk.OAuth2Scope{Name: "testAcc"}); err != nil {
+		t.Fatalf("failed to create the scope: %v", err)
+	}
+	scopes, _, err := client.AuthorizationServer.ListOAuth2Scopes(ctx, server.Id, nil)
+	if err != nil || len(scopes) != 1 || scopes[0].Name != "testAcc" {
+		t.Errorf("expected the scope, got %+v, %v", scopes, err)
+	}
+	scopes, _, err = client.AuthorizationServer.ListOAuth2Scopes(ctx, "default", nil)
+	if err != nil || len(scopes) != 0 {
+		t.Errorf("expected the scope only on its server, got %+v, %v", scopes, err)
+	}
+}
+
+func TestApps(t *testing.T) {
+	s, ctx, client := newTestClient(t)
+
+	created, _, err := client.Application.CreateApplication(ctx, sdk.NewOpenIdConnectApplication(), nil)
+	if err != nil {
+		t.Fatalf("failed to create the app: %v", err)
+	}
+	app := created.(*sdk.OpenIdConnectApplication)
+	if app.Credentials == nil || app.Credentials.OauthClient.ClientId != app.Id || app.Credentials.OauthClient.ClientSecret == "" {
+		t.Errorf("expected generated client credentials, got %+v", app.Credentials)
+	}
+	if _, _, err := client.Application.CreateApplicationGroupAssignment(ctx, app.Id, EveryoneGroupID, sdk.ApplicationGroupAssignment{}); err != nil {
+		t.Fatalf("failed to assign the group: %v", err)
+	}
+	assignments, _, err := client.Application.ListApplicationGroupAssignments(ctx, app.Id, nil)
+	if err != nil || len(assignments) != 1 || assignments[0].Id != EveryoneGroupID {
+		t.Errorf("expected the group assignment, got %+v, %v", assignments, err)
+	}
+	_, _, err = client.Application.CreateApplicationGroupAssignment(ctx, app.Id, "00gmissing", sdk.ApplicationGroupAssignment{})
+	if err == nil {
+		t.Error("expected the assignment of a missing group to fail")
+	}
+
+	// active apps can't be deleted
+	resp, err := client.Application.DeleteApplication(ctx, app.Id)
+	if err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the delete of an active app to be forbidden, got %v", err)
+	}
+	if _, err := client.Application.DeactivateApplication(ctx, app.Id); err != nil {
+		t.Fatalf("failed to deactivate the app: %v", err)
+	}
+	if _, err := client.Application.DeleteApplication(ctx, app.Id); err != nil {
+		t.Fatalf("failed to delete the app: %v", err)
+	}
+	if _, ok := s.Get("/api/v1/apps/" + app.Id + "/groups/" + EveryoneGroupID); ok {
+		t.Error("expected the deleted app's assignments to be removed")
+	}
+}
+
+func TestAppGrants(t *testing.T) {
+	_, ctx, client := newTestClient(t)
+
+	created, _, err := client.Application.CreateApplication(ctx, sdk.NewOpenIdConnectApplication(), nil)
+	if err != nil {
+		t.Fatalf("failed to create the app: %v", err)
+	}
+	appID := created.(*sdk.OpenIdConnectApplication).Id
+	grant := sdk.OAuth2ScopeConsentGrant{Issuer: "https://emulator.example.com", ScopeId: "okta.users.read"}
+	granted, _, err := client.Application.GrantConsentToScope(ctx, appID, grant)
+	if err != nil {
+		t.Fatalf("failed to grant the scope: %v", err)
+	}
+	if granted.Status != "ACTIVE" || granted.CreatedBy == nil || granted.CreatedBy.Id != AdminUserID {
+		t.Errorf("expected an active grant created by the admin, got %+v", granted)
+	}
+	if _, resp, err := client.Application.GrantConsentToScope(ctx, appID, grant); err == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected granting the scope again to fail, got %v", err)
+	}
+	grant.Issuer = "https://emulator.example.com/oauth2/default"
+	if _, _, err := client.Application.GrantConsentToScope(ctx, appID, grant); err != nil {
+		t.Errorf("failed to grant the scope of another issuer: %v", err)
+	}
+	grants, _, err := client.Application.ListScopeConsentGrants(ctx, appID, nil)
+	if err != nil || len(grants) != 2 {
+		t.Errorf("expected the two grants, got %+v, %v", grants, err)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	resp, err := http.Get(s.URL + "/api/v1/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a request without a token to be unauthorized, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,343 @@
+package expression
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity is how bad a problem of an expression is.
+type Severity int
+
+const (
+	// SeverityError is a problem Okta rejects the expression for, or that
+	// makes it fail whenever it is evaluated.
+	SeverityError Severity = iota
+	// SeverityWarning is a likely mistake, e.g. an unknown function or a
+	// comparison that is always false, the checker can't be sure about.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Problem is an error or warning of an expression.
+type Problem struct {
+	// Pos is the byte offset of the problem in the expression.
+	Pos      int
+	Severity Severity
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s at column %d", p.Message, p.Pos+1)
+}
+
+// Check parses and type checks the expression in the context and returns
+// its problems in the order they appear. An expression that doesn't parse
+// has a single error.
+func Check(src string, ctx Context) []Problem {
+	n, err := Parse(src)
+	if err != nil {
+		if e, ok := err.(*Error); ok {
+			return []Problem{{Pos: e.Pos, Severity: SeverityError, Message: e.Message}}
+		}
+		return []Problem{{Severity: SeverityError, Message: err.Error()}}
+	}
+	c := &checker{ctx: ctx}
+	c.check(n)
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		return c.problems[i].Pos < c.problems[j].Pos
+	})
+	return c.problems
+}
+
+type checker struct {
+	ctx      Context
+	problems []Problem
+	// selections is the depth of the selections and projections being
+	// checked, in which a name is a property of the element.
+	selections int
+}
+
+func (c *checker) errorf(n Node, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Pos: n.Pos(), Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) warnf(n Node, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Pos: n.Pos(), Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// check reports the problems of the node and returns its type.
+func (c *checker) check(n Node) Type {
+	switch n := n.(type) {
+	case *Literal:
+		switch n.Kind {
+		case StringLiteral:
+			return String
+		case NumberLiteral:
+			return Number
+		case BooleanLiteral:
+			return Boolean
+		}
+		return Null
+	case *Ident:
+		if _, ok := namespaces[n.Name]; ok {
+			c.warnf(n, "%s is a function namespace, not a value", n.Name)
+			return Unknown
+		}
+		if c.selections == 0 && !c.ctx.allows(n.Name) {
+			c.warnf(n, "%q isn't a variable of %s expressions", n.Name, c.ctx)
+		}
+		return Unknown
+	case *Member:
+		if ident, ok := n.X.(*Ident); ok {
+			if _, ok := namespaces[ident.Name]; ok {
+				c.warnf(n, "%s.%s is a function, not a property", ident.Name, n.Name)
+				return Unknown
+			}
+		}
+		if t := c.check(n.X); isPrimitive(t) {
+			c.warnf(n, "property %q of %s value", n.Name, an(t))
+		}
+		return Unknown
+	case *Call:
+		return c.call(n)
+	case *Index:
+		if t := c.check(n.X); isPrimitive(t) {
+			c.errorf(n, "cannot index %s value", an(t))
+		}
+		c.check(n.Index)
+		return Unknown
+	case *Selection:
+		return c.selection(n)
+	case *Unary:
+		t := c.check(n.X)
+		if n.Op == "-" {
+			if !compatible(t, Number) {
+				c.errorf(n, "operator - takes a number, not %s", an(t))
+			}
+			return Number
+		}
+		if !compatible(t, Boolean) {
+			c.errorf(n, "operator %s takes a boolean, not %s", n.Op, an(t))
+		}
+		return Boolean
+	case *Binary:
+		return c.binary(n)
+	case *Ternary:
+		cond := c.check(n.Cond)
+		els := c.check(n.Else)
+		if n.Then == nil {
+			if cond == els {
+				return cond
+			}
+			return Unknown
+		}
+		if !compatible(cond, Boolean) {
+			c.errorf(n, "condition of ?: is %s, not a boolean", an(cond))
+		}
+		if then := c.check(n.Then); then == els {
+			return then
+		}
+		return Unknown
+	case *Paren:
+		return c.check(n.X)
+	case *InlineList:
+		for _, element := range n.Elements {
+			c.check(element)
+		}
+		return Array
+	case *InlineMap:
+		for i := range n.Keys {
+			// keys are property names, e.g. {'group.profile.name': '...'}
+			if _, ok := n.Keys[i].(*Ident); !ok {
+				c.check(n.Keys[i])
+			}
+			c.check(n.Values[i])
+		}
+		return Map
+	}
+	return Unknown
+}
+
+func (c *checker) call(n *Call) Type {
+	var (
+		name string
+		sig  signature
+		ok   bool
+	)
+	switch fn := n.Fn.(type) {
+	case *Ident:
+		name = fn.Name
+		sig, ok = functions[name]
+		if !ok {
+			c.warnf(n, "unknown function %s", name)
+		}
+	case *Member:
+		ident, isIdent := fn.X.(*Ident)
+		switch {
+		case isIdent && namespaces[ident.Name] != nil:
+			name = ident.Name + "." + fn.Name
+			sig, ok = namespaces[ident.Name][fn.Name]
+			if !ok {
+				c.warnf(n, "unknown function %s", name)
+			}
+		case isIdent && ident.Name == "user":
+			c.check(fn.X)
+			name = "user." + fn.Name
+			sig, ok = userMethods[fn.Name]
+			if !ok {
+				c.warnf(n, "unknown method %s", name)
+			}
+		default:
+			if t := c.check(fn.X); isPrimitive(t) {
+				c.warnf(n, "method %s of %s value", fn.Name, an(t))
+			}
+		}
+	}
+	args := make([]Type, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = c.check(arg)
+	}
+	if !ok {
+		return Unknown
+	}
+	min, max := len(sig.params)-sig.optional, len(sig.params)
+	switch {
+	case sig.variadic && len(args) < min:
+		c.errorf(n, "%s takes at least %s, not %d", name, arguments(min), len(args))
+		return sig.result
+	case !sig.variadic && (len(args) < min || len(args) > max):
+		c.errorf(n, "%s takes %s, not %d", name, arity(min, max), len(args))
+		return sig.result
+	}
+	for i, t := range args {
+		param := sig.params[len(sig.params)-1]
+		if i < len(sig.params) {
+			param = sig.params[i]
+		}
+		if !compatible(t, param) {
+			c.errorf(n.Args[i], "argument %d of %s is %s, not %s", i+1, name, an(t), an(param))
+		}
+	}
+	return sig.result
+}
+
+// selection checks a selection or projection. A selection's condition is a
+// boolean, .?[ selects an array of an array and a map of a map, .^[ and .$[
+// an element of an array.
+func (c *checker) selection(n *Selection) Type {
+	t := c.check(n.X)
+	if isPrimitive(t) {
+		c.errorf(n, "operator %s] takes an array or a map, not %s", n.Op, an(t))
+	}
+	c.selections++
+	cond := c.check(n.Cond)
+	c.selections--
+	if n.Op == ".![" {
+		return Array
+	}
+	if !compatible(cond, Boolean) {
+		c.errorf(n.Cond, "condition of %s] is %s, not a boolean", n.Op, an(cond))
+	}
+	if n.Op == ".?[" && (t == Array || t == Map) {
+		return t
+	}
+	return Unknown
+}
+
+func (c *checker) binary(n *Binary) Type {
+	x, y := c.check(n.X), c.check(n.Y)
+	switch n.Op {
+	case "AND", "OR", "&&", "||":
+		for _, t := range []Type{x, y} {
+			if !compatible(t, Boolean) {
+				c.errorf(n, "operator %s takes booleans, not %s", n.Op, an(t))
+				break
+			}
+		}
+		return Boolean
+	case "+":
+		switch {
+		case x == String || y == String:
+			return String
+		case x == Number && y == Number:
+			return Number
+		case x != Unknown && y != Unknown:
+			c.errorf(n, "operator + takes numbers or strings, not %s and %s", an(x), an(y))
+		}
+		return Unknown
+	case "-", "*", "/", "%":
+		for _, t := range []Type{x, y} {
+			if !compatible(t, Number) {
+				c.errorf(n, "operator %s takes numbers, not %s", n.Op, an(t))
+				break
+			}
+		}
+		return Number
+	case "==", "!=":
+		if x != Unknown && y != Unknown && x != Null && y != Null && x != y {
+			always := "false"
+			if n.Op == "!=" {
+				always = "true"
+			}
+			c.warnf(n, "comparison of %s with %s is always %s", an(x), an(y), always)
+		}
+		return Boolean
+	case "matches":
+		for _, t := range []Type{x, y} {
+			if !compatible(t, String) {
+				c.errorf(n, "operator matches takes strings, not %s", an(t))
+				break
+			}
+		}
+		return Boolean
+	}
+	// <, >, <= and >=
+	for _, t := range []Type{x, y} {
+		if t == Boolean || t == Array || t == Map || t == Null {
+			c.errorf(n, "operator %s can't compare %s", n.Op, an(t))
+			return Boolean
+		}
+	}
+	if (x == String && y == Number) || (x == Number && y == String) {
+		c.errorf(n, "operator %s can't compare %s with %s", n.Op, an(x), an(y))
+	}
+	return Boolean
+}
+
+// compatible reports if a value of the type can be used where the wanted
+// type is expected. null is compatible with any type.
+func compatible(t, want Type) bool {
+	return t == Unknown || want == Unknown || t == Null || t == want
+}
+
+func isPrimitive(t Type) bool {
+	return t == String || t == Number || t == Boolean || t == Null
+}
+
+func arity(min, max int) string {
+	if min == max {
+		return arguments(min)
+	}
+	return fmt.Sprintf("%d to %d arguments", min, max)
+}
+
+func arguments(n int) string {
+	if n == 1 {
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+// an returns the type with its indefinite article, e.g. an array.
+func an(t Type) string {
+	if t == Array || t == Unknown {
+		return "an " + t.String()
+	}
+	return "a " + t.String()
+}
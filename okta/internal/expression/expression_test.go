@@ -0,0 +1,215 @@
+package expression
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		`user.department == "Engineering"`,
+		`isMemberOfAnyGroup("00g1emaKYZTWRYYRRTSK", "00g1emaKYZTWRYYRRTSL")`,
+		`String.stringContains(user.email, '@example.com') AND user.title != null`,
+		`user.getGroups({'group.profile.name': 'West Coast.*'}, {'group.type': {'OKTA_GROUP'}})`,
+		`user.isMemberOf({'group.id': {'00gjitX9HqABSoqTB0g3', '00gjitX9HqABSoqTB0g4'}})`,
+		`user.countryCode != null ? Iso3166Convert.toAlpha3(user.countryCode) : "USA"`,
+		`appuser.displayName ?: user.firstName + " " + user.lastName`,
+		`NOT (user.age >= 18) or !hasDirectoryUser()`,
+		`Arrays.contains(user.emails, 'a''b') && user.emails[0] matches '.+@example\.com'`,
+		`-user.level * (2 + 3) % 4 / 1.5`,
+		`{:}`,
+		`{}`,
+		`user.getGroups({'group.type': {'OKTA_GROUP'}}, {'group.profile.name': 'East.*'}).![profile.name]`,
+		`user.getGroups({'group.type': {'OKTA_GROUP'}}).?[profile.name matches 'East.*'].^[true]`,
+		`findDirectoryUser()?.firstName`,
+		`user.level > 1L AND user.score < 1e3 AND user.ratio <= 2.5E-4`,
+		`user.level gt 1 and user.level LE 10 or user.score eq 100 AND user.score ne user.max`,
+		`user.level ge 1 AND user.level lt 10 AND user.score mod 2 == user.score div 2`,
+	}
+	for _, src := range valid {
+		if _, err := Parse(src); err != nil {
+			t.Errorf("Parse(%q): %v", src, err)
+		}
+	}
+
+	invalid := map[string]string{
+		``:                        "empty expression at column 1",
+		`user.login ==`:           "unexpected end of expression at column 14",
+		`"unterminated`:           "unterminated string at column 1",
+		`user.`:                   "expected a property or method name, found end of expression at column 6",
+		`String.len(user.login`:   `expected ")", found end of expression at column 22`,
+		`a ? b`:                   `expected ":", found end of expression at column 6`,
+		`user.login # comment`:    "unexpected character '#' at column 12",
+		`user.login user.email`:   `unexpected "user" at column 12`,
+		`{'a': 'b', 'c'}`:         `expected ":", found "}" at column 15`,
+		`isMemberOfGroup('x',)`:   `unexpected ")" at column 21`,
+		`user.emails[0`:           `expected "]", found end of expression at column 14`,
+		`(user.login == 'a'`:      `expected ")", found end of expression at column 19`,
+		`user.login == 'a' 'b'`:   "unexpected string 'b' at column 19",
+		`user.login = 'a'`:        "unexpected character '=' at column 12",
+		`user.login =='a' && AND`: `unexpected "AND" at column 21`,
+		`user.emails.![`:          "unexpected end of expression at column 15",
+		`user.emails.?[true`:      `expected "]", found end of expression at column 19`,
+		`user?.`:                  "expected a property or method name, found end of expression at column 7",
+	}
+	for src, want := range invalid {
+		_, err := Parse(src)
+		if err == nil {
+			t.Errorf("Parse(%q) succeeded, want %q", src, want)
+			continue
+		}
+		if err.Error() != want {
+			t.Errorf("Parse(%q) = %q, want %q", src, err, want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := map[string]string{
+		`user.department=="Engineering"`:                                  `user.department == "Engineering"`,
+		`  isMemberOfAnyGroup( "a" ,'b' )  `:                              `isMemberOfAnyGroup("a", 'b')`,
+		`user.a and not(user.b) Or user.c`:                                `user.a AND NOT (user.b) OR user.c`,
+		`user.a&&!user.b`:                                                 `user.a && !user.b`,
+		"user.x!=null?user.x:'none'":                                      `user.x != null ? user.x : 'none'`,
+		`user.x?:user.y`:                                                  `user.x ?: user.y`,
+		`{ 'a','b' }`:                                                     `{'a', 'b'}`,
+		`user.getGroups({'group.type':{'OKTA_GROUP'}})`:                   `user.getGroups({'group.type': {'OKTA_GROUP'}})`,
+		`user.emails[ 0 ]`:                                                `user.emails[0]`,
+		`TRUE == False`:                                                   `true == false`,
+		"String.toUpperCase(\n  user.firstName\n) + '\\'s'":               `String.toUpperCase(user.firstName) + '\'s'`,
+		`- user.level`:                                                    `-user.level`,
+		`user.login MATCHES '.*'`:                                         `user.login matches '.*'`,
+		`user.level gt 1 and user.level LE 10`:                            `user.level > 1 AND user.level <= 10`,
+		`user.a eq 1 or user.b ne 2 or user.c lt 3 or user.d ge 4`:        `user.a == 1 OR user.b != 2 OR user.c < 3 OR user.d >= 4`,
+		`user.a div 2 mod 3`:                                              `user.a / 2 % 3`,
+		`{:}`:                                                             `{:}`,
+		`(user.a == 1) && (user.b == 2 || user.c == 3)`:                   `(user.a == 1) && (user.b == 2 || user.c == 3)`,
+		`String.stringSwitch(user.x,'d','k1','v1','k2','v2')`:             `String.stringSwitch(user.x, 'd', 'k1', 'v1', 'k2', 'v2')`,
+		`user.a==1?user.b==2?'x':'y':'z'`:                                 `user.a == 1 ? user.b == 2 ? 'x' : 'y' : 'z'`,
+		`Arrays.get(user.emails,0).toLowerCase()`:                         `Arrays.get(user.emails, 0).toLowerCase()`,
+		`findDirectoryUser().firstName`:                                   `findDirectoryUser().firstName`,
+		`user.a+user.b-3*4`:                                               `user.a + user.b - 3 * 4`,
+		`isMemberOfGroupName("Group ""quoted""")`:                         `isMemberOfGroupName("Group ""quoted""")`,
+		`user.profileUrl != null and user.profileUrl != ""`:               `user.profileUrl != null AND user.profileUrl != ""`,
+		`{a : 1, b : 2}`:                                                  `{a: 1, b: 2}`,
+		`Convert.toInt( "12" )`:                                           `Convert.toInt("12")`,
+		`user . login`:                                                    `user.login`,
+		`Time.now( )`:                                                     `Time.now()`,
+		`Groups.startsWith("OKTA","group",10)`:                            `Groups.startsWith("OKTA", "group", 10)`,
+		`getFilteredGroups({"00gml2xHE3RYRx7cM0g3"},"group.name",40)`:     `getFilteredGroups({"00gml2xHE3RYRx7cM0g3"}, "group.name", 40)`,
+		`user.getGroups({'group.type':{'OKTA_GROUP'}}).![ profile.name ]`: `user.getGroups({'group.type': {'OKTA_GROUP'}}).![profile.name]`,
+		`findDirectoryUser() ?. firstName`:                                `findDirectoryUser()?.firstName`,
+		`user.level>1L||user.score<1e3`:                                   `user.level > 1L || user.score < 1e3`,
+	}
+	for src, want := range cases {
+		got, err := Format(src)
+		if err != nil {
+			t.Errorf("Format(%q): %v", src, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Format(%q) = %q, want %q", src, got, want)
+		}
+		if again, _ := Format(got); again != got {
+			t.Errorf("Format(%q) isn't stable: %q", got, again)
+		}
+	}
+	if _, err := Format(`user.login ==`); err == nil {
+		t.Error("Format of an invalid expression succeeded")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		src  string
+		ctx  Context
+		want []string
+	}{
+		{src: `user.department == "Engineering" AND isMemberOfGroupName("Admins")`, ctx: GroupRule},
+		{src: `String.substringBefore(user.email, "@") + "." + Convert.toInt("1")`, ctx: ProfileMapping},
+		{src: `source.login != null ? source.login : appuser.userName`, ctx: ProfileMapping},
+		{src: `Groups.startsWith("OKTA", "admin", 10)`, ctx: Claim},
+		{src: `Arrays.contains(access.scope, "email") ? user.email : null`, ctx: Claim},
+		{src: `user.getGroups({'group.type': {'OKTA_GROUP'}})`, ctx: SAMLAttribute},
+		{src: `Time.now("America/Los_Angeles", "yyyy-MM-dd")`, ctx: AnyContext},
+		{src: `isMemberOfAnyGroup("a", "b", "c")`, ctx: GroupRule},
+		{src: `String.stringSwitch(user.x, "d", "k1", "v1", "k2", "v2")`, ctx: GroupRule},
+		{src: `user.age >= 18 AND user.level < 3.5`, ctx: GroupRule},
+		{src: `user.getGroups({'group.type': {'OKTA_GROUP'}}, {'group.profile.name': 'East.*'}).![profile.name]`, ctx: SAMLAttribute},
+		{src: `Arrays.contains(user.getGroups({'group.type': {'OKTA_GROUP'}}).?[profile.name matches 'East.*'], "x")`, ctx: Claim},
+		{src: `findDirectoryUser()?.firstName`, ctx: AnyContext},
+		{src: `user.level > 1L AND user.score < 1e3`, ctx: GroupRule},
+
+		{src: `user.login ==`, ctx: GroupRule, want: []string{
+			"error: unexpected end of expression at column 14"}},
+		{src: `String.len(user.login, 1)`, ctx: GroupRule, want: []string{
+			"error: String.len takes 1 argument, not 2 at column 8"}},
+		{src: `String.substring(user.login, "0", 1)`, ctx: GroupRule, want: []string{
+			"error: argument 2 of String.substring is a string, not a number at column 30"}},
+		{src: `Time.now("UTC", "yyyy", "x")`, ctx: AnyContext, want: []string{
+			"error: Time.now takes 0 to 2 arguments, not 3 at column 6"}},
+		{src: `isMemberOfAnyGroup()`, ctx: GroupRule, want: []string{
+			"error: isMemberOfAnyGroup takes at least 1 argument, not 0 at column 1"}},
+		{src: `user.department AND "x"`, ctx: GroupRule, want: []string{
+			"error: operator AND takes booleans, not a string at column 17"}},
+		{src: `NOT String.len(user.login)`, ctx: GroupRule, want: []string{
+			"error: operator NOT takes a boolean, not a number at column 1"}},
+		{src: `"x" ? 1 : 2`, ctx: AnyContext, want: []string{
+			"error: condition of ?: is a string, not a boolean at column 5"}},
+		{src: `"a" * 2`, ctx: AnyContext, want: []string{
+			"error: operator * takes numbers, not a string at column 5"}},
+		{src: `true + 1`, ctx: AnyContext, want: []string{
+			"error: operator + takes numbers or strings, not a boolean and a number at column 6"}},
+		{src: `String.len(user.login) > "5"`, ctx: GroupRule, want: []string{
+			"error: operator > can't compare a number with a string at column 24"}},
+		{src: `user.a < true`, ctx: GroupRule, want: []string{
+			"error: operator < can't compare a boolean at column 8"}},
+		{src: `"abc"[0]`, ctx: AnyContext, want: []string{
+			"error: cannot index a string value at column 6"}},
+		{src: `String.len(user.x) == "5"`, ctx: GroupRule, want: []string{
+			"warning: comparison of a number with a string is always false at column 20"}},
+		{src: `String.toUpper(user.login)`, ctx: GroupRule, want: []string{
+			"warning: unknown function String.toUpper at column 8"}},
+		{src: `isMemberOf("x")`, ctx: GroupRule, want: []string{
+			"warning: unknown function isMemberOf at column 1"}},
+		{src: `user.getManager()`, ctx: GroupRule, want: []string{
+			"warning: unknown method user.getManager at column 6"}},
+		{src: `appuser.login == "x"`, ctx: GroupRule, want: []string{
+			`warning: "appuser" isn't a variable of group_rule expressions at column 1`}},
+		{src: `String.len`, ctx: AnyContext, want: []string{
+			"warning: String.len is a function, not a property at column 8"}},
+		{src: `"abc".![length]`, ctx: AnyContext, want: []string{
+			"error: operator .![] takes an array or a map, not a string at column 6"}},
+		{src: `user.emails.?["x"]`, ctx: GroupRule, want: []string{
+			"error: condition of .?[] is a string, not a boolean at column 15"}},
+		{src: `user.emails.?[appuser.x != null]`, ctx: GroupRule},
+		{src: `"abc".length`, ctx: AnyContext, want: []string{
+			`warning: property "length" of a string value at column 7`}},
+		{src: `String.len(user.login, 1) == "x" AND apuser.x`, ctx: GroupRule, want: []string{
+			"error: String.len takes 1 argument, not 2 at column 8",
+			"warning: comparison of a number with a string is always false at column 27",
+			`warning: "apuser" isn't a variable of group_rule expressions at column 38`,
+		}},
+	}
+	for _, c := range cases {
+		var got []string
+		for _, p := range Check(c.src, c.ctx) {
+			got = append(got, p.Severity.String()+": "+p.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+			t.Errorf("Check(%q, %s) =\n%s\nwant\n%s", c.src, c.ctx, strings.Join(got, "\n"), strings.Join(c.want, "\n"))
+		}
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	for _, name := range ContextNames() {
+		ctx, ok := ParseContext(name)
+		if !ok || ctx.String() != name {
+			t.Errorf("ParseContext(%q) = %s, %t", name, ctx, ok)
+		}
+	}
+	if _, ok := ParseContext("unknown"); ok {
+		t.Error(`ParseContext("unknown") succeeded`)
+	}
+}
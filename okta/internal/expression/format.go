@@ -0,0 +1,100 @@
+package expression
+
+import "strings"
+
+// Format returns the expression in canonical form: a space around binary and
+// ternary operators and after commas, none inside parentheses, brackets and
+// braces, and upper case word operators. Literals, parentheses and quoting are
+// kept as they were written.
+func Format(src string) (string, error) {
+	n, err := Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	format(&b, n)
+	return b.String(), nil
+}
+
+func format(b *strings.Builder, n Node) {
+	switch n := n.(type) {
+	case *Literal:
+		b.WriteString(n.Text)
+	case *Ident:
+		b.WriteString(n.Name)
+	case *Member:
+		format(b, n.X)
+		if n.Safe {
+			b.WriteString("?.")
+		} else {
+			b.WriteString(".")
+		}
+		b.WriteString(n.Name)
+	case *Call:
+		format(b, n.Fn)
+		b.WriteString("(")
+		formatList(b, n.Args)
+		b.WriteString(")")
+	case *Index:
+		format(b, n.X)
+		b.WriteString("[")
+		format(b, n.Index)
+		b.WriteString("]")
+	case *Selection:
+		format(b, n.X)
+		b.WriteString(n.Op)
+		format(b, n.Cond)
+		b.WriteString("]")
+	case *Unary:
+		b.WriteString(n.Op)
+		if n.Op == "NOT" {
+			b.WriteString(" ")
+		}
+		format(b, n.X)
+	case *Binary:
+		format(b, n.X)
+		b.WriteString(" " + n.Op + " ")
+		format(b, n.Y)
+	case *Ternary:
+		format(b, n.Cond)
+		if n.Then == nil {
+			b.WriteString(" ?: ")
+		} else {
+			b.WriteString(" ? ")
+			format(b, n.Then)
+			b.WriteString(" : ")
+		}
+		format(b, n.Else)
+	case *Paren:
+		b.WriteString("(")
+		format(b, n.X)
+		b.WriteString(")")
+	case *InlineList:
+		b.WriteString("{")
+		formatList(b, n.Elements)
+		b.WriteString("}")
+	case *InlineMap:
+		b.WriteString("{")
+		if len(n.Keys) == 0 {
+			b.WriteString(":")
+		}
+		for i := range n.Keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			format(b, n.Keys[i])
+			b.WriteString(": ")
+			format(b, n.Values[i])
+		}
+		b.WriteString("}")
+	}
+}
+
+func formatList(b *strings.Builder, nodes []Node) {
+	for i, n := range nodes {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		format(b, n)
+	}
+}
@@ -0,0 +1,201 @@
+package expression
+
+// Type is the type of a value of an expression. The values of profile
+// properties, and so of most expressions, aren't known without the profile
+// schemas and are Unknown, which is compatible with any type.
+type Type int
+
+const (
+	Unknown Type = iota
+	String
+	Number
+	Boolean
+	Array
+	Map
+	Null
+)
+
+func (t Type) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Number:
+		return "number"
+	case Boolean:
+		return "boolean"
+	case Array:
+		return "array"
+	case Map:
+		return "map"
+	case Null:
+		return "null"
+	}
+	return "unknown"
+}
+
+// signature is the signature of a function. Unknown parameters take a value
+// of any type. The last optional parameters can be left out, and the last
+// parameter of a variadic function can be repeated.
+type signature struct {
+	params   []Type
+	optional int
+	variadic bool
+	result   Type
+}
+
+func fn(result Type, params ...Type) signature {
+	return signature{params: params, result: result}
+}
+
+func (s signature) withOptional(n int) signature {
+	s.optional = n
+	return s
+}
+
+func (s signature) withVariadic() signature {
+	s.variadic = true
+	return s
+}
+
+// namespaces are the functions called on a namespace, e.g.
+// String.toUpperCase(user.login).
+var namespaces = map[string]map[string]signature{
+	"String": {
+		"append":          fn(String, String, String),
+		"join":            fn(String, String, String).withVariadic(),
+		"len":             fn(Number, String),
+		"removeSpaces":    fn(String, String),
+		"replace":         fn(String, String, String, String),
+		"replaceFirst":    fn(String, String, String, String),
+		"startsWith":      fn(Boolean, String, String),
+		"stringContains":  fn(Boolean, String, String),
+		"stringSwitch":    fn(String, String, String, String).withVariadic(),
+		"substring":       fn(String, String, Number, Number),
+		"substringAfter":  fn(String, String, String),
+		"substringBefore": fn(String, String, String),
+		"toLowerCase":     fn(String, String),
+		"toUpperCase":     fn(String, String),
+	},
+	"Arrays": {
+		"add":         fn(Array, Array, Unknown),
+		"remove":      fn(Array, Array, Unknown),
+		"clear":       fn(Array, Array),
+		"get":         fn(Unknown, Array, Number),
+		"flatten":     fn(Array, Unknown).withVariadic(),
+		"contains":    fn(Boolean, Array, Unknown),
+		"size":        fn(Number, Array),
+		"isEmpty":     fn(Boolean, Array),
+		"toCsvString": fn(String, Array),
+	},
+	"Convert": {
+		"toInt": fn(Number, Unknown),
+		"toNum": fn(Number, String),
+	},
+	"Iso3166Convert": {
+		"toAlpha2":  fn(String, String),
+		"toAlpha3":  fn(String, String),
+		"toNumeric": fn(String, String),
+		"toName":    fn(String, String),
+	},
+	"Time": {
+		"now":                  fn(String, String, String).withOptional(2),
+		"fromWindowsToIso8601": fn(String, String),
+		"fromUnixToIso8601":    fn(String, String),
+		"fromStringToIso8601":  fn(String, String, String),
+		"fromIso8601ToWindows": fn(String, String),
+		"fromIso8601ToUnix":    fn(String, String),
+		"fromIso8601ToString":  fn(String, String, String),
+	},
+	"Groups": {
+		"contains":   fn(Array, String, String, Number),
+		"startsWith": fn(Array, String, String, Number),
+		"endsWith":   fn(Array, String, String, Number),
+	},
+}
+
+// functions are the functions called without a namespace.
+var functions = map[string]signature{
+	"isMemberOfGroup":               fn(Boolean, String),
+	"isMemberOfGroupName":           fn(Boolean, String),
+	"isMemberOfGroupNameStartsWith": fn(Boolean, String),
+	"isMemberOfGroupNameContains":   fn(Boolean, String),
+	"isMemberOfGroupNameRegex":      fn(Boolean, String),
+	"isMemberOfAnyGroup":            fn(Boolean, String).withVariadic(),
+	"getFilteredGroups":             fn(Array, Array, String, Number),
+	"hasDirectoryUser":              fn(Boolean),
+	"findDirectoryUser":             fn(Unknown),
+	"hasWorkdayUser":                fn(Boolean),
+	"findWorkdayUser":               fn(Unknown),
+	"getManagerUser":                fn(Unknown, String),
+	"getManagerAppUser":             fn(Unknown, String, String),
+	"getAssistantUser":              fn(Unknown, String),
+	"getAssistantAppUser":           fn(Unknown, String, String),
+}
+
+// userMethods are the methods of the user variable.
+var userMethods = map[string]signature{
+	"getGroups":           fn(Array, Map).withVariadic(),
+	"isMemberOf":          fn(Boolean, Map).withVariadic(),
+	"getLinkedObject":     fn(Unknown, String),
+	"getInternalProperty": fn(Unknown, String),
+}
+
+// Context is where an expression is evaluated, which decides the variables
+// it can use.
+type Context int
+
+const (
+	// AnyContext allows the variables of every context.
+	AnyContext Context = iota
+	// GroupRule is the expression of a group rule.
+	GroupRule
+	// ProfileMapping is the expression of a profile mapping property.
+	ProfileMapping
+	// Claim is the value of an authorization server claim.
+	Claim
+	// SAMLAttribute is a value of a SAML app attribute statement.
+	SAMLAttribute
+)
+
+var contexts = []struct {
+	name      string
+	variables []string
+}{
+	AnyContext:     {"any", []string{"user", "appuser", "idpuser", "source", "app", "org", "access", "session"}},
+	GroupRule:      {"group_rule", []string{"user"}},
+	ProfileMapping: {"profile_mapping", []string{"source", "user", "appuser", "idpuser", "app", "org"}},
+	Claim:          {"claim", []string{"user", "app", "org", "access", "session", "appuser", "idpuser"}},
+	SAMLAttribute:  {"saml_attribute", []string{"user", "app", "org", "appuser", "idpuser", "session"}},
+}
+
+func (c Context) String() string {
+	return contexts[c].name
+}
+
+// ContextNames returns the names of the contexts.
+func ContextNames() []string {
+	names := make([]string, len(contexts))
+	for i, c := range contexts {
+		names[i] = c.name
+	}
+	return names
+}
+
+// ParseContext returns the context of the name.
+func ParseContext(name string) (Context, bool) {
+	for i, c := range contexts {
+		if c.name == name {
+			return Context(i), true
+		}
+	}
+	return AnyContext, false
+}
+
+func (c Context) allows(variable string) bool {
+	for _, v := range contexts[c].variables {
+		if v == variable {
+			return true
+		}
+	}
+	return false
+}
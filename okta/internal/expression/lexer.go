@@ -0,0 +1,163 @@
+// Package expression parses, type checks and formats Okta Expression
+// Language expressions, see
+// https://developer.okta.com/docs/reference/okta-expression-language/. The
+// language is a subset of the Spring Expression Language: literals, property
+// access, function and method calls, logical, relational and arithmetic
+// operators, the ternary, Elvis and safe navigation operators, collection
+// selection and projection, and {} array and map literals.
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	// text is the source of the token, a string's with its quotes.
+	text string
+	pos  int
+}
+
+// keywords are the operators spelled as words, they are case insensitive.
+// The relational and arithmetic ones are the same as their symbols.
+var keywords = map[string]string{
+	"and":     "AND",
+	"or":      "OR",
+	"not":     "NOT",
+	"matches": "matches",
+	"eq":      "==",
+	"ne":      "!=",
+	"lt":      "<",
+	"le":      "<=",
+	"gt":      ">",
+	"ge":      ">=",
+	"div":     "/",
+	"mod":     "%",
+}
+
+// operators are the symbol operators, longest first. .?[, .^[ and .$[ open
+// a selection, .![ a projection.
+var operators = []string{
+	".?[", ".^[", ".$[", ".![",
+	"==", "!=", "<=", ">=", "&&", "||", "?:", "?.",
+	"<", ">", "!", "+", "-", "*", "/", "%", "?", ":", ".", ",", "(", ")", "[", "]", "{", "}",
+}
+
+// Error is a syntax or type error of an expression.
+type Error struct {
+	// Pos is the byte offset of the error in the expression.
+	Pos     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Message, e.Pos+1)
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			end, err := scanString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[i:end], pos: i})
+			i = end
+		case isDigit(c):
+			start := i
+			for i < len(src) && isDigit(src[i]) {
+				i++
+			}
+			if i+1 < len(src) && src[i] == '.' && isDigit(src[i+1]) {
+				i++
+				for i < len(src) && isDigit(src[i]) {
+					i++
+				}
+			}
+			// an exponent, e.g. 1e3 or 2.5E-4
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				j := i + 1
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				if j < len(src) && isDigit(src[j]) {
+					for i = j; i < len(src) && isDigit(src[i]); i++ {
+					}
+				}
+			}
+			// a type suffix, e.g. 1L
+			if i < len(src) && strings.IndexByte("lLfFdD", src[i]) >= 0 {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[start:i], pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			word := src[start:i]
+			if keyword, ok := keywords[strings.ToLower(word)]; ok {
+				tokens = append(tokens, token{kind: tokenOperator, text: keyword, pos: start})
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: word, pos: start})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &Error{Pos: i, Message: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// scanString returns the end of the string literal starting at the quote.
+// A quote is escaped by a backslash or by doubling it.
+func scanString(src string, start int) (int, error) {
+	quote := src[start]
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(src) && src[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, &Error{Pos: start, Message: "unterminated string"}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
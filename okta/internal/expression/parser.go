@@ -0,0 +1,437 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Node is a node of the syntax tree of an expression.
+type Node interface {
+	// Pos is the byte offset of the node in the expression.
+	Pos() int
+}
+
+// LiteralKind is the kind of a literal.
+type LiteralKind int
+
+const (
+	StringLiteral LiteralKind = iota
+	NumberLiteral
+	BooleanLiteral
+	NullLiteral
+)
+
+type (
+	// Literal is a string, number, boolean or null literal. Text is its source,
+	// a string's with its quotes.
+	Literal struct {
+		At   int
+		Kind LiteralKind
+		Text string
+	}
+
+	// Ident is a variable, e.g. user, or a namespace, e.g. String.
+	Ident struct {
+		At   int
+		Name string
+	}
+
+	// Member is a property access, e.g. user.login, or with Safe the safe
+	// navigation findDirectoryUser()?.firstName that is null on null.
+	Member struct {
+		At   int
+		X    Node
+		Name string
+		Safe bool
+	}
+
+	// Call is a function call, e.g. isMemberOfAnyGroup(...), or a method
+	// call, e.g. String.toUpperCase(...). Fn is an Ident or a Member.
+	Call struct {
+		At   int
+		Fn   Node
+		Args []Node
+	}
+
+	// Index is an element access, e.g. user.emails[0].
+	Index struct {
+		At    int
+		X     Node
+		Index Node
+	}
+
+	// Selection is a collection selection, X.?[Cond] of the elements the
+	// condition holds for, X.^[Cond] of the first and X.$[Cond] of the last,
+	// or with Op .![ the projection X.![Cond] of the expression on each
+	// element. Cond is evaluated against the element.
+	Selection struct {
+		At   int
+		Op   string
+		X    Node
+		Cond Node
+	}
+
+	// Unary is a negation, !, NOT or -.
+	Unary struct {
+		At int
+		Op string
+		X  Node
+	}
+
+	// Binary is a logical, relational or arithmetic operation.
+	Binary struct {
+		At int
+		Op string
+		X  Node
+		Y  Node
+	}
+
+	// Ternary is a cond ? then : else, or with a nil Then the Elvis
+	// operation cond ?: else.
+	Ternary struct {
+		At   int
+		Cond Node
+		Then Node
+		Else Node
+	}
+
+	// Paren is a parenthesized expression, kept so that formatting doesn't
+	// change the grouping the author wrote.
+	Paren struct {
+		At int
+		X  Node
+	}
+
+	// InlineList is an inline list, e.g. {"a", "b"}.
+	InlineList struct {
+		At       int
+		Elements []Node
+	}
+
+	// InlineMap is an inline map, e.g. {a: "b"}.
+	InlineMap struct {
+		At     int
+		Keys   []Node
+		Values []Node
+	}
+)
+
+func (n *Literal) Pos() int    { return n.At }
+func (n *Ident) Pos() int      { return n.At }
+func (n *Member) Pos() int     { return n.At }
+func (n *Call) Pos() int       { return n.At }
+func (n *Index) Pos() int      { return n.At }
+func (n *Selection) Pos() int  { return n.At }
+func (n *Unary) Pos() int      { return n.At }
+func (n *Binary) Pos() int     { return n.At }
+func (n *Ternary) Pos() int    { return n.At }
+func (n *Paren) Pos() int      { return n.At }
+func (n *InlineList) Pos() int { return n.At }
+func (n *InlineMap) Pos() int  { return n.At }
+
+// Parse returns the syntax tree of the expression, or an *Error.
+func Parse(src string) (Node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 0, Message: "empty expression"}
+	}
+	n, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("unexpected %s", describe(t))}
+	}
+	return n, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the operators.
+func (p *parser) accept(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return t, false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return p.next(), true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) expect(op string) (token, error) {
+	if t, ok := p.accept(op); ok {
+		return t, nil
+	}
+	t := p.peek()
+	return t, &Error{Pos: t.pos, Message: fmt.Sprintf("expected %q, found %s", op, describe(t))}
+}
+
+func (p *parser) expr() (Node, error) {
+	return p.ternary()
+}
+
+func (p *parser) ternary() (Node, error) {
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.accept("?:"); ok {
+		els, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		return &Ternary{At: t.pos, Cond: cond, Else: els}, nil
+	}
+	t, ok := p.accept("?")
+	if !ok {
+		return cond, nil
+	}
+	then, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	els, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return &Ternary{At: t.pos, Cond: cond, Then: then, Else: els}, nil
+}
+
+// binary parses a left associative chain of the operators over operands
+// parsed by operand.
+func (p *parser) binary(operand func() (Node, error), ops ...string) (Node, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept(ops...)
+		if !ok {
+			return x, nil
+		}
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: t.pos, Op: t.text, X: x, Y: y}
+	}
+}
+
+func (p *parser) or() (Node, error) {
+	return p.binary(p.and, "OR", "||")
+}
+
+func (p *parser) and() (Node, error) {
+	return p.binary(p.relational, "AND", "&&")
+}
+
+func (p *parser) relational() (Node, error) {
+	return p.binary(p.additive, "==", "!=", "<", ">", "<=", ">=", "matches")
+}
+
+func (p *parser) additive() (Node, error) {
+	return p.binary(p.multiplicative, "+", "-")
+}
+
+func (p *parser) multiplicative() (Node, error) {
+	return p.binary(p.unary, "*", "/", "%")
+}
+
+func (p *parser) unary() (Node, error) {
+	t, ok := p.accept("!", "NOT", "-")
+	if !ok {
+		return p.postfix()
+	}
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &Unary{At: t.pos, Op: t.text, X: x}, nil
+}
+
+func (p *parser) postfix() (Node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if dot, ok := p.accept(".", "?."); ok {
+			name := p.next()
+			if name.kind != tokenIdent {
+				return nil, &Error{Pos: name.pos, Message: fmt.Sprintf("expected a property or method name, found %s", describe(name))}
+			}
+			x = &Member{At: name.pos, X: x, Name: name.text, Safe: dot.text == "?."}
+			if x, err = p.call(x); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if t, ok := p.accept(".?[", ".^[", ".$[", ".!["); ok {
+			cond, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &Selection{At: t.pos, Op: t.text, X: x, Cond: cond}
+			continue
+		}
+		if t, ok := p.accept("["); ok {
+			index, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &Index{At: t.pos, X: x, Index: index}
+			continue
+		}
+		return x, nil
+	}
+}
+
+// call parses the arguments if fn is followed by a parenthesis.
+func (p *parser) call(fn Node) (Node, error) {
+	if _, ok := p.accept("("); !ok {
+		return fn, nil
+	}
+	c := &Call{At: fn.Pos(), Fn: fn}
+	if _, ok := p.accept(")"); ok {
+		return c, nil
+	}
+	for {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		c.Args = append(c.Args, arg)
+		if _, ok := p.accept(","); ok {
+			continue
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{At: t.pos, Kind: StringLiteral, Text: t.text}, nil
+	case tokenNumber:
+		return &Literal{At: t.pos, Kind: NumberLiteral, Text: t.text}, nil
+	case tokenIdent:
+		switch strings.ToLower(t.text) {
+		case "true", "false":
+			return &Literal{At: t.pos, Kind: BooleanLiteral, Text: strings.ToLower(t.text)}, nil
+		case "null":
+			return &Literal{At: t.pos, Kind: NullLiteral, Text: "null"}, nil
+		}
+		return p.call(&Ident{At: t.pos, Name: t.text})
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return &Paren{At: t.pos, X: x}, nil
+		case "{":
+			return p.inline(t)
+		}
+	}
+	return nil, &Error{Pos: t.pos, Message: fmt.Sprintf("unexpected %s", describe(t))}
+}
+
+// inline parses an inline list or map after its opening brace. {} is an
+// empty list and {:} an empty map.
+func (p *parser) inline(open token) (Node, error) {
+	if _, ok := p.accept("}"); ok {
+		return &InlineList{At: open.pos}, nil
+	}
+	if _, ok := p.accept(":"); ok {
+		if _, err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		return &InlineMap{At: open.pos}, nil
+	}
+	var elements, values []Node
+	isMap := false
+	for {
+		element, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if len(elements) == 1 {
+			_, isMap = p.accept(":")
+		} else if isMap {
+			if _, err := p.expect(":"); err != nil {
+				return nil, err
+			}
+		}
+		if isMap {
+			value, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		if _, ok := p.accept(","); ok {
+			continue
+		}
+		if _, err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		if isMap {
+			return &InlineMap{At: open.pos, Keys: elements, Values: values}, nil
+		}
+		return &InlineList{At: open.pos, Elements: elements}, nil
+	}
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return "string " + t.text
+	case tokenNumber:
+		return "number " + t.text
+	case tokenIdent:
+		return fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+)
+
+// terraformParallelism is terraform's default -parallelism, the number of
+// resource operations it runs at once.
+const terraformParallelism = 10
+
+// defaultMaxInFlight is the default cap of concurrent requests per rate limit
+// bucket.
+const defaultMaxInFlight = terraformParallelism
+
+// MaxInFlight returns the cap of concurrent requests per rate limit bucket for
+// the provider's parallelism, the number of requests each of terraform's
+// resource operations makes at once.
+func MaxInFlight(parallelism int) int {
+	if parallelism <= 0 {
+		return defaultMaxInFlight
+	}
+	return terraformParallelism * parallelism
+}
+
+// rateLimitWindow is the length of Okta's rate limit window.
+const rateLimitWindow = time.Minute
+
+// Clock is the source of time for the admission controller.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// AdmissionController admits requests per rate limit bucket. It spreads the
+// bucket's remaining capacity evenly across the rest of the one minute window
+// instead of letting requests burst and then sleep until reset, and it caps
+// the number of requests in flight for each bucket. The cap adapts to
+// concurrent rate limit violations with additive increase, multiplicative
+// decrease.
+type AdmissionController struct {
+	apiMutex    *apimutex.APIMutex
+	clock       Clock
+	maxInFlight int
+	lock        sync.Mutex
+	buckets     map[string]*admissionBucket
+}
+
+type admissionBucket struct {
+	// next is the earliest time the next request can be admitted
+	next    time.Time
+	limiter *AIMDLimiter
+}
+
+// NewAdmissionController returns an admission controller for the api mutex.
+// A maxInFlight of zero or less uses the default cap.
+func NewAdmissionController(apiMutex *apimutex.APIMutex, maxInFlight int, clock Clock) *AdmissionController {
+	if maxInFlight <= 0 {
+		maxInFlight = defaultMaxInFlight
+	}
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &AdmissionController{
+		apiMutex:    apiMutex,
+		clock:       clock,
+		maxInFlight: maxInFlight,
+		buckets:     map[string]*admissionBucket{},
+	}
+}
+
+// Acquire blocks until the number of requests in flight for the bucket of the
+// method and path is under the cap, or the context is done. The returned
+// function releases the acquired place.
+func (a *AdmissionController) Acquire(ctx context.Context, method, path string) (func(), error) {
+	return a.bucket(a.apiMutex.Bucket(method, path)).limiter.Acquire(ctx)
+}
+
+// Success accounts for a request to the method and path that wasn't limited
+// for concurrency, growing the bucket's in flight cap.
+func (a *AdmissionController) Success(method, path string) {
+	a.bucket(a.apiMutex.Bucket(method, path)).limiter.Success()
+}
+
+// Backoff accounts for a concurrent rate limit violation of a request to the
+// method and path, shrinking the bucket's in flight cap.
+func (a *AdmissionController) Backoff(method, path string) {
+	a.bucket(a.apiMutex.Bucket(method, path)).limiter.Backoff()
+}
+
+// InFlightLimit returns the current in flight cap for the bucket of the method
+// and path.
+func (a *AdmissionController) InFlightLimit(method, path string) int {
+	return a.bucket(a.apiMutex.Bucket(method, path)).limiter.Limit()
+}
+
+// Reserve reserves the next time slot for a request to the method and path
+// and returns how long the request has to wait for it. While the bucket has
+// capacity its remaining budget, what is left under the capacity threshold, is
+// spaced evenly until reset. Without capacity requests are admitted from reset
+// onwards at the pace of a full window so that waiting requests don't all wake
+// at the same instant. The returned function releases the slot of a request
+// that gives up waiting for it, so that the requests reserving after it don't
+// wait for a request that is never made.
+func (a *AdmissionController) Reserve(method, path string) (time.Duration, func()) {
+	bucket := a.bucket(a.apiMutex.Bucket(method, path))
+	hasCapacity := a.apiMutex.HasCapacity(method, path)
+	status := a.apiMutex.Status(method, path)
+	now := a.clock.Now()
+	reset := time.Unix(status.Reset(), 0)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// the bucket's status is unknown or its window has reset
+	if status.Limit() <= 0 || !reset.After(now) {
+		return 0, func() {}
+	}
+
+	allowed := float64(status.Limit()) * float64(a.apiMutex.Capacity()) / 100.0
+	budget := allowed - float64(status.Limit()-status.Remaining())
+
+	slot := bucket.next
+	var interval time.Duration
+	if hasCapacity && budget > 0 {
+		if slot.Before(now) {
+			slot = now
+		}
+		interval = reset.Sub(now) / time.Duration(math.Ceil(budget))
+	} else {
+		if slot.Before(reset) {
+			slot = reset
+		}
+		interval = rateLimitWindow / time.Duration(math.Max(1, math.Ceil(allowed)))
+	}
+	bucket.next = slot.Add(interval)
+	return slot.Sub(now), func() {
+		a.lock.Lock()
+		defer a.lock.Unlock()
+		bucket.next = bucket.next.Add(-interval)
+	}
+}
+
+func (a *AdmissionController) bucket(name string) *admissionBucket {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	bucket, ok := a.buckets[name]
+	if !ok {
+		bucket = &admissionBucket{limiter: NewAIMDLimiter(a.maxInFlight)}
+		a.buckets[name] = bucket
+	}
+	return bucket
+}
@@ -0,0 +1,159 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+)
+
+// fakeClock is a clock whose time only moves when something waits on it.
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(time.Now().Unix(), 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestAdmissionSpreadsRemainingBudget(t *testing.T) {
+	clock := newFakeClock()
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	admission := NewAdmissionController(apiMutex, 0, clock)
+	path := "/api/v1/apps"
+
+	// 50% of 100 is allowed, 20 used, leaving a budget of 30 over 30 seconds
+	apiMutex.Update(http.MethodGet, path, 100, 80, clock.Now().Unix()+30)
+	for i, expected := range []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second} {
+		if wait, _ := admission.Reserve(http.MethodGet, path); wait != expected {
+			t.Errorf("request %d: expected wait of %s, got %s", i, expected, wait)
+		}
+	}
+}
+
+func TestAdmissionStaggersAfterReset(t *testing.T) {
+	clock := newFakeClock()
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	admission := NewAdmissionController(apiMutex, 0, clock)
+	path := "/api/v1/apps"
+
+	// 60 of 100 used is over the 50% capacity, requests wait for reset and
+	// are then admitted at the pace of 50 requests per minute
+	apiMutex.Update(http.MethodGet, path, 100, 40, clock.Now().Unix()+30)
+	for i, expected := range []time.Duration{30 * time.Second, 31200 * time.Millisecond, 32400 * time.Millisecond} {
+		if wait, _ := admission.Reserve(http.MethodGet, path); wait != expected {
+			t.Errorf("request %d: expected wait of %s, got %s", i, expected, wait)
+		}
+	}
+}
+
+func TestAdmissionReleasesCancelledSlot(t *testing.T) {
+	clock := newFakeClock()
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	admission := NewAdmissionController(apiMutex, 0, clock)
+	path := "/api/v1/apps"
+
+	apiMutex.Update(http.MethodGet, path, 100, 80, clock.Now().Unix()+30)
+	admission.Reserve(http.MethodGet, path)
+	wait, cancel := admission.Reserve(http.MethodGet, path)
+	if wait != time.Second {
+		t.Fatalf("expected wait of %s, got %s", time.Second, wait)
+	}
+	cancel()
+	// the cancelled request's slot goes to the next request
+	if wait, _ = admission.Reserve(http.MethodGet, path); wait != time.Second {
+		t.Errorf("expected wait of %s for the released slot, got %s", time.Second, wait)
+	}
+}
+
+func TestAdmissionUnknownStatus(t *testing.T) {
+	clock := newFakeClock()
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	admission := NewAdmissionController(apiMutex, 0, clock)
+
+	for i := 0; i < 3; i++ {
+		if wait, _ := admission.Reserve(http.MethodGet, "/api/v1/users"); wait != 0 {
+			t.Errorf("request %d: expected no wait for unknown status, got %s", i, wait)
+		}
+	}
+}
+
+func TestAdmissionCapsInFlight(t *testing.T) {
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	admission := NewAdmissionController(apiMutex, 2, newFakeClock())
+	path := "/api/v1/groups"
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := admission.Acquire(context.Background(), http.MethodGet, path)
+		if err != nil {
+			t.Fatalf("request %d: didn't expect error, got %+v", i, err)
+		}
+		releases = append(releases, release)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := admission.Acquire(ctx, http.MethodGet, path); err != context.Canceled {
+		t.Fatalf("expected %v error when over the in flight cap, got %+v", context.Canceled, err)
+	}
+
+	// other buckets are not affected
+	if _, err := admission.Acquire(context.Background(), http.MethodGet, "/api/v1/users"); err != nil {
+		t.Fatalf("didn't expect error for another bucket, got %+v", err)
+	}
+
+	releases[0]()
+	if _, err := admission.Acquire(context.Background(), http.MethodGet, path); err != nil {
+		t.Fatalf("didn't expect error after release, got %+v", err)
+	}
+}
+
+func TestGovernedTransportPacesWithClock(t *testing.T) {
+	clock := newFakeClock()
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	transport := NewGovernedTransport(nil, apiMutex, 0, hclog.NewNullLogger())
+	transport.clock = clock
+	transport.admission = NewAdmissionController(apiMutex, 0, clock)
+	path := "/api/v1/apps"
+
+	start := clock.Now()
+	apiMutex.Update(http.MethodGet, path, 100, 80, start.Unix()+30)
+	for i := 0; i < 3; i++ {
+		if err := transport.preRequestHook(context.Background(), http.MethodGet, path); err != nil {
+			t.Fatalf("request %d: didn't expect error, got %+v", i, err)
+		}
+	}
+	if elapsed := clock.Now().Sub(start); elapsed != 2*time.Second {
+		t.Errorf("expected three requests to be paced over 2s, took %s", elapsed)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	for parallelism, expected := range map[int]int{0: 10, 1: 10, 4: 40} {
+		if got := MaxInFlight(parallelism); got != expected {
+			t.Errorf("parallelism %d: expected %d requests in flight, got %d", parallelism, expected, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"sync"
+)
+
+// AIMDLimiter limits the number of requests in flight with additive increase,
+// multiplicative decrease. Each concurrent rate limit violation halves the
+// limit and each successful request grows it by one over the course of a full
+// limit's worth of requests.
+type AIMDLimiter struct {
+	lock     sync.Mutex
+	max      int
+	limit    float64
+	inFlight int
+	changed  chan struct{}
+}
+
+// NewAIMDLimiter returns an AIMD limiter capped at max requests in flight. A
+// max of zero or less doesn't limit requests until the first violation.
+func NewAIMDLimiter(max int) *AIMDLimiter {
+	return &AIMDLimiter{
+		max:     max,
+		limit:   float64(max),
+		changed: make(chan struct{}),
+	}
+}
+
+// Acquire blocks until the number of requests in flight is under the limit,
+// or the context is done. The returned function releases the acquired place.
+func (l *AIMDLimiter) Acquire(ctx context.Context) (func(), error) {
+	for {
+		l.lock.Lock()
+		if l.limit <= 0 || l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.lock.Unlock()
+			var once sync.Once
+			return func() { once.Do(l.release) }, nil
+		}
+		changed := l.changed
+		l.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Success additively increases the limit.
+func (l *AIMDLimiter) Success() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.limit <= 0 {
+		return
+	}
+	before := int(l.limit)
+	l.limit += 1 / l.limit
+	if l.max > 0 && l.limit > float64(l.max) {
+		l.limit = float64(l.max)
+	}
+	if int(l.limit) > before {
+		l.broadcast()
+	}
+}
+
+// Backoff multiplicatively decreases the limit, an unbounded limiter is
+// bounded at half of what is currently in flight.
+func (l *AIMDLimiter) Backoff() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	current := l.limit
+	if current <= 0 {
+		current = float64(l.inFlight)
+	}
+	l.limit = current / 2
+	if l.limit < 1 {
+		l.limit = 1
+	}
+}
+
+// Limit returns the current limit, zero when unbounded.
+func (l *AIMDLimiter) Limit() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return int(l.limit)
+}
+
+func (l *AIMDLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.inFlight--
+	l.broadcast()
+}
+
+func (l *AIMDLimiter) broadcast() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
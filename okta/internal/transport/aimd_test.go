@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAIMDLimiter(t *testing.T) {
+	limiter := NewAIMDLimiter(4)
+	if got := limiter.Limit(); got != 4 {
+		t.Fatalf("expected a limit of 4, got %d", got)
+	}
+
+	limiter.Backoff()
+	if got := limiter.Limit(); got != 2 {
+		t.Errorf("expected the limit to be halved to 2, got %d", got)
+	}
+	limiter.Backoff()
+	limiter.Backoff()
+	if got := limiter.Limit(); got != 1 {
+		t.Errorf("expected the limit to not go below 1, got %d", got)
+	}
+
+	// additive increase, a full limit's worth of successes grows it by one
+	limiter.Success()
+	if got := limiter.Limit(); got != 2 {
+		t.Errorf("expected the limit to grow to 2, got %d", got)
+	}
+	for i := 0; i < 10; i++ {
+		limiter.Success()
+	}
+	if got := limiter.Limit(); got != 4 {
+		t.Errorf("expected the limit to be capped at 4, got %d", got)
+	}
+
+	limiter.Backoff()
+	limiter.Backoff()
+	release, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("failed to acquire: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected to block over the limit, got %v", err)
+	}
+	release()
+	release, err = limiter.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("failed to acquire after a release: %v", err)
+	}
+	release()
+}
+
+func TestAIMDLimiterUnbounded(t *testing.T) {
+	limiter := NewAIMDLimiter(0)
+	var releases []func()
+	for i := 0; i < 8; i++ {
+		release, err := limiter.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("failed to acquire: %v", err)
+		}
+		releases = append(releases, release)
+	}
+	if got := limiter.Limit(); got != 0 {
+		t.Errorf("expected the limiter to be unbounded, got %d", got)
+	}
+	limiter.Backoff()
+	if got := limiter.Limit(); got != 4 {
+		t.Errorf("expected the first violation to bound the limiter at half of what is in flight, got %d", got)
+	}
+	for _, release := range releases {
+		release()
+	}
+}
@@ -0,0 +1,26 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/okta/terraform-provider-okta/sdk/cache"
+)
+
+// CacheInvalidationMiddleware invalidates the response cache for every
+// mutating request that passes through the pipeline. The v2 request executor
+// serves cached GETs itself, but the v3 and supplement clients can mutate the
+// same resources. The cache is invalidated before the request, and again after
+// it, in case a concurrent read cached the resource while it was changing.
+func CacheInvalidationMiddleware(c cache.Cache) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return next.RoundTrip(req)
+			}
+			cache.Invalidate(c, req)
+			resp, err := next.RoundTrip(req)
+			cache.Invalidate(c, req)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package transport
+
+import (
+	"net/http"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// CoalescingTransport merges identical GETs that are in flight at the same
+// time into a single call of the base round tripper. It is for clients, like
+// the v3 Okta client, that don't coalesce requests themselves the way the v2
+// request executor does.
+type CoalescingTransport struct {
+	base      http.RoundTripper
+	coalescer *sdk.RequestCoalescer
+}
+
+// NewCoalescingTransport returns a coalescing transport on top of base.
+func NewCoalescingTransport(base http.RoundTripper) *CoalescingTransport {
+	return &CoalescingTransport{
+		base:      base,
+		coalescer: sdk.NewRequestCoalescer(),
+	}
+}
+
+// RoundTrip makes the request, or waits for an identical one in flight, and
+// returns a copy of the response.
+func (t *CoalescingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.coalescer.Do(req.Context(), sdk.CoalesceKey(req), func() (*http.Response, error) {
+		return t.base.RoundTrip(req)
+	})
+}
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+const (
+	dpopHeader      = "DPoP"
+	dpopNonceHeader = "DPoP-Nonce"
+	useDPoPNonce    = "use_dpop_nonce"
+)
+
+// DPoPTransport binds the access tokens of the private key flow to the
+// prover's key. Token requests carry a DPoP proof, and requests authorized
+// with a DPoP bound token have their Authorization header switched to the
+// DPoP scheme and carry a proof of the token. A request Okta turns down with
+// use_dpop_nonce is made once more with the nonce it handed out. The v2 and v3
+// Okta clients both send their tokens as Bearer tokens through the shared
+// pipeline, so the transport remembers which tokens Okta issued as DPoP ones.
+type DPoPTransport struct {
+	base   http.RoundTripper
+	prover *sdk.DPoPProver
+	lock   sync.Mutex
+	tokens map[string]struct{}
+}
+
+// NewDPoPTransport returns a DPoP transport of the prover.
+func NewDPoPTransport(base http.RoundTripper, prover *sdk.DPoPProver) *DPoPTransport {
+	return &DPoPTransport{
+		base:   base,
+		prover: prover,
+		tokens: map[string]struct{}{},
+	}
+}
+
+// RoundTrip proves token requests and requests of DPoP bound tokens, other
+// requests pass straight through.
+func (t *DPoPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isTokenRequest(req) {
+		resp, err := t.roundTrip(req, "")
+		if err == nil && resp.StatusCode == http.StatusOK {
+			t.recordToken(resp)
+		}
+		return resp, err
+	}
+	token, bound := t.boundToken(req)
+	if !bound {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "DPoP "+token)
+	return t.roundTrip(req, token)
+}
+
+// roundTrip makes the request with a proof, and makes it again with a new
+// proof if Okta asks for a nonce it hadn't been given.
+func (t *DPoPTransport) roundTrip(req *http.Request, token string) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		proof, err := t.prover.Proof(req.Method, req.URL, token)
+		if err != nil {
+			return nil, err
+		}
+		nonce := t.prover.Nonce(req.URL)
+		attemptReq := req.Clone(req.Context())
+		attemptReq.Header.Set(dpopHeader, proof)
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return resp, err
+		}
+		newNonce := resp.Header.Get(dpopNonceHeader)
+		if newNonce != "" {
+			t.prover.SetNonce(req.URL, newNonce)
+		}
+		if attempt > 0 || newNonce == "" || newNonce == nonce || !isUseDPoPNonce(resp) {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+	}
+}
+
+// boundToken returns the access token of the request if it is DPoP bound. A
+// request already on the DPoP scheme is bound.
+func (t *DPoPTransport) boundToken(req *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !found {
+		return "", false
+	}
+	if strings.EqualFold(scheme, "DPoP") {
+		return token, true
+	}
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, bound := t.tokens[token]
+	return token, bound
+}
+
+// recordToken remembers the access token of a token response if Okta issued
+// it as a DPoP token, the response body is restored after it is inspected.
+func (t *DPoPTransport) recordToken(resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	bodyBytes, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return
+	}
+	var accessToken sdk.RequestAccessToken
+	if err := json.Unmarshal(bodyBytes, &accessToken); err != nil {
+		return
+	}
+	if accessToken.AccessToken == "" || !strings.EqualFold(accessToken.TokenType, "DPoP") {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.tokens[accessToken.AccessToken] = struct{}{}
+}
+
+// isUseDPoPNonce reports if Okta turned down the request for a missing or
+// stale nonce, the token endpoint answers with an OAuth error and the
+// resource server with a WWW-Authenticate challenge.
+func isUseDPoPNonce(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		return oauthError(resp) == useDPoPNonce
+	case http.StatusUnauthorized:
+		return strings.Contains(resp.Header.Get("WWW-Authenticate"), useDPoPNonce) || oauthError(resp) == useDPoPNonce
+	}
+	return false
+}
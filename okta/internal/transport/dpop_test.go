@@ -0,0 +1,134 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestDPoPTransport(t *testing.T) {
+	prover, err := sdk.NewDPoPProver()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var proofs []sdk.DPoPProofClaims
+	var authorizations []string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		authorizations = append(authorizations, req.Header.Get("Authorization"))
+		proof := req.Header.Get("DPoP")
+		if proof == "" {
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		}
+		jws, err := jose.ParseSigned(proof)
+		if err != nil {
+			t.Fatalf("expected a DPoP proof, got %+v", err)
+		}
+		if typ := jws.Signatures[0].Header.ExtraHeaders["typ"]; typ != "dpop+jwt" {
+			t.Errorf("expected proof of type dpop+jwt, got %v", typ)
+		}
+		parsed, _ := jwt.ParseSigned(proof)
+		var claims sdk.DPoPProofClaims
+		if err := parsed.Claims(jws.Signatures[0].Header.JSONWebKey, &claims); err != nil {
+			t.Fatalf("expected the proof to verify with its embedded key, got %+v", err)
+		}
+		proofs = append(proofs, claims)
+
+		if strings.HasSuffix(req.URL.Path, "/v1/token") {
+			if claims.Nonce != "token-nonce" {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Header:     http.Header{"Dpop-Nonce": {"token-nonce"}},
+					Body:       io.NopCloser(strings.NewReader(`{"error":"use_dpop_nonce","error_description":"Authorization server requires nonce in DPoP proof."}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"token_type":"DPoP","access_token":"token","expires_in":3600}`)),
+			}, nil
+		}
+		if claims.Nonce != "api-nonce" {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Header: http.Header{
+					"Dpop-Nonce":       {"api-nonce"},
+					"Www-Authenticate": {`DPoP error="use_dpop_nonce", error_description="Resource server requires nonce in DPoP proof"`},
+				},
+				Body: io.NopCloser(strings.NewReader(``)),
+			}, nil
+		}
+		body := []byte{}
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(string(body)))}, nil
+	})
+	rt := NewDPoPTransport(base, prover)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.okta.com/oauth2/v1/token?grant_type=client_credentials&client_assertion=assertion", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the token request to succeed with the nonce, got %d", resp.StatusCode)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), `"access_token":"token"`) {
+		t.Errorf("expected the token response body to be restored, got %q", body)
+	}
+	if len(proofs) != 2 || proofs[1].HTTPMethod != http.MethodPost || proofs[1].HTTPURI != "https://example.okta.com/oauth2/v1/token" {
+		t.Errorf("expected a second token proof of POST https://example.okta.com/oauth2/v1/token, got %+v", proofs)
+	}
+	if proofs[0].ID == proofs[1].ID {
+		t.Errorf("expected each proof to have its own jti")
+	}
+
+	// the DPoP bound token is switched to the DPoP scheme and its proof
+	// carries the token hash
+	proofs, authorizations = nil, nil
+	req, _ = http.NewRequest(http.MethodPost, "https://example.okta.com/api/v1/users?activate=false", strings.NewReader(`{"profile":{}}`))
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err = rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != http.StatusOK || string(body) != `{"profile":{}}` {
+		t.Errorf("expected the request to be made again with its body and the nonce, got %d %q", resp.StatusCode, body)
+	}
+	sum := sha256.Sum256([]byte("token"))
+	if len(proofs) != 2 || proofs[1].AccessTokenHash != base64.RawURLEncoding.EncodeToString(sum[:]) || proofs[1].HTTPURI != "https://example.okta.com/api/v1/users" {
+		t.Errorf("expected a second proof of the token for https://example.okta.com/api/v1/users, got %+v", proofs)
+	}
+	if authorizations[1] != "DPoP token" {
+		t.Errorf("expected the DPoP authorization scheme, got %q", authorizations[1])
+	}
+
+	// the nonce is kept for later requests, tokens Okta didn't bind pass
+	// straight through
+	proofs, authorizations = nil, nil
+	req, _ = http.NewRequest(http.MethodGet, "https://example.okta.com/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, "https://example.okta.com/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "Bearer other")
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	if len(proofs) != 1 {
+		t.Errorf("expected a single proof with the kept nonce, got %d", len(proofs))
+	}
+	if authorizations[1] != "Bearer other" {
+		t.Errorf("expected the unbound token to keep the Bearer scheme, got %q", authorizations[1])
+	}
+}
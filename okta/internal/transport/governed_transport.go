@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+	"github.com/okta/terraform-provider-okta/sdk"
 )
 
 const (
@@ -19,21 +20,26 @@ const (
 )
 
 type GovernedTransport struct {
-	base     http.RoundTripper
-	apiMutex *apimutex.APIMutex
-	logger   hclog.Logger
+	base      http.RoundTripper
+	apiMutex  *apimutex.APIMutex
+	admission *AdmissionController
+	clock     Clock
+	logger    hclog.Logger
 }
 
 // NewGovernedTransport returns a governed transport that relies on pre- and post-
-// requests from the http round tripper. The pre request consults the api mutex
-// to determine if sleeping for the Okta API one minute bucket is called for.
-// The post request updates the information it is holding about the current api
-// rate limits.
-func NewGovernedTransport(base http.RoundTripper, apiMutex *apimutex.APIMutex, logger hclog.Logger) *GovernedTransport {
+// requests from the http round tripper. The pre request consults the admission
+// controller to pace the request within the Okta API one minute bucket and to
+// cap the requests in flight per bucket at maxInFlight. The post request
+// updates the information it is holding about the current api rate limits.
+func NewGovernedTransport(base http.RoundTripper, apiMutex *apimutex.APIMutex, maxInFlight int, logger hclog.Logger) *GovernedTransport {
+	clock := realClock{}
 	return &GovernedTransport{
-		base:     base,
-		apiMutex: apiMutex,
-		logger:   logger,
+		base:      base,
+		apiMutex:  apiMutex,
+		admission: NewAdmissionController(apiMutex, maxInFlight, clock),
+		clock:     clock,
+		logger:    logger,
 	}
 }
 
@@ -44,6 +50,13 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	if err := t.preRequestHook(req.Context(), req.Method, path); err != nil {
 		return nil, err
 	}
+	waitStart := t.clock.Now()
+	release, err := t.admission.Acquire(req.Context(), req.Method, path)
+	if err != nil {
+		return nil, err
+	}
+	recordThrottled(req.Context(), req.Method, path, t.clock.Now().Sub(waitStart))
+	defer release()
 
 	resp, err := t.base.RoundTrip(req)
 	// always attempt to save x-headers
@@ -56,20 +69,19 @@ func (t *GovernedTransport) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path string) error {
-	if t.apiMutex.HasCapacity(method, path) {
+	timeToSleep, cancel := t.admission.Reserve(method, path)
+	if timeToSleep <= 0 {
 		return nil
 	}
 
 	status := t.apiMutex.Status(method, path)
-	now := time.Now().Unix()
-	timeToSleep := status.Reset() - now
-
-	line := fmt.Sprintf("Throttling API requests; sleeping for %d seconds until rate limit reset (path class %q, bucket %q: %d remaining of %d total); current request \"%s %s\"",
-		timeToSleep,
+	line := fmt.Sprintf("Throttling API requests; sleeping for %s to pace requests within rate limit (path class %q, bucket %q: %d remaining of %d total, reset at %d); current request \"%s %s\"",
+		timeToSleep.Round(time.Millisecond),
 		t.apiMutex.Class(method, path),
 		t.apiMutex.Bucket(method, path),
 		status.Remaining(),
 		status.Limit(),
+		status.Reset(),
 		method,
 		path,
 	)
@@ -77,8 +89,10 @@ func (t *GovernedTransport) preRequestHook(ctx context.Context, method, path str
 
 	select {
 	case <-ctx.Done():
+		cancel()
 		return ctx.Err()
-	case <-time.NewTimer(time.Second * time.Duration(timeToSleep)).C:
+	case <-t.clock.After(timeToSleep):
+		recordThrottled(ctx, method, path, timeToSleep)
 		return nil
 	}
 }
@@ -87,6 +101,18 @@ func (t *GovernedTransport) postRequestHook(method, path string, resp *http.Resp
 	if resp == nil {
 		return
 	}
+	if sdk.IsConcurrencyLimitResponse(resp) {
+		// the x-headers refer to the concurrent limit, not the bucket's
+		t.admission.Backoff(method, path)
+		t.logger.Info(fmt.Sprintf("Concurrent rate limit exceeded; reducing requests in flight to %d (bucket %q); current request \"%s %s\"",
+			t.admission.InFlightLimit(method, path),
+			t.apiMutex.Bucket(method, path),
+			method,
+			path,
+		))
+		return
+	}
+	t.admission.Success(method, path)
 	reset, err := strconv.ParseInt(resp.Header.Get(X_RATE_LIMIT_RESET), 10, 64)
 	if err != nil {
 		t.logger.Warn(fmt.Sprintf("%q response header is missing or invalid, skipping postRequestHook: %+v", X_RATE_LIMIT_RESET, err))
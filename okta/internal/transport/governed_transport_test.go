@@ -3,8 +3,10 @@ package transport
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,7 +24,7 @@ func TestPreRequestHook(t *testing.T) {
 
 	client := &http.Client{}
 	apiMutex, _ := apimutex.NewAPIMutex(percentage)
-	transport := NewGovernedTransport(client.Transport, apiMutex, hclog.NewNullLogger())
+	transport := NewGovernedTransport(client.Transport, apiMutex, 0, hclog.NewNullLogger())
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -43,7 +45,7 @@ func TestPostRequestHook(t *testing.T) {
 	percentage := 10
 	client := &http.Client{}
 	apiMutex, _ := apimutex.NewAPIMutex(percentage)
-	transport := NewGovernedTransport(client.Transport, apiMutex, hclog.NewNullLogger())
+	transport := NewGovernedTransport(client.Transport, apiMutex, 0, hclog.NewNullLogger())
 
 	path := "/api/v1/apps"
 	request := http.Request{
@@ -69,3 +71,59 @@ func TestPostRequestHook(t *testing.T) {
 		t.Fatalf("expected %q api mutex status %+v to have reset %d, limit %d, and remaining %d values", path, status, reset, limit, remaining)
 	}
 }
+
+func TestPostRequestHookConcurrencyLimit(t *testing.T) {
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	transport := NewGovernedTransport(nil, apiMutex, 0, hclog.NewNullLogger())
+	path := "/api/v1/apps"
+	reset := time.Now().Unix() + 30
+	apiMutex.Update(http.MethodGet, path, 100, 80, reset)
+
+	headers := http.Header{}
+	headers.Add("x-rate-limit-limit", "600")
+	headers.Add("x-rate-limit-remaining", "540")
+	headers.Add("x-rate-limit-reset", fmt.Sprintf("%v", reset+30))
+	response := http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     headers,
+		Body:       io.NopCloser(strings.NewReader(`{"errorCode":"E0000047","errorSummary":"API call exceeded rate limit due to too many requests."}`)),
+	}
+
+	limit := transport.admission.InFlightLimit(http.MethodGet, path)
+	transport.postRequestHook(http.MethodGet, path, &response)
+	if got := transport.admission.InFlightLimit(http.MethodGet, path); got != limit/2 {
+		t.Errorf("expected in flight limit to be halved from %d, got %d", limit, got)
+	}
+	status := apiMutex.Status(http.MethodGet, path)
+	if status.Limit() != 100 || status.Remaining() != 80 || status.Reset() != reset {
+		t.Errorf("expected concurrent limit headers to not be accounted to the bucket, got %+v", status)
+	}
+}
+
+func TestPostRequestHookPerMinuteLimit(t *testing.T) {
+	apiMutex, _ := apimutex.NewAPIMutex(50)
+	transport := NewGovernedTransport(nil, apiMutex, 0, hclog.NewNullLogger())
+	// the catch-all bucket accounts for endpoints of different limits
+	path := "/api/v1/unknown"
+	reset := time.Now().Unix() + 30
+	apiMutex.Update(http.MethodGet, path, 100, 80, reset)
+
+	headers := http.Header{}
+	headers.Add("x-rate-limit-limit", "50")
+	headers.Add("x-rate-limit-remaining", "0")
+	headers.Add("x-rate-limit-reset", fmt.Sprintf("%v", reset))
+	response := http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     headers,
+		Body:       io.NopCloser(strings.NewReader(`{"errorCode":"E0000047","errorSummary":"API call exceeded rate limit due to too many requests."}`)),
+	}
+
+	limit := transport.admission.InFlightLimit(http.MethodGet, path)
+	transport.postRequestHook(http.MethodGet, path, &response)
+	if got := transport.admission.InFlightLimit(http.MethodGet, path); got != limit {
+		t.Errorf("expected per minute limit to not reduce the in flight limit of %d, got %d", limit, got)
+	}
+	if status := apiMutex.Status(http.MethodGet, path); status.Remaining() != 0 {
+		t.Errorf("expected per minute limit to be accounted to the bucket, got %+v", status)
+	}
+}
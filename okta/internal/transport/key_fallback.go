@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// KeyFallbackTransport retries token requests of the private key flow whose
+// client assertion Okta rejected with invalid_client, signed with the next key
+// of the fallback signer. The v2 and v3 Okta clients request their tokens
+// through the shared pipeline, so both fall back to the same key.
+type KeyFallbackTransport struct {
+	base   http.RoundTripper
+	signer *sdk.FallbackSigner
+	logger hclog.Logger
+}
+
+// NewKeyFallbackTransport returns a key fallback transport of the signer.
+func NewKeyFallbackTransport(base http.RoundTripper, signer *sdk.FallbackSigner, logger hclog.Logger) *KeyFallbackTransport {
+	return &KeyFallbackTransport{
+		base:   base,
+		signer: signer,
+		logger: logger,
+	}
+}
+
+// RoundTrip makes the request, and for a rejected client assertion makes it
+// again with each remaining key until one is accepted.
+func (t *KeyFallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isTokenRequest(req) {
+		return t.base.RoundTrip(req)
+	}
+	for {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || !isInvalidClient(resp) {
+			return resp, err
+		}
+		query := req.URL.Query()
+		assertion, ok := t.signer.Resign(query.Get("client_assertion"))
+		if !ok {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		t.logger.Warn(fmt.Sprintf("private key was rejected with invalid_client, falling back to private key %d", t.signer.Current()+1))
+
+		query.Set("client_assertion", assertion)
+		req = req.Clone(req.Context())
+		req.URL.RawQuery = query.Encode()
+	}
+}
+
+// isTokenRequest reports if the request is a token request of the private key
+// flow, both Okta clients send the client assertion in the query.
+func isTokenRequest(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/v1/token") &&
+		req.URL.Query().Get("client_assertion") != ""
+}
+
+// isInvalidClient reports if the response is an OAuth invalid_client error.
+func isInvalidClient(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	return oauthError(resp) == "invalid_client"
+}
+
+// oauthError returns the error code of an OAuth error response, the response
+// body is restored after it is inspected.
+func oauthError(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	bodyBytes, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return ""
+	}
+	var e sdk.Error
+	if err := json.Unmarshal(bodyBytes, &e); err != nil {
+		return ""
+	}
+	return e.ErrorMessage
+}
@@ -0,0 +1,83 @@
+package transport
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestKeyFallbackTransport(t *testing.T) {
+	var keys []sdk.PrivateKey
+	for i := 0; i < 3; i++ {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, sdk.PrivateKey{Key: rsaKey, Algorithm: jose.RS256})
+	}
+	signer, err := sdk.NewFallbackSigner(keys, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the last key is registered with the service app
+	registered := &keys[2].Key.(*rsa.PrivateKey).PublicKey
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		jws, err := jose.ParseSigned(req.URL.Query().Get("client_assertion"))
+		if err != nil {
+			t.Fatalf("expected a client assertion, got %+v", err)
+		}
+		if _, err := jws.Verify(registered); err != nil {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"error":"invalid_client","error_description":"The client_assertion signature is invalid."}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"token"}`)),
+		}, nil
+	})
+	rt := NewKeyFallbackTransport(base, signer, hclog.NewNullLogger())
+
+	assertion, err := sdk.CreateClientAssertion("https://example.okta.com", "clientID", signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{"grant_type": {"client_credentials"}, "client_assertion": {assertion}}
+	req, _ := http.NewRequest(http.MethodPost, "https://example.okta.com/oauth2/v1/token?"+query.Encode(), nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the token request to succeed with the third key, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 token requests, got %d", calls)
+	}
+	if signer.Current() != 2 {
+		t.Errorf("expected the signer to keep using the third key, got key %d", signer.Current()+1)
+	}
+
+	// other requests pass straight through
+	calls = 0
+	req, _ = http.NewRequest(http.MethodGet, "https://example.okta.com/api/v1/users/me?client_assertion="+assertion, nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single request, got %d", calls)
+	}
+}
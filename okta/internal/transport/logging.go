@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// RedactingLoggingTransport hands a logging transport, e.g. one of the
+// terraform plugin SDK's, redacted copies of the requests and responses, so
+// that secrets don't end up in the logs. The requests are made, and the
+// responses returned, as they are.
+type RedactingLoggingTransport struct {
+	logging  http.RoundTripper
+	redactor *Redactor
+}
+
+type loggedExchangeKey struct{}
+
+// loggedExchange is the request the logged copy stands for and its response.
+type loggedExchange struct {
+	req  *http.Request
+	resp *http.Response
+	err  error
+}
+
+// NewRedactingLoggingTransport returns a transport logging through the
+// logging transport the constructor returns for the inner round tripper.
+func NewRedactingLoggingTransport(base http.RoundTripper, redactor *Redactor, logging func(http.RoundTripper) http.RoundTripper) *RedactingLoggingTransport {
+	return &RedactingLoggingTransport{
+		logging:  logging(&loggedTransport{base: base, redactor: redactor}),
+		redactor: redactor,
+	}
+}
+
+// RoundTrip logs a redacted copy of the request, makes the request and logs a
+// redacted copy of its response.
+func (t *RedactingLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	exchange := &loggedExchange{req: req}
+	logged := req.Clone(context.WithValue(req.Context(), loggedExchangeKey{}, exchange))
+	logged.Header = t.redactor.Headers(req.Header)
+	if req.URL.RawQuery != "" {
+		if u, err := req.URL.Parse(t.redactor.URL(req.URL.String())); err == nil {
+			logged.URL = u
+		}
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		redacted := t.redactor.Body(req.Header.Get("Content-Type"), body)
+		logged.Body = io.NopCloser(bytes.NewReader(redacted))
+		logged.ContentLength = int64(len(redacted))
+	}
+	_, _ = t.logging.RoundTrip(logged)
+	return exchange.resp, exchange.err
+}
+
+// loggedTransport makes the request a logged copy stands for and hands the
+// logging transport a redacted copy of the response.
+type loggedTransport struct {
+	base     http.RoundTripper
+	redactor *Redactor
+}
+
+func (t *loggedTransport) RoundTrip(logged *http.Request) (*http.Response, error) {
+	exchange := logged.Context().Value(loggedExchangeKey{}).(*loggedExchange)
+	exchange.resp, exchange.err = t.base.RoundTrip(exchange.req)
+	if exchange.err != nil {
+		return nil, exchange.err
+	}
+	resp := exchange.resp
+	copied := *resp
+	copied.Header = t.redactor.Headers(resp.Header)
+	if resp.Body != nil && resp.Body != http.NoBody {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			exchange.resp, exchange.err = nil, err
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		redacted := t.redactor.Body(resp.Header.Get("Content-Type"), body)
+		copied.Body = io.NopCloser(bytes.NewReader(redacted))
+		copied.ContentLength = int64(len(redacted))
+	}
+	return &copied, nil
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"testing"
+)
+
+func TestRedactingLoggingTransport(t *testing.T) {
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		if req.Header.Get("Authorization") != "SSWS token" || !strings.Contains(string(body), `"value":"secret"`) {
+			t.Errorf("expected the request to be made as it is, got %v %s", req.Header, body)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"credentials":{"oauthClient":{"client_secret":"secret"}}}`)),
+			Request:    req,
+		}, nil
+	})
+	var logged []string
+	logging := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			dump, _ := httputil.DumpRequestOut(req, true)
+			logged = append(logged, string(dump))
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return resp, err
+			}
+			dump, _ = httputil.DumpResponse(resp, true)
+			logged = append(logged, string(dump))
+			return resp, nil
+		})
+	}
+	rt := NewRedactingLoggingTransport(base, OktaSecrets, logging)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://example.okta.com/api/v1/users", strings.NewReader(`{"credentials":{"password":{"value":"secret"}}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "SSWS token")
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), `"client_secret":"secret"`) {
+		t.Errorf("expected the response to be returned as it is, got %s", body)
+	}
+	if len(logged) != 2 {
+		t.Fatalf("expected the request and response to be logged, got %d", len(logged))
+	}
+	for _, dump := range logged {
+		if strings.Contains(dump, `"secret"`) || strings.Contains(dump, "SSWS token") {
+			t.Errorf("expected the logged secrets to be redacted, got %s", dump)
+		}
+	}
+	if !strings.Contains(logged[0], "SSWS REDACTED") {
+		t.Errorf("expected the logged authorization to keep its scheme, got %s", logged[0])
+	}
+}
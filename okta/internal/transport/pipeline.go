@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"net/http"
+)
+
+const (
+	StageAuth         = "auth"
+	StageKeyFallback  = "key-fallback"
+	StageCache        = "cache"
+	StageUsage        = "usage"
+	StageUsageAttempt = "usage-attempt"
+	StageGovernor     = "governor"
+	StageDPoP         = "dpop"
+	StageRetry        = "retry"
+	StageLogging      = "logging"
+)
+
+// Middleware wraps the next round tripper in the pipeline with additional
+// behavior.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Stage is a named middleware in a transport pipeline.
+type Stage struct {
+	Name       string
+	Middleware Middleware
+}
+
+// Pipeline is an ordered list of middleware stages that produces a single
+// round tripper. The first stage is the outermost, it sees the request first
+// and the response last. The v2 and v3 Okta clients share one pipeline so
+// that their calls are accounted for together.
+type Pipeline struct {
+	stages []Stage
+}
+
+// NewPipeline returns a pipeline of the given stages, stages with a nil
+// middleware are skipped.
+func NewPipeline(stages ...Stage) *Pipeline {
+	p := &Pipeline{}
+	for _, stage := range stages {
+		if stage.Middleware == nil {
+			continue
+		}
+		p.stages = append(p.stages, stage)
+	}
+	return p
+}
+
+// Stages returns the names of the pipeline's stages in order.
+func (p *Pipeline) Stages() []string {
+	names := make([]string, len(p.stages))
+	for i, stage := range p.stages {
+		names[i] = stage.Name
+	}
+	return names
+}
+
+// RoundTripper returns the round tripper of all the pipeline's stages wrapped
+// around the base round tripper.
+func (p *Pipeline) RoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rt := base
+	for i := len(p.stages) - 1; i >= 0; i-- {
+		rt = p.stages[i].Middleware(rt)
+	}
+	return rt
+}
+
+// HeaderTransport sets a header on outgoing requests that don't already have
+// a value for it.
+type HeaderTransport struct {
+	base  http.RoundTripper
+	name  string
+	value string
+}
+
+// NewHeaderTransport returns a header transport for the given header name and
+// value.
+func NewHeaderTransport(base http.RoundTripper, name, value string) *HeaderTransport {
+	return &HeaderTransport{
+		base:  base,
+		name:  name,
+		value: value,
+	}
+}
+
+// RoundTrip sets the header, if it is missing, on a clone of the request
+// before handing it to the next round tripper.
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.value == "" || req.Header.Get(t.name) != "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(t.name, t.value)
+	return t.base.RoundTrip(req)
+}
+
+// HeaderMiddleware returns a middleware of a header transport.
+func HeaderMiddleware(name, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return NewHeaderTransport(next, name, value)
+	}
+}
@@ -0,0 +1,71 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPipelineOrder(t *testing.T) {
+	var calls []string
+	stage := func(name string) Stage {
+		return Stage{
+			Name: name,
+			Middleware: func(next http.RoundTripper) http.RoundTripper {
+				return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name)
+					return next.RoundTrip(req)
+				})
+			},
+		}
+	}
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "base")
+		return httptest.NewRecorder().Result(), nil
+	})
+
+	pipeline := NewPipeline(
+		stage(StageAuth),
+		Stage{Name: StageGovernor},
+		stage(StageRetry),
+		stage(StageLogging),
+	)
+	expected := []string{StageAuth, StageRetry, StageLogging}
+	if !reflect.DeepEqual(pipeline.Stages(), expected) {
+		t.Fatalf("expected stages %v, got %v", expected, pipeline.Stages())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.okta.com/api/v1/users", nil)
+	if _, err := pipeline.RoundTripper(base).RoundTrip(req); err != nil {
+		t.Fatalf("didn't expect error, got %+v", err)
+	}
+	expected = append(expected, "base")
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+func TestHeaderTransport(t *testing.T) {
+	var got string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		got = req.Header.Get("Authorization")
+		return httptest.NewRecorder().Result(), nil
+	})
+	transport := NewHeaderTransport(base, "Authorization", "SSWS token")
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.okta.com/api/v1/users", nil)
+	_, _ = transport.RoundTrip(req)
+	if got != "SSWS token" {
+		t.Errorf("expected missing header to be set, got %q", got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("expected original request to be left untouched")
+	}
+
+	req.Header.Set("Authorization", "Bearer other")
+	_, _ = transport.RoundTrip(req)
+	if got != "Bearer other" {
+		t.Errorf("expected existing header to be kept, got %q", got)
+	}
+}
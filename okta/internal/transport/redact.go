@@ -0,0 +1,221 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the secrets the redactor scrubs.
+const Redacted = "REDACTED"
+
+// Redactor scrubs secrets out of requests and responses before they are
+// logged or saved to VCR cassettes. Secrets of JSON bodies are found by path,
+// secrets of form bodies and query strings by parameter name and secrets of
+// headers by header name.
+type Redactor struct {
+	paths   [][]string
+	params  map[string]bool
+	headers map[string]bool
+}
+
+// NewRedactor returns a redactor of the JSON paths, parameters and headers.
+// A path is the dotted keys of the secret, e.g. credentials.password.value,
+// where * matches any key. Arrays on a path are looked into as if each of
+// their elements was the value, so a path doesn't index them.
+func NewRedactor(paths, params, headers []string) *Redactor {
+	r := &Redactor{
+		params:  map[string]bool{},
+		headers: map[string]bool{},
+	}
+	for _, path := range paths {
+		r.paths = append(r.paths, strings.Split(path, "."))
+	}
+	for _, param := range params {
+		r.params[param] = true
+	}
+	for _, header := range headers {
+		r.headers[http.CanonicalHeaderKey(header)] = true
+	}
+	return r
+}
+
+// OktaSecrets is the redaction policy of the secrets of the Okta models, the
+// management API and the OAuth 2.0 endpoints.
+var OktaSecrets = NewRedactor(
+	[]string{
+		// users, and apps with shared credentials
+		"credentials.password.value",
+		"credentials.password.hash.value",
+		"credentials.password.hash.salt",
+		"credentials.recovery_question.answer",
+		"oldPassword.value",
+		"newPassword.value",
+		// apps
+		"credentials.oauthClient.client_secret",
+		"client_secret",
+		"secret_hash",
+		// identity providers
+		"protocol.credentials.client.client_secret",
+		// event and inline hooks
+		"channel.config.authScheme.value",
+		"channel.config.headers.value",
+		// authenticators
+		"provider.configuration.secretKey",
+		"provider.configuration.sharedSecret",
+		// CAPTCHAs, SMTP servers, domain certificates and log streams
+		"secretKey",
+		"password",
+		"privateKey",
+		"settings.token",
+		// tokens of the OAuth 2.0 endpoints
+		"access_token",
+		"id_token",
+		"refresh_token",
+	},
+	[]string{"client_assertion", "client_secret", "password", "refresh_token", "code_verifier"},
+	[]string{"Authorization", "Dpop", "Cookie", "Set-Cookie"},
+)
+
+// Header returns the redacted values of the header. The scheme of an
+// authorization stays readable.
+func (r *Redactor) Header(name string, values []string) []string {
+	if !r.headers[http.CanonicalHeaderKey(name)] {
+		return values
+	}
+	redacted := make([]string, len(values))
+	for i, value := range values {
+		redacted[i] = Redacted
+		if scheme, _, ok := strings.Cut(value, " "); ok && http.CanonicalHeaderKey(name) == "Authorization" {
+			redacted[i] = scheme + " " + Redacted
+		}
+	}
+	return redacted
+}
+
+// Headers returns a redacted copy of the headers.
+func (r *Redactor) Headers(headers http.Header) http.Header {
+	redacted := http.Header{}
+	for name, values := range headers {
+		redacted[name] = r.Header(name, values)
+	}
+	return redacted
+}
+
+// URL returns the URL with the secrets of its query string redacted.
+func (r *Redactor) URL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+	query, ok := r.form(u.RawQuery)
+	if !ok {
+		return rawURL
+	}
+	u.RawQuery = query
+	return u.String()
+}
+
+// Values returns a redacted copy of the form values.
+func (r *Redactor) Values(values url.Values) url.Values {
+	if values == nil {
+		return nil
+	}
+	redacted := url.Values{}
+	for name, v := range values {
+		redacted[name] = v
+		if r.params[name] {
+			redacted[name] = []string{Redacted}
+		}
+	}
+	return redacted
+}
+
+// Body returns the body with its secrets redacted, a JSON body or a form
+// body. A body of any other content type is returned as it is.
+func (r *Redactor) Body(contentType string, body []byte) []byte {
+	switch {
+	case len(body) == 0:
+		return body
+	case strings.Contains(contentType, "json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil || !r.JSON(value) {
+			return body
+		}
+		redacted, err := json.Marshal(value)
+		if err != nil {
+			return body
+		}
+		return redacted
+	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+		if form, ok := r.form(string(body)); ok {
+			return []byte(form)
+		}
+	}
+	return body
+}
+
+// JSON redacts the secrets of the decoded JSON value in place and reports if
+// there were any.
+func (r *Redactor) JSON(value interface{}) bool {
+	redacted := false
+	for _, path := range r.paths {
+		if redactPath(value, path) {
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+func redactPath(value interface{}, path []string) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		redacted := false
+		for _, element := range v {
+			if redactPath(element, path) {
+				redacted = true
+			}
+		}
+		return redacted
+	case map[string]interface{}:
+		redacted := false
+		for key, child := range v {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			if len(path) > 1 {
+				if redactPath(child, path[1:]) {
+					redacted = true
+				}
+				continue
+			}
+			if child != nil && child != Redacted {
+				v[key] = Redacted
+				redacted = true
+			}
+		}
+		return redacted
+	}
+	return false
+}
+
+// form redacts the secrets of the url encoded form and reports if there were
+// any.
+func (r *Redactor) form(encoded string) (string, bool) {
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return encoded, false
+	}
+	redacted := false
+	for name := range values {
+		if r.params[name] && values.Get(name) != Redacted {
+			values.Set(name, Redacted)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return encoded, false
+	}
+	return values.Encode(), true
+}
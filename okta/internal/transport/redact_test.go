@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestOktaSecrets(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			"application/json",
+			`{"profile":{"login":"a@example.com","password":"profile"},"credentials":{"password":{"value":"secret"},"recovery_question":{"question":"q","answer":"secret"}}}`,
+			`{"credentials":{"password":{"value":"REDACTED"},"recovery_question":{"answer":"REDACTED","question":"q"}},"profile":{"login":"a@example.com","password":"profile"}}`,
+		},
+		{
+			"application/json",
+			`{"credentials":{"password":{"hash":{"algorithm":"BCRYPT","salt":"secret","value":"secret"}}}}`,
+			`{"credentials":{"password":{"hash":{"algorithm":"BCRYPT","salt":"REDACTED","value":"REDACTED"}}}}`,
+		},
+		{
+			// a list of apps
+			"application/json; charset=UTF-8",
+			`[{"id":"0oa1","credentials":{"oauthClient":{"client_id":"0oa1","client_secret":"secret"}}},{"id":"0oa2"}]`,
+			`[{"credentials":{"oauthClient":{"client_id":"0oa1","client_secret":"REDACTED"}},"id":"0oa1"},{"id":"0oa2"}]`,
+		},
+		{
+			"application/json",
+			`{"channel":{"config":{"headers":[{"key":"x-api-key","value":"secret"}],"authScheme":{"type":"HEADER","key":"Authorization","value":"secret"}}}}`,
+			`{"channel":{"config":{"authScheme":{"key":"Authorization","type":"HEADER","value":"REDACTED"},"headers":[{"key":"x-api-key","value":"REDACTED"}]}}}`,
+		},
+		{
+			"application/json",
+			`{"protocol":{"credentials":{"client":{"client_id":"id","client_secret":"secret"}}}}`,
+			`{"protocol":{"credentials":{"client":{"client_id":"id","client_secret":"REDACTED"}}}}`,
+		},
+		{
+			// nothing to redact keeps the body as it is
+			"application/json",
+			`{"profile":{"name":"testAcc"}, "type":"OKTA_GROUP"}`,
+			`{"profile":{"name":"testAcc"}, "type":"OKTA_GROUP"}`,
+		},
+		{
+			"application/x-www-form-urlencoded",
+			`grant_type=client_credentials&client_secret=secret&scope=okta.users.read`,
+			`client_secret=REDACTED&grant_type=client_credentials&scope=okta.users.read`,
+		},
+		{
+			"text/plain",
+			`client_secret=secret`,
+			`client_secret=secret`,
+		},
+	}
+	for _, test := range tests {
+		if redacted := string(OktaSecrets.Body(test.contentType, []byte(test.body))); redacted != test.expected {
+			t.Errorf("expected %s to be redacted to %s, got %s", test.body, test.expected, redacted)
+		}
+	}
+
+	redactedURL := OktaSecrets.URL("https://example.okta.com/oauth2/v1/token?client_assertion=assertion&grant_type=client_credentials")
+	if u, _ := url.Parse(redactedURL); u.Query().Get("client_assertion") != Redacted || u.Query().Get("grant_type") != "client_credentials" {
+		t.Errorf("expected the client assertion of the query to be redacted, got %s", redactedURL)
+	}
+
+	headers := OktaSecrets.Headers(http.Header{"Authorization": {"SSWS token"}, "Dpop": {"proof"}, "Accept": {"application/json"}})
+	if headers.Get("Authorization") != "SSWS REDACTED" || headers.Get("Dpop") != Redacted || headers.Get("Accept") != "application/json" {
+		t.Errorf("expected the authorization and DPoP headers to be redacted, got %v", headers)
+	}
+	if values := OktaSecrets.Values(url.Values{"client_assertion": {"assertion"}, "scope": {"okta.users.read"}}); values.Get("client_assertion") != Redacted || !strings.Contains(values.Get("scope"), "okta") {
+		t.Errorf("expected the client assertion of the form to be redacted, got %v", values)
+	}
+}
@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// UsageCounts are the API usage counters reported for an endpoint class, a
+// rate limit bucket, and the whole run.
+type UsageCounts struct {
+	// Requests is the number of requests made, each counted once however
+	// often it was retried
+	Requests int `json:"requests"`
+	// Retries is the number of attempts that were retries of a request
+	Retries int `json:"retries"`
+	// RateLimited is the number of 429 responses
+	RateLimited int `json:"rate_limited"`
+	// ConcurrencyLimited is the number of 429 responses for exceeding the
+	// concurrent rate limit, they are also counted as rate limited
+	ConcurrencyLimited int `json:"concurrency_limited"`
+	// Errors is the number of transport errors and 5xx responses
+	Errors int `json:"errors"`
+	// ThrottledSeconds is the time requests spent waiting on the governor
+	ThrottledSeconds float64 `json:"throttled_seconds"`
+}
+
+func (c *UsageCounts) add(o UsageCounts) {
+	c.Requests += o.Requests
+	c.Retries += o.Retries
+	c.RateLimited += o.RateLimited
+	c.ConcurrencyLimited += o.ConcurrencyLimited
+	c.Errors += o.Errors
+	c.ThrottledSeconds += o.ThrottledSeconds
+}
+
+// ClassUsage is the API usage of one endpoint class.
+type ClassUsage struct {
+	Class  string `json:"class"`
+	Bucket string `json:"bucket"`
+	UsageCounts
+}
+
+// BucketUsage is the API usage of one rate limit bucket.
+type BucketUsage struct {
+	Bucket string `json:"bucket"`
+	UsageCounts
+}
+
+// UsageReport is the API usage of a provider run.
+type UsageReport struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	UsageCounts
+	Buckets []BucketUsage `json:"buckets"`
+	Classes []ClassUsage  `json:"classes"`
+}
+
+// UsageRecorder counts API requests per endpoint class and rate limit bucket
+// of the api mutex. It has two middlewares, the request middleware marks each
+// request before retry and the attempt middleware counts every attempt of it
+// after retry. The Okta clients retry rate limited requests above the
+// transport, they mark their retries with the X-Okta-Retry-Count header.
+type UsageRecorder struct {
+	apiMutex *apimutex.APIMutex
+	started  time.Time
+	lock     sync.Mutex
+	classes  map[string]*ClassUsage
+}
+
+// retryCountHeader is the header the Okta clients mark their retries with.
+const retryCountHeader = "X-Okta-Retry-Count"
+
+type usageContextKey struct{}
+
+// usageRequest is the context value of a request passing through the
+// recorder's middlewares.
+type usageRequest struct {
+	recorder *UsageRecorder
+	lock     sync.Mutex
+	attempts int
+}
+
+// NewUsageRecorder returns a usage recorder that classifies requests with the
+// api mutex.
+func NewUsageRecorder(apiMutex *apimutex.APIMutex) *UsageRecorder {
+	return &UsageRecorder{
+		apiMutex: apiMutex,
+		started:  time.Now(),
+		classes:  map[string]*ClassUsage{},
+	}
+}
+
+// RequestMiddleware marks requests so that the attempt middleware can tell
+// retries apart, it goes before the retry stage.
+func (r *UsageRecorder) RequestMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := context.WithValue(req.Context(), usageContextKey{}, &usageRequest{recorder: r})
+			return next.RoundTrip(req.WithContext(ctx))
+		})
+	}
+}
+
+// AttemptMiddleware counts each attempt of a request and its outcome, it goes
+// after the retry stage.
+func (r *UsageRecorder) AttemptMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			retry := req.Header.Get(retryCountHeader) != ""
+			if ur, ok := req.Context().Value(usageContextKey{}).(*usageRequest); ok {
+				ur.lock.Lock()
+				ur.attempts++
+				if ur.attempts > 1 {
+					retry = true
+				}
+				ur.lock.Unlock()
+			}
+			counts := UsageCounts{Requests: 1}
+			if retry {
+				counts = UsageCounts{Retries: 1}
+			}
+
+			resp, err := next.RoundTrip(req)
+			switch {
+			case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+				counts.Errors = 1
+			case resp.StatusCode == http.StatusTooManyRequests:
+				counts.RateLimited = 1
+				if sdk.IsConcurrencyLimitResponse(resp) {
+					counts.ConcurrencyLimited = 1
+				}
+			}
+			r.record(req.Method, req.URL.Path, counts)
+			return resp, err
+		})
+	}
+}
+
+// recordThrottled accounts for time a request spent waiting to be admitted if
+// the request passed through a usage recorder.
+func recordThrottled(ctx context.Context, method, path string, d time.Duration) {
+	if ur, ok := ctx.Value(usageContextKey{}).(*usageRequest); ok && d > 0 {
+		ur.recorder.record(method, path, UsageCounts{ThrottledSeconds: d.Seconds()})
+	}
+}
+
+func (r *UsageRecorder) record(method, path string, counts UsageCounts) {
+	class := r.apiMutex.Class(method, path)
+	bucket := r.apiMutex.Bucket(method, path)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	usage, ok := r.classes[class]
+	if !ok {
+		usage = &ClassUsage{Class: class, Bucket: bucket}
+		r.classes[class] = usage
+	}
+	usage.add(counts)
+}
+
+// Report returns the usage so far, buckets and classes are sorted by number of
+// requests, most requested first.
+func (r *UsageRecorder) Report() UsageReport {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	report := UsageReport{
+		Started:  r.started,
+		Finished: time.Now(),
+		Buckets:  []BucketUsage{},
+		Classes:  []ClassUsage{},
+	}
+	buckets := map[string]*BucketUsage{}
+	for _, usage := range r.classes {
+		report.Classes = append(report.Classes, *usage)
+		report.add(usage.UsageCounts)
+		bucket, ok := buckets[usage.Bucket]
+		if !ok {
+			bucket = &BucketUsage{Bucket: usage.Bucket}
+			buckets[usage.Bucket] = bucket
+		}
+		bucket.add(usage.UsageCounts)
+	}
+	for _, bucket := range buckets {
+		report.Buckets = append(report.Buckets, *bucket)
+	}
+	sort.Slice(report.Classes, func(i, j int) bool {
+		if report.Classes[i].Requests != report.Classes[j].Requests {
+			return report.Classes[i].Requests > report.Classes[j].Requests
+		}
+		return report.Classes[i].Class < report.Classes[j].Class
+	})
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		if report.Buckets[i].Requests != report.Buckets[j].Requests {
+			return report.Buckets[i].Requests > report.Buckets[j].Requests
+		}
+		return report.Buckets[i].Bucket < report.Buckets[j].Bucket
+	})
+	return report
+}
+
+// WriteReport writes the usage report as JSON to the path.
+func (r *UsageRecorder) WriteReport(path string) error {
+	data, err := json.MarshalIndent(r.Report(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okta/terraform-provider-okta/okta/internal/apimutex"
+)
+
+func TestUsageRecorder(t *testing.T) {
+	apiMutex, _ := apimutex.NewAPIMutex(100)
+	recorder := NewUsageRecorder(apiMutex)
+
+	statuses := []int{http.StatusTooManyRequests, http.StatusOK, http.StatusOK, http.StatusInternalServerError}
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		recordThrottled(req.Context(), req.Method, req.URL.Path, 500*time.Millisecond)
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`)), Request: req}, nil
+	})
+	// retries once on a 429
+	retry := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+				return next.RoundTrip(req)
+			}
+			return resp, err
+		})
+	}
+	rt := NewPipeline(
+		Stage{Name: StageUsage, Middleware: recorder.RequestMiddleware()},
+		Stage{Name: StageRetry, Middleware: retry},
+		Stage{Name: StageUsageAttempt, Middleware: recorder.AttemptMiddleware()},
+	).RoundTripper(base)
+
+	for _, path := range []string{"/api/v1/users/00u1a2b3c4d5e6f7g8h9", "/api/v1/users/00u9h8g7f6e5d4c3b2a1", "/api/v1/groups"} {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.okta.com"+path, nil)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("didn't expect error, got %+v", err)
+		}
+	}
+
+	report := recorder.Report()
+	expected := UsageCounts{Requests: 3, Retries: 1, RateLimited: 1, Errors: 1, ThrottledSeconds: 2}
+	if report.UsageCounts != expected {
+		t.Errorf("expected totals %+v, got %+v", expected, report.UsageCounts)
+	}
+	if len(report.Classes) != 2 {
+		t.Fatalf("expected 2 classes, got %+v", report.Classes)
+	}
+	users := report.Classes[0]
+	if users.Class != "GET /api/v1/users/ID" || users.Bucket != "/api/v1/users/{id:.+}" || users.Requests != 2 || users.Retries != 1 {
+		t.Errorf("unexpected users class usage %+v", users)
+	}
+	if len(report.Buckets) != 2 || report.Buckets[0].Bucket != "/api/v1/users/{id:.+}" {
+		t.Errorf("expected users bucket first, got %+v", report.Buckets)
+	}
+
+	path := filepath.Join(t.TempDir(), "usage.json")
+	if err := recorder.WriteReport(path); err != nil {
+		t.Fatalf("didn't expect error writing report, got %+v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("didn't expect error reading report, got %+v", err)
+	}
+	var written UsageReport
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("expected a JSON report, got %+v", err)
+	}
+	if written.Requests != 3 || len(written.Classes) != 2 {
+		t.Errorf("unexpected written report %s", data)
+	}
+}
+
+func TestUsageRecorderClientRetries(t *testing.T) {
+	apiMutex, _ := apimutex.NewAPIMutex(100)
+	recorder := NewUsageRecorder(apiMutex)
+
+	statuses := []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK}
+	rt := NewPipeline(
+		Stage{Name: StageUsage, Middleware: recorder.RequestMiddleware()},
+		Stage{Name: StageUsageAttempt, Middleware: recorder.AttemptMiddleware()},
+	).RoundTripper(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(`{}`)), Request: req}, nil
+	}))
+
+	// the Okta clients retry above the transport, each attempt passes
+	// through the pipeline anew
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.okta.com/api/v1/groups", nil)
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			req.Header.Set("X-Okta-Retry-Count", strconv.Itoa(i))
+		}
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("didn't expect error, got %+v", err)
+		}
+	}
+
+	expected := UsageCounts{Requests: 1, Retries: 2, RateLimited: 2}
+	if report := recorder.Report(); report.UsageCounts != expected {
+		t.Errorf("expected totals %+v, got %+v", expected, report.UsageCounts)
+	}
+}
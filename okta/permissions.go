@@ -0,0 +1,449 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+// Permission families, named after the Okta OAuth 2.0 scopes for the
+// management API. A family is read with its okta.<family>.read scope and
+// written with its okta.<family>.manage scope.
+const (
+	permApps                 = "okta.apps"
+	permAuthenticators       = "okta.authenticators"
+	permAuthorizationServers = "okta.authorizationServers"
+	permBehaviors            = "okta.behaviors"
+	permBrands               = "okta.brands"
+	permCaptchas             = "okta.captchas"
+	permDomains              = "okta.domains"
+	permEventHooks           = "okta.eventHooks"
+	permFactors              = "okta.factors"
+	permGroups               = "okta.groups"
+	permIdps                 = "okta.idps"
+	permInlineHooks          = "okta.inlineHooks"
+	permLinkedObjects        = "okta.linkedObjects"
+	permNetworkZones         = "okta.networkZones"
+	permOrgs                 = "okta.orgs"
+	permPolicies             = "okta.policies"
+	permProfileMappings      = "okta.profileMappings"
+	permRoles                = "okta.roles"
+	permSchemas              = "okta.schemas"
+	permTemplates            = "okta.templates"
+	permThreatInsights       = "okta.threatInsights"
+	permTrustedOrigins       = "okta.trustedOrigins"
+	permUsers                = "okta.users"
+	permUserTypes            = "okta.userTypes"
+	// permNone is the family of the data sources that don't call Okta.
+	permNone = "none"
+)
+
+// resourcePermissions is the permission family each resource and data source
+// needs, resources read and write it and data sources only read it.
+var resourcePermissions = map[string]string{
+	adminRoleCustom:               permRoles,
+	adminRoleCustomAssignments:    permRoles,
+	adminRoleTargets:              permRoles,
+	app:                           permApps,
+	appActiveSigningKey:           permApps,
+	appAutoLogin:                  permApps,
+	appBasicAuth:                  permApps,
+	appBookmark:                   permApps,
+	appCSR:                        permApps,
+	appFeatures:                   permApps,
+	appGroupAssignment:            permApps,
+	appGroupAssignments:           permApps,
+	appMetadataSaml:               permApps,
+	appOAuth:                      permApps,
+	appOAuthAPIScope:              permApps,
+	appOAuthAPIScopes:             permApps,
+	appOAuthPostLogoutRedirectURI: permApps,
+	appOAuthRedirectURI:           permApps,
+	appOAuthSecret:                permApps,
+	appProvisioningConnection:     permApps,
+	appSaml:                       permApps,
+	appSamlAppSettings:            permApps,
+	appSecurePasswordStore:        permApps,
+	appSharedCredentials:          permApps,
+	appSigningKey:                 permApps,
+	appSignOnPolicy:               permPolicies,
+	appSignOnPolicyRule:           permPolicies,
+	appSwa:                        permApps,
+	appThreeField:                 permApps,
+	appUser:                       permApps,
+	appUserAssignments:            permApps,
+	appUserBaseSchemaProperty:     permSchemas,
+	appUserSchemaProperty:         permSchemas,
+	authenticator:                 permAuthenticators,
+	authServer:                    permAuthorizationServers,
+	authServerClaim:               permAuthorizationServers,
+	authServerClaimDefault:        permAuthorizationServers,
+	authServerClaims:              permAuthorizationServers,
+	authServerDefault:             permAuthorizationServers,
+	authServerPolicy:              permAuthorizationServers,
+	authServerPolicyRule:          permAuthorizationServers,
+	authServerScope:               permAuthorizationServers,
+	authServerScopes:              permAuthorizationServers,
+	behavior:                      permBehaviors,
+	behaviors:                     permBehaviors,
+	brand:                         permBrands,
+	brands:                        permBrands,
+	captcha:                       permCaptchas,
+	captchaOrgWideSettings:        permCaptchas,
+	defaultPolicy:                 permPolicies,
+	domain:                        permDomains,
+	domainCertificate:             permDomains,
+	domainVerification:            permDomains,
+	emailCustomization:            permBrands,
+	emailCustomizations:           permBrands,
+	emailSender:                   permOrgs,
+	emailSenderVerification:       permOrgs,
+	emailTemplate:                 permBrands,
+	emailTemplates:                permBrands,
+	eventHook:                     permEventHooks,
+	eventHookVerification:         permEventHooks,
+	expressionLint:                permNone,
+	factor:                        permFactors,
+	factorTotp:                    permFactors,
+	group:                         permGroups,
+	groupEveryone:                 permGroups,
+	groupMemberships:              permGroups,
+	groupRole:                     permRoles,
+	groupRule:                     permGroups,
+	groups:                        permGroups,
+	groupSchemaProperty:           permSchemas,
+	idpMetadataSaml:               permIdps,
+	idpOidc:                       permIdps,
+	idpSaml:                       permIdps,
+	idpSamlKey:                    permIdps,
+	idpSocial:                     permIdps,
+	inlineHook:                    permInlineHooks,
+	linkDefinition:                permLinkedObjects,
+	linkValue:                     permUsers,
+	networkZone:                   permNetworkZones,
+	orgConfiguration:              permOrgs,
+	orgSupport:                    permOrgs,
+	policy:                        permPolicies,
+	policyMfa:                     permPolicies,
+	policyMfaDefault:              permPolicies,
+	policyPassword:                permPolicies,
+	policyPasswordDefault:         permPolicies,
+	policyProfileEnrollment:       permPolicies,
+	policyProfileEnrollmentApps:   permPolicies,
+	policyRuleIdpDiscovery:        permPolicies,
+	policyRuleMfa:                 permPolicies,
+	policyRulePassword:            permPolicies,
+	policyRuleProfileEnrollment:   permPolicies,
+	policyRuleSignOn:              permPolicies,
+	policySignOn:                  permPolicies,
+	profileMapping:                permProfileMappings,
+	rateLimiting:                  permOrgs,
+	resourceSet:                   permRoles,
+	roleSubscription:              permRoles,
+	securityNotificationEmails:    permOrgs,
+	templateSms:                   permTemplates,
+	theme:                         permBrands,
+	themes:                        permBrands,
+	threatInsightSettings:         permThreatInsights,
+	trustedOrigin:                 permTrustedOrigins,
+	trustedOrigins:                permTrustedOrigins,
+	user:                          permUsers,
+	userAdminRoles:                permRoles,
+	userBaseSchemaProperty:        permSchemas,
+	userFactorQuestion:            permUsers,
+	userGroupMemberships:          permGroups,
+	userProfileMappingSource:      permProfileMappings,
+	users:                         permUsers,
+	userSchemaProperty:            permSchemas,
+	userSecurityQuestions:         permUsers,
+	userType:                      permUserTypes,
+}
+
+// adminRoleScopes are the scopes equivalent to the standard admin roles. A
+// scope of "*" stands for every family. The CUSTOM role isn't listed, its
+// permissions are up to the org.
+var adminRoleScopes = map[string][]string{
+	"SUPER_ADMIN": {"*.manage"},
+	"ORG_ADMIN": {
+		"*.read", permApps + ".manage", permAuthenticators + ".manage", permBehaviors + ".manage",
+		permBrands + ".manage", permCaptchas + ".manage", permDomains + ".manage", permEventHooks + ".manage",
+		permFactors + ".manage", permGroups + ".manage", permIdps + ".manage", permInlineHooks + ".manage",
+		permLinkedObjects + ".manage", permNetworkZones + ".manage", permOrgs + ".manage", permPolicies + ".manage",
+		permProfileMappings + ".manage", permRoles + ".manage", permSchemas + ".manage", permTemplates + ".manage",
+		permThreatInsights + ".manage", permTrustedOrigins + ".manage", permUsers + ".manage", permUserTypes + ".manage",
+	},
+	"APP_ADMIN":                   {permApps + ".manage", permPolicies + ".read", permUsers + ".read", permGroups + ".read"},
+	"API_ACCESS_MANAGEMENT_ADMIN": {permAuthorizationServers + ".manage", permApps + ".manage", permGroups + ".read"},
+	"USER_ADMIN":                  {permUsers + ".manage", permGroups + ".manage"},
+	"GROUP_MEMBERSHIP_ADMIN":      {permGroups + ".manage", permUsers + ".read"},
+	"HELP_DESK_ADMIN":             {permUsers + ".read", permGroups + ".read", permFactors + ".read"},
+	"MOBILE_ADMIN":                {permUsers + ".read", permGroups + ".read", permApps + ".read"},
+	"READ_ONLY_ADMIN":             {"*.read"},
+	"REPORT_ADMIN":                {},
+}
+
+// capabilities are the permissions of the provider's credentials, discovered
+// from the caller's admin roles or the service app's granted scopes. Granted
+// scopes are exactly what Okta enforces, admin roles are an approximation.
+type capabilities struct {
+	source  string
+	scopes  map[string]bool
+	granted bool
+}
+
+// newCapabilities returns the capabilities of the granted scopes.
+func newCapabilities(source string, scopes []string) *capabilities {
+	c := &capabilities{
+		source: source,
+		scopes: map[string]bool{},
+	}
+	for _, scope := range scopes {
+		c.scopes[scope] = true
+	}
+	return c
+}
+
+// capabilitiesOfRoles returns the capabilities of the admin roles, or nil if
+// a role's permissions can't be told, e.g. for a custom role.
+func capabilitiesOfRoles(roles []string) *capabilities {
+	var scopes []string
+	for _, role := range roles {
+		roleScopes, ok := adminRoleScopes[role]
+		if !ok {
+			return nil
+		}
+		scopes = append(scopes, roleScopes...)
+	}
+	if len(roles) == 0 {
+		return newCapabilities("no admin roles", scopes)
+	}
+	sort.Strings(roles)
+	return newCapabilities("admin roles "+strings.Join(roles, ", "), scopes)
+}
+
+func (c *capabilities) canRead(family string) bool {
+	return c.scopes["*.read"] || c.scopes[family+".read"] || c.canManage(family)
+}
+
+func (c *capabilities) canManage(family string) bool {
+	return c.scopes["*.manage"] || c.scopes[family+".manage"]
+}
+
+// preflightScopes gets the access token of the private key flow up front, so
+// that scopes the service app wasn't granted fail here rather than an apply
+// failing midway with a 403. With permission_aware the capabilities of the
+// scopes Okta granted are set. A token that doesn't tell its scopes leaves
+// them unknown.
+func preflightScopes(c *Config) error {
+	granted, err := c.oktaClient.GetRequestExecutor().GrantedScopes()
+	if err != nil {
+		return fmt.Errorf("failed to get an access token for client_id %q with scopes %s: %w", c.clientID, strings.Join(c.scopes, ", "), err)
+	}
+	if len(granted) == 0 {
+		c.logger.Warn(fmt.Sprintf("the access token of client_id %q doesn't tell its granted scopes, they can't be checked", c.clientID))
+		return nil
+	}
+	c.grantedScopes = granted
+	c.scopesWarned = &sync.Map{}
+	if c.permissionAware {
+		c.capabilities = newCapabilities("granted scopes "+strings.Join(granted, ", "), granted)
+		c.capabilities.granted = true
+		c.logger.Info(fmt.Sprintf("permission_aware running with the %s", c.capabilities.source))
+	}
+	return nil
+}
+
+// warnMissingScope warns, once per type, when a resource or data source is
+// read or created whose scope wasn't granted to the access token. With
+// permission_aware its plan or read fails instead, see permissionDiff and
+// requireReadScope.
+func warnMissingScope(name string, write bool, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := fn(ctx, d, meta)
+		c, ok := meta.(*Config)
+		family := resourcePermissions[name]
+		if !ok || c.permissionAware || c.scopesWarned == nil || family == "" || family == permNone {
+			return diags
+		}
+		caps := newCapabilities("", c.grantedScopes)
+		var scope, does string
+		switch {
+		case write && !caps.canManage(family):
+			scope, does = family+".manage", "Creating, updating and deleting it fails"
+		case !write && !caps.canRead(family):
+			scope, does = family+".read", "Reading it fails"
+		default:
+			return diags
+		}
+		if _, warned := c.scopesWarned.LoadOrStore(name, true); warned {
+			return diags
+		}
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("The access token wasn't granted the %s scope of %s", scope, name),
+			Detail: fmt.Sprintf("Okta granted the scopes %s. %s with 403 Forbidden.",
+				strings.Join(c.grantedScopes, ", "), does),
+		})
+	}
+}
+
+// discoverCapabilities sets the capabilities of the admin roles of the
+// caller. The capabilities are left unknown, and nothing is held back, if
+// they can't be discovered.
+func discoverCapabilities(ctx context.Context, c *Config) {
+	me, _, err := c.oktaClient.User.GetUser(ctx, "me")
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("permission_aware can't discover the caller: %v", err))
+		return
+	}
+	assigned, _, err := c.oktaClient.User.ListAssignedRolesForUser(ctx, me.Id, nil)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("permission_aware can't discover the caller's admin roles: %v", err))
+		return
+	}
+	roles := make([]string, len(assigned))
+	for i, role := range assigned {
+		roles[i] = role.Type
+	}
+	c.capabilities = capabilitiesOfRoles(roles)
+	if c.capabilities == nil {
+		c.logger.Info(fmt.Sprintf("permission_aware can't tell the permissions of admin roles %s", strings.Join(roles, ", ")))
+		return
+	}
+	c.logger.Info(fmt.Sprintf("permission_aware running with the permissions of %s", c.capabilities.source))
+}
+
+func getCapabilitiesFromMetadata(meta interface{}) *capabilities {
+	config, ok := meta.(*Config)
+	if !ok {
+		return nil
+	}
+	return config.capabilities
+}
+
+// declarePermissions has the provider's resources fail a plan that writes
+// them without the permission to do so, and warn about the sub-objects their
+// reads skipped because they are forbidden. Those the granted scopes don't
+// cover warn about the missing scope when they are read or created.
+func declarePermissions(p *schema.Provider) {
+	for name, r := range p.ResourcesMap {
+		r.CustomizeDiff = permissionDiff(name, r.CustomizeDiff)
+		if r.CreateContext != nil {
+			r.CreateContext = warnMissingScope(name, true, warnForbiddenReads(name, r.CreateContext))
+		}
+		if r.ReadContext != nil {
+			r.ReadContext = requireReadScope(name, warnMissingScope(name, true, warnForbiddenReads(name, r.ReadContext)))
+		}
+		if r.UpdateContext != nil {
+			r.UpdateContext = warnForbiddenReads(name, r.UpdateContext)
+		}
+	}
+	for name, r := range p.DataSourcesMap {
+		if r.ReadContext != nil {
+			r.ReadContext = requireReadScope(name, warnMissingScope(name, false, warnForbiddenReads(name, r.ReadContext)))
+		}
+	}
+}
+
+// permissionDiff fails the plan of a resource that is created or updated
+// without the permission to write it, before any call to Okta is made.
+func permissionDiff(name string, next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		caps := getCapabilitiesFromMetadata(meta)
+		family := resourcePermissions[name]
+		if caps != nil && family != "" && (d.Id() == "" || len(d.GetChangedKeysPrefix("")) > 0) && !caps.canManage(family) {
+			return fmt.Errorf("%s can't be written with the current credentials, it needs the %s.manage scope or an admin role that grants it, the credentials have %s",
+				name, family, caps.source)
+		}
+		if next != nil {
+			return next(ctx, d, meta)
+		}
+		return nil
+	}
+}
+
+// requireReadScope fails the read of a resource or data source whose scope
+// wasn't granted to the access token, naming the missing scope.
+func requireReadScope(name string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		caps := getCapabilitiesFromMetadata(meta)
+		family := resourcePermissions[name]
+		if caps != nil && caps.granted && family != "" && family != permNone && !caps.canRead(family) {
+			return diag.Errorf("%s can't be read with the current credentials, it needs the %s.read or %s.manage scope, the credentials have %s",
+				name, family, family, caps.source)
+		}
+		return fn(ctx, d, meta)
+	}
+}
+
+type forbiddenReadsKey struct{}
+
+// forbiddenReads are the sub-objects a resource's operation skipped reading.
+type forbiddenReads struct {
+	lock  sync.Mutex
+	whats []string
+}
+
+func (f *forbiddenReads) add(what string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, w := range f.whats {
+		if w == what {
+			return
+		}
+	}
+	f.whats = append(f.whats, what)
+}
+
+// warnForbiddenReads adds a warning to the operation's diagnostics for each
+// sub-object it skipped reading.
+func warnForbiddenReads(name string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		forbidden := &forbiddenReads{}
+		diags := fn(context.WithValue(ctx, forbiddenReadsKey{}, forbidden), d, meta)
+		for _, what := range forbidden.whats {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%s skipped reading %s", name, what),
+				Detail:   fmt.Sprintf("The current credentials aren't permitted to read %s, it is left out of the state of %s.", what, name),
+			})
+		}
+		return diags
+	}
+}
+
+// skipForbiddenRead reports if the credentials can't read the permission
+// family, in which case reading the sub-object is skipped with a warning.
+func skipForbiddenRead(ctx context.Context, meta interface{}, family, what string) bool {
+	caps := getCapabilitiesFromMetadata(meta)
+	if caps == nil || caps.canRead(family) {
+		return false
+	}
+	recordForbiddenRead(ctx, meta, what, fmt.Sprintf("needs %s.read", family))
+	return true
+}
+
+// suppressErrorOnForbidden suppresses the 401 Unauthorized and 403 Forbidden
+// errors of reading a sub-object that the credentials, or the org's features,
+// don't allow, and skips the sub-object with a warning.
+func suppressErrorOnForbidden(ctx context.Context, what string, meta interface{}, resp *sdk.Response, err error) error {
+	if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+		recordForbiddenRead(ctx, meta, what, http.StatusText(resp.StatusCode))
+		return nil
+	}
+	return responseErr(resp, err)
+}
+
+func recordForbiddenRead(ctx context.Context, meta interface{}, what, reason string) {
+	logger(meta).Warn(fmt.Sprintf("Skipping %q: %s", what, reason))
+	if forbidden, ok := ctx.Value(forbiddenReadsKey{}).(*forbiddenReads); ok {
+		forbidden.add(what)
+	}
+}
@@ -0,0 +1,281 @@
+package okta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestResourcePermissions(t *testing.T) {
+	p := Provider()
+	for name := range p.ResourcesMap {
+		if resourcePermissions[name] == "" {
+			t.Errorf("resource %s doesn't declare its permission", name)
+		}
+	}
+	for name := range p.DataSourcesMap {
+		if resourcePermissions[name] == "" {
+			t.Errorf("data source %s doesn't declare its permission", name)
+		}
+	}
+}
+
+func TestResourcePermissionFamilies(t *testing.T) {
+	// the resources of each family are those Okta authorizes with its
+	// okta.<family>.* scopes
+	families := map[string][]string{
+		permApps:                 {app, appOAuthAPIScope, appUser},
+		permAuthenticators:       {authenticator},
+		permAuthorizationServers: {authServer, authServerPolicyRule},
+		permBehaviors:            {behavior},
+		permBrands:               {brand, theme, emailCustomization, emailCustomizations, emailTemplate, emailTemplates},
+		permCaptchas:             {captcha},
+		permDomains:              {domain, domainCertificate},
+		permEventHooks:           {eventHook},
+		permFactors:              {factor},
+		permGroups:               {group, groupRule, userGroupMemberships},
+		permIdps:                 {idpOidc, idpSaml},
+		permInlineHooks:          {inlineHook},
+		permLinkedObjects:        {linkDefinition},
+		permNetworkZones:         {networkZone},
+		permOrgs:                 {orgConfiguration, emailSender},
+		permPolicies:             {policyPassword, appSignOnPolicy},
+		permProfileMappings:      {profileMapping},
+		permRoles:                {adminRoleCustom, groupRole, userAdminRoles},
+		permSchemas:              {userSchemaProperty, groupSchemaProperty},
+		permTemplates:            {templateSms},
+		permThreatInsights:       {threatInsightSettings},
+		permTrustedOrigins:       {trustedOrigin},
+		permUsers:                {user, linkValue},
+		permUserTypes:            {userType},
+		permNone:                 {expressionLint},
+	}
+	for family, names := range families {
+		for _, name := range names {
+			if got := resourcePermissions[name]; got != family {
+				t.Errorf("expected %s to need %s, got %q", name, family, got)
+			}
+		}
+	}
+	for name, family := range resourcePermissions {
+		if _, ok := families[family]; !ok {
+			t.Errorf("%s needs unknown permission family %q", name, family)
+		}
+	}
+}
+
+func TestCapabilitiesOfRoles(t *testing.T) {
+	tests := []struct {
+		roles      []string
+		family     string
+		canRead    bool
+		canManage  bool
+		unknownCap bool
+	}{
+		{[]string{"SUPER_ADMIN"}, permAuthorizationServers, true, true, false},
+		{[]string{"ORG_ADMIN"}, permAuthorizationServers, true, false, false},
+		{[]string{"ORG_ADMIN"}, permUsers, true, true, false},
+		{[]string{"READ_ONLY_ADMIN"}, permApps, true, false, false},
+		{[]string{"APP_ADMIN", "GROUP_MEMBERSHIP_ADMIN"}, permGroups, true, true, false},
+		{[]string{"APP_ADMIN"}, permRoles, false, false, false},
+		{[]string{}, permUsers, false, false, false},
+		{[]string{"APP_ADMIN", "CUSTOM"}, permRoles, false, false, true},
+	}
+	for _, test := range tests {
+		caps := capabilitiesOfRoles(test.roles)
+		if test.unknownCap {
+			if caps != nil {
+				t.Errorf("expected the capabilities of %v to be unknown", test.roles)
+			}
+			continue
+		}
+		if caps.canRead(test.family) != test.canRead || caps.canManage(test.family) != test.canManage {
+			t.Errorf("expected %v to read %s %t and manage it %t, got %t and %t", test.roles, test.family,
+				test.canRead, test.canManage, caps.canRead(test.family), caps.canManage(test.family))
+		}
+	}
+}
+
+func TestPermissionDiff(t *testing.T) {
+	p := Provider()
+	config := &Config{
+		logger:       hclog.NewNullLogger(),
+		capabilities: newCapabilities("scopes okta.groups.read", []string{"okta.groups.read"}),
+	}
+	raw := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "testAcc"})
+	_, err := p.ResourcesMap[group].Diff(context.TODO(), nil, raw, config)
+	if err == nil || !strings.Contains(err.Error(), "okta.groups.manage") {
+		t.Errorf("expected the plan to fail for okta.groups.manage, got %v", err)
+	}
+
+	config.capabilities = newCapabilities("scopes okta.groups.manage", []string{"okta.groups.manage"})
+	if _, err := p.ResourcesMap[group].Diff(context.TODO(), nil, raw, config); err != nil {
+		t.Errorf("didn't expect the plan to fail, got %v", err)
+	}
+
+	// without discovered capabilities nothing is held back
+	config.capabilities = nil
+	if _, err := p.ResourcesMap[group].Diff(context.TODO(), nil, raw, config); err != nil {
+		t.Errorf("didn't expect the plan to fail, got %v", err)
+	}
+}
+
+func TestWarnForbiddenReads(t *testing.T) {
+	config := &Config{
+		logger:       hclog.NewNullLogger(),
+		capabilities: newCapabilities("scopes okta.users.read", []string{"okta.users.read"}),
+	}
+	read := warnForbiddenReads(user, func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if !skipForbiddenRead(ctx, m, permRoles, "admin_roles") {
+			t.Error("expected admin_roles to be skipped")
+		}
+		if skipForbiddenRead(ctx, m, permUsers, "profile") {
+			t.Error("didn't expect profile to be skipped")
+		}
+		resp := &sdk.Response{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		if err := suppressErrorOnForbidden(ctx, "group_memberships", m, resp, nil); err != nil {
+			t.Errorf("expected the 403 to be suppressed, got %v", err)
+		}
+		return nil
+	})
+	diags := read(context.TODO(), nil, config)
+	if len(diags) != 2 || diags[0].Severity != diag.Warning || diags[0].Summary != "okta_user skipped reading admin_roles" ||
+		diags[1].Summary != "okta_user skipped reading group_memberships" {
+		t.Errorf("expected warnings of the skipped admin_roles and group_memberships, got %+v", diags)
+	}
+}
+
+func TestRequireReadScope(t *testing.T) {
+	config := &Config{
+		logger:       hclog.NewNullLogger(),
+		capabilities: newCapabilities("granted scopes okta.users.read", []string{"okta.users.read"}),
+	}
+	config.capabilities.granted = true
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return nil
+	}
+	if diags := requireReadScope(users, read)(context.TODO(), nil, config); diags.HasError() {
+		t.Errorf("didn't expect okta_users to fail, got %+v", diags)
+	}
+	diags := requireReadScope(groups, read)(context.TODO(), nil, config)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "okta.groups.read") {
+		t.Errorf("expected okta_groups to fail for okta.groups.read, got %+v", diags)
+	}
+}
+
+func TestSetRolesForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errorCode":"E0000006","errorSummary":"You do not have permission to perform the requested action"}`))
+	}))
+	defer server.Close()
+	config := &Config{
+		orgName:     "test",
+		domain:      "okta.com",
+		accessToken: "accessToken",
+		httpProxy:   server.URL,
+		logLevel:    int(hclog.Warn),
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	d := schema.TestResourceDataRaw(t, dataSourceUser().Schema, map[string]interface{}{})
+	d.SetId("00u1abcdefghijklmnop")
+	read := warnForbiddenReads(user, func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if err := setRoles(ctx, d, m); err != nil {
+			return diag.FromErr(err)
+		}
+		if err := setAllGroups(ctx, d, m); err != nil {
+			return diag.FromErr(err)
+		}
+		return nil
+	})
+	diags := read(context.TODO(), d, config)
+	if len(diags) != 2 || diags[0].Severity != diag.Warning || diags[0].Summary != "okta_user skipped reading roles" ||
+		diags[1].Summary != "okta_user skipped reading group_memberships" {
+		t.Errorf("expected warnings of the skipped roles and group_memberships, got %+v", diags)
+	}
+}
+
+func TestDataSourceUsersForbidden(t *testing.T) {
+	var forbidden int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/api/v1/groups/") {
+			_, _ = w.Write([]byte(`[{"id":"00u1abcdefghijklmnop","profile":{}},{"id":"00u2abcdefghijklmnop","profile":{}}]`))
+			return
+		}
+		forbidden++
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errorCode":"E0000006","errorSummary":"You do not have permission to perform the requested action"}`))
+	}))
+	defer server.Close()
+	config := &Config{
+		orgName:     "test",
+		domain:      "okta.com",
+		accessToken: "accessToken",
+		httpProxy:   server.URL,
+		logLevel:    int(hclog.Warn),
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	d := schema.TestResourceDataRaw(t, dataSourceUsers().Schema, map[string]interface{}{
+		"group_id":       "00g1abcdefghijklmnop",
+		"include_groups": true,
+		"include_roles":  true,
+	})
+	diags := warnForbiddenReads(users, dataSourceUsersRead)(context.TODO(), d, config)
+	if len(diags) != 2 || diags[0].Summary != "okta_users skipped reading group_memberships" || diags[1].Summary != "okta_users skipped reading admin_roles" {
+		t.Errorf("expected a warning of each skipped sub-object, got %+v", diags)
+	}
+	if forbidden != 2 {
+		t.Errorf("expected the sub-objects to be skipped for the other users after the first 403, got %d 403s", forbidden)
+	}
+	if got := len(d.Get("users").([]interface{})); got != 2 {
+		t.Errorf("expected the users to be read, got %d", got)
+	}
+}
+
+func TestWarnMissingScope(t *testing.T) {
+	config := &Config{grantedScopes: []string{"okta.groups.manage", "okta.users.read"}, scopesWarned: &sync.Map{}}
+	read := func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics { return nil }
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+
+	diags := warnMissingScope(user, true, read)(context.TODO(), d, config)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Summary, "okta.users.manage scope of okta_user") {
+		t.Fatalf("expected a warning of the missing scope, got %+v", diags)
+	}
+	if diags := warnMissingScope(user, true, read)(context.TODO(), d, config); len(diags) != 0 {
+		t.Errorf("expected a single warning per type, got %+v", diags)
+	}
+	if diags := warnMissingScope(app, false, read)(context.TODO(), d, config); len(diags) != 1 || !strings.Contains(diags[0].Summary, "okta.apps.read scope of okta_app") {
+		t.Errorf("expected a warning of the missing read scope, got %+v", diags)
+	}
+	for _, covered := range []struct {
+		name  string
+		write bool
+	}{{group, true}, {users, false}, {expressionLint, false}} {
+		if diags := warnMissingScope(covered.name, covered.write, read)(context.TODO(), d, config); len(diags) != 0 {
+			t.Errorf("didn't expect a warning for %s, got %+v", covered.name, diags)
+		}
+	}
+
+	config = &Config{grantedScopes: []string{"okta.groups.manage"}, scopesWarned: &sync.Map{}, permissionAware: true}
+	if diags := warnMissingScope(user, true, read)(context.TODO(), d, config); len(diags) != 0 {
+		t.Errorf("didn't expect a warning with permission_aware, it fails up front, got %+v", diags)
+	}
+	if diags := warnMissingScope(user, true, read)(context.TODO(), d, &Config{}); len(diags) != 0 {
+		t.Errorf("didn't expect a warning without granted scopes, got %+v", diags)
+	}
+}
@@ -92,13 +92,15 @@ func setDefaultPolicy(ctx context.Context, d *schema.ResourceData, m interface{}
 	if err != nil {
 		return nil, err
 	}
-	groups, _, err := getOktaClientFromMetadata(m).Group.ListGroups(ctx, &query.Params{Q: "Everyone"})
-	if err != nil {
-		return nil, fmt.Errorf("failed find default group for default password policy: %v", err)
-	}
-	for i := range groups {
-		if groups[i].Profile.Name == "Everyone" {
-			_ = d.Set("default_included_group_id", groups[i].Id)
+	if !skipForbiddenRead(ctx, m, permGroups, "default_included_group_id") {
+		groups, resp, err := getOktaClientFromMetadata(m).Group.ListGroups(ctx, &query.Params{Q: "Everyone"})
+		if err := suppressErrorOnForbidden(ctx, "default_included_group_id", m, resp, err); err != nil {
+			return nil, fmt.Errorf("failed find default group for default password policy: %v", err)
+		}
+		for i := range groups {
+			if groups[i].Profile.Name == "Everyone" {
+				_ = d.Set("default_included_group_id", groups[i].Id)
+			}
 		}
 	}
 	_ = d.Set("name", policy.Name)
@@ -21,20 +21,27 @@ const (
 	adminRoleCustomAssignments    = "okta_admin_role_custom_assignments"
 	adminRoleTargets              = "okta_admin_role_targets"
 	app                           = "okta_app"
+	appActiveSigningKey           = "okta_app_active_signing_key"
 	appAutoLogin                  = "okta_app_auto_login"
 	appBasicAuth                  = "okta_app_basic_auth"
 	appBookmark                   = "okta_app_bookmark"
+	appCSR                        = "okta_app_csr"
+	appFeatures                   = "okta_app_features"
 	appGroupAssignment            = "okta_app_group_assignment"
 	appGroupAssignments           = "okta_app_group_assignments"
 	appMetadataSaml               = "okta_app_metadata_saml"
 	appOAuth                      = "okta_app_oauth"
 	appOAuthAPIScope              = "okta_app_oauth_api_scope"
+	appOAuthAPIScopes             = "okta_app_oauth_api_scopes"
 	appOAuthPostLogoutRedirectURI = "okta_app_oauth_post_logout_redirect_uri"
 	appOAuthRedirectURI           = "okta_app_oauth_redirect_uri"
+	appOAuthSecret                = "okta_app_oauth_secret"
+	appProvisioningConnection     = "okta_app_provisioning_connection"
 	appSaml                       = "okta_app_saml"
 	appSamlAppSettings            = "okta_app_saml_app_settings"
 	appSecurePasswordStore        = "okta_app_secure_password_store"
 	appSharedCredentials          = "okta_app_shared_credentials"
+	appSigningKey                 = "okta_app_signing_key"
 	appSignOnPolicy               = "okta_app_signon_policy"
 	appSignOnPolicyRule           = "okta_app_signon_policy_rule"
 	appSwa                        = "okta_app_swa"
@@ -71,6 +78,7 @@ const (
 	emailTemplates                = "okta_email_templates"
 	eventHook                     = "okta_event_hook"
 	eventHookVerification         = "okta_event_hook_verification"
+	expressionLint                = "okta_expression"
 	factor                        = "okta_factor"
 	factorTotp                    = "okta_factor_totp"
 	group                         = "okta_group"
@@ -130,7 +138,13 @@ const (
 // Provider establishes a client connection to an okta site
 // determined by its schema string values
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	return newProvider(&sharedConfig{})
+}
+
+// newProvider returns the plugin SDK provider, loading its Config into the
+// shared Config.
+func newProvider(shared *sharedConfig) *schema.Provider {
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"org_name": {
 				Type:        schema.TypeString,
@@ -143,14 +157,14 @@ func Provider() *schema.Provider {
 				Optional:      true,
 				DefaultFunc:   schema.EnvDefaultFunc("OKTA_ACCESS_TOKEN", nil),
 				Description:   "Bearer token granting privileges to Okta API.",
-				ConflictsWith: []string{"api_token", "client_id", "scopes", "private_key"},
+				ConflictsWith: []string{"api_token", "client_id", "scopes", "private_key", "private_keys"},
 			},
 			"api_token": {
 				Type:          schema.TypeString,
 				Optional:      true,
 				DefaultFunc:   schema.EnvDefaultFunc("OKTA_API_TOKEN", nil),
 				Description:   "API Token granting privileges to Okta API.",
-				ConflictsWith: []string{"access_token", "client_id", "scopes", "private_key"},
+				ConflictsWith: []string{"access_token", "client_id", "scopes", "private_key", "private_keys"},
 			},
 			"client_id": {
 				Type:          schema.TypeString,
@@ -171,7 +185,30 @@ func Provider() *schema.Provider {
 				Optional:      true,
 				Type:          schema.TypeString,
 				DefaultFunc:   schema.EnvDefaultFunc("OKTA_API_PRIVATE_KEY", nil),
-				Description:   "API Token granting privileges to Okta API.",
+				Description:   "PEM or JWK formatted private key, or a path to a file of it, granting privileges to Okta API. A JWK set, or a file of several PEM keys, is tried in order.",
+				ConflictsWith: []string{"access_token", "api_token"},
+			},
+			"private_keys": {
+				Optional:      true,
+				Type:          schema.TypeList,
+				Sensitive:     true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Description:   "Additional PEM or JWK formatted private keys, or paths to files of them, that are tried in order when Okta rejects the previous key with `invalid_client`, e.g. during key rotation.",
+				ConflictsWith: []string{"access_token", "api_token"},
+			},
+			"private_key_passphrase": {
+				Optional:      true,
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("OKTA_API_PRIVATE_KEY_PASSPHRASE", nil),
+				Description:   "Passphrase of encrypted PKCS#8 private keys.",
+				ConflictsWith: []string{"access_token", "api_token"},
+			},
+			"dpop": {
+				Optional:      true,
+				Type:          schema.TypeBool,
+				DefaultFunc:   schema.EnvDefaultFunc("OKTA_API_DPOP", nil),
+				Description:   "Bind the access tokens of the private key flow with DPoP (Demonstrating Proof-of-Possession), required by service apps that enforce DPoP.",
 				ConflictsWith: []string{"access_token", "api_token"},
 			},
 			"private_key_id": {
@@ -240,6 +277,47 @@ func Provider() *schema.Provider {
 					"capacity while making calls to the Okta management API endpoints. Okta API operates in one minute buckets. " +
 					"See Okta Management API Rate Limits: https://developer.okta.com/docs/reference/rl-global-mgmt/",
 			},
+			"max_api_capacity_state_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OKTA_MAX_API_CAPACITY_STATE_FILE", nil),
+				Description: "(Experimental) path to a state file where the rate limit status governed by `max_api_capacity` " +
+					"is shared between concurrent provider processes on the same machine.",
+			},
+			"max_api_capacity_buckets_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OKTA_MAX_API_CAPACITY_BUCKETS_FILE", nil),
+				Description: "(Experimental) path to a YAML or JSON file of `method`, `path`, and `bucket` objects that map " +
+					"endpoints to their rate limit buckets for `max_api_capacity`, overriding the built in mappings.",
+			},
+			"usage_report_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OKTA_USAGE_REPORT_FILE", nil),
+				Description: "path to a file where a JSON report of the API requests made by the provider, per endpoint " +
+					"class and rate limit bucket, is written when the provider shuts down.",
+			},
+			"permission_aware": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OKTA_PERMISSION_AWARE", false),
+				Description: "Discover the admin roles of the caller, or the granted scopes of a service app, at configure time. Plans that write a resource without the permission to do so fail early, and reads skip what is forbidden with a warning.",
+			},
+			"response_cache": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("OKTA_RESPONSE_CACHE", nil),
+				ValidateDiagFunc: stringInSlice([]string{"memory", "disk"}),
+				Description: "caches GET responses of the Okta API for the duration of a plan or apply, either in `memory` " +
+					"or on `disk`. Cached responses are invalidated by any change to the same resource or its list endpoints.",
+			},
+			"response_cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OKTA_RESPONSE_CACHE_DIR", nil),
+				Description: "directory under which the `disk` response cache is kept, defaults to the system's temporary directory.",
+			},
 			"request_timeout": {
 				Type:             schema.TypeInt,
 				Optional:         true,
@@ -252,19 +330,25 @@ func Provider() *schema.Provider {
 			adminRoleCustom:               resourceAdminRoleCustom(),
 			adminRoleCustomAssignments:    resourceAdminRoleCustomAssignments(),
 			adminRoleTargets:              resourceAdminRoleTargets(),
+			appActiveSigningKey:           resourceAppActiveSigningKey(),
 			appAutoLogin:                  resourceAppAutoLogin(),
 			appBasicAuth:                  resourceAppBasicAuth(),
 			appBookmark:                   resourceAppBookmark(),
+			appCSR:                        resourceAppCSR(),
+			appFeatures:                   resourceAppFeatures(),
 			appGroupAssignment:            resourceAppGroupAssignment(),
 			appGroupAssignments:           resourceAppGroupAssignments(),
 			appOAuth:                      resourceAppOAuth(),
 			appOAuthAPIScope:              resourceAppOAuthAPIScope(),
 			appOAuthPostLogoutRedirectURI: resourceAppOAuthPostLogoutRedirectURI(),
 			appOAuthRedirectURI:           resourceAppOAuthRedirectURI(),
+			appOAuthSecret:                resourceAppOAuthSecret(),
+			appProvisioningConnection:     resourceAppProvisioningConnection(),
 			appSaml:                       resourceAppSaml(),
 			appSamlAppSettings:            resourceAppSamlAppSettings(),
 			appSecurePasswordStore:        resourceAppSecurePasswordStore(),
 			appSharedCredentials:          resourceAppSharedCredentials(),
+			appSigningKey:                 resourceAppSigningKey(),
 			appSignOnPolicy:               resourceAppSignOnPolicy(),
 			appSignOnPolicyRule:           resourceAppSignOnPolicyRule(),
 			appSwa:                        resourceAppSwa(),
@@ -343,6 +427,7 @@ func Provider() *schema.Provider {
 			appGroupAssignments:      dataSourceAppGroupAssignments(),
 			appMetadataSaml:          dataSourceAppMetadataSaml(),
 			appOAuth:                 dataSourceAppOauth(),
+			appOAuthAPIScopes:        dataSourceAppOAuthAPIScopes(),
 			appSaml:                  dataSourceAppSaml(),
 			appSignOnPolicy:          dataSourceAppSignOnPolicy(),
 			appUserAssignments:       dataSourceAppUserAssignments(),
@@ -361,6 +446,7 @@ func Provider() *schema.Provider {
 			emailCustomizations:      dataSourceEmailCustomizations(),
 			emailTemplate:            dataSourceEmailTemplate(),
 			emailTemplates:           dataSourceEmailTemplates(),
+			expressionLint:           dataSourceExpression(),
 			defaultPolicy:            dataSourceDefaultPolicy(),
 			group:                    dataSourceGroup(),
 			groupEveryone:            dataSourceEveryoneGroup(),
@@ -381,29 +467,42 @@ func Provider() *schema.Provider {
 			userSecurityQuestions:    dataSourceUserSecurityQuestions(),
 			userType:                 dataSourceUserType(),
 		},
-		ConfigureContextFunc: providerConfigure,
+		ConfigureContextFunc: func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return providerConfigure(ctx, d, shared)
+		},
 	}
+	declarePermissions(p)
+	return p
 }
 
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData, shared *sharedConfig) (interface{}, diag.Diagnostics) {
 	log.Printf("[INFO] Initializing Okta client")
 	config := Config{
-		orgName:        d.Get("org_name").(string),
-		domain:         d.Get("base_url").(string),
-		apiToken:       d.Get("api_token").(string),
-		accessToken:    d.Get("access_token").(string),
-		clientID:       d.Get("client_id").(string),
-		privateKey:     d.Get("private_key").(string),
-		privateKeyId:   d.Get("private_key_id").(string),
-		scopes:         convertInterfaceToStringSet(d.Get("scopes")),
-		retryCount:     d.Get("max_retries").(int),
-		parallelism:    d.Get("parallelism").(int),
-		backoff:        d.Get("backoff").(bool),
-		minWait:        d.Get("min_wait_seconds").(int),
-		maxWait:        d.Get("max_wait_seconds").(int),
-		logLevel:       d.Get("log_level").(int),
-		requestTimeout: d.Get("request_timeout").(int),
-		maxAPICapacity: d.Get("max_api_capacity").(int),
+		orgName:          d.Get("org_name").(string),
+		domain:           d.Get("base_url").(string),
+		apiToken:         d.Get("api_token").(string),
+		accessToken:      d.Get("access_token").(string),
+		clientID:         d.Get("client_id").(string),
+		privateKey:       d.Get("private_key").(string),
+		privateKeyId:     d.Get("private_key_id").(string),
+		privateKeys:      convertInterfaceToStringArr(d.Get("private_keys")),
+		keyPassphrase:    d.Get("private_key_passphrase").(string),
+		dpop:             d.Get("dpop").(bool),
+		scopes:           convertInterfaceToStringSet(d.Get("scopes")),
+		retryCount:       d.Get("max_retries").(int),
+		parallelism:      d.Get("parallelism").(int),
+		backoff:          d.Get("backoff").(bool),
+		minWait:          d.Get("min_wait_seconds").(int),
+		maxWait:          d.Get("max_wait_seconds").(int),
+		logLevel:         d.Get("log_level").(int),
+		requestTimeout:   d.Get("request_timeout").(int),
+		maxAPICapacity:   d.Get("max_api_capacity").(int),
+		apiStateFile:     d.Get("max_api_capacity_state_file").(string),
+		apiBucketsFile:   d.Get("max_api_capacity_buckets_file").(string),
+		usageReportFile:  d.Get("usage_report_file").(string),
+		responseCache:    d.Get("response_cache").(string),
+		responseCacheDir: d.Get("response_cache_dir").(string),
+		permissionAware:  d.Get("permission_aware").(bool),
 	}
 
 	if httpProxy, ok := d.Get("http_proxy").(string); ok {
@@ -414,16 +513,11 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		config.scopes = strings.Split(v, ",")
 	}
 
-	if err := config.loadAndValidate(ctx); err != nil {
+	loaded, err := shared.load(ctx, &config)
+	if err != nil {
 		return nil, diag.Errorf("[ERROR] invalid configuration: %v", err)
 	}
-
-	// Discover if the Okta Org is Classic or OIE
-	if org, _, err := config.supplementClient.GetWellKnownOktaOrganization(ctx); err == nil {
-		config.classicOrg = (org.Pipeline == "v1") // v1 == Classic, idx == OIE
-	}
-
-	return &config, nil
+	return loaded, nil
 }
 
 // This is a global MutexKV for use within this plugin.
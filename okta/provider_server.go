@@ -0,0 +1,35 @@
+package okta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// providerServers are the servers of the provider's resources and data
+// sources, sharing the Config. Resources are ported from the plugin SDK to
+// the plugin framework one at a time: a ported resource is removed from the
+// plugin SDK provider and added to the framework provider's Resources. Both
+// providers declare the same provider schema and the state of a ported
+// resource is kept, as long as its schema doesn't change.
+func providerServers(shared *sharedConfig) []func() tfprotov5.ProviderServer {
+	return []func() tfprotov5.ProviderServer{
+		func() tfprotov5.ProviderServer {
+			return schema.NewGRPCProviderServer(newProvider(shared))
+		},
+		providerserver.NewProtocol5(newFrameworkProvider(shared)),
+	}
+}
+
+// ProviderServer returns the provider's protocol version 5 server, muxing the
+// servers of the plugin SDK and plugin framework resources and data sources.
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	server, err := tf5muxserver.NewMuxServer(ctx, providerServers(&sharedConfig{})...)
+	if err != nil {
+		return nil, err
+	}
+	return server.ProviderServer, nil
+}
@@ -0,0 +1,238 @@
+package okta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/emulator"
+)
+
+func TestProviderServer(t *testing.T) {
+	server, err := ProviderServer(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create the provider server: %v", err)
+	}
+	resp, err := server().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("failed to get the schema: %v", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic of the schema: %s: %s", d.Summary, d.Detail)
+	}
+	p := Provider()
+	for name := range p.ResourcesMap {
+		if resp.ResourceSchemas[name] == nil {
+			t.Errorf("resource %s isn't served", name)
+		}
+	}
+	for name := range p.DataSourcesMap {
+		if resp.DataSourceSchemas[name] == nil {
+			t.Errorf("data source %s isn't served", name)
+		}
+	}
+}
+
+func TestSharedConfig(t *testing.T) {
+	server := emulator.NewServer()
+	defer server.Close()
+
+	shared := &sharedConfig{}
+	var metas []interface{}
+	for i := 0; i < 2; i++ {
+		p := newProvider(shared)
+		diags := p.Configure(context.TODO(), terraform.NewResourceConfigRaw(map[string]interface{}{
+			"org_name":   "emulator",
+			"base_url":   "example.com",
+			"api_token":  "token",
+			"http_proxy": server.URL,
+		}))
+		if diags.HasError() {
+			t.Fatalf("failed to configure the provider: %+v", diags)
+		}
+		metas = append(metas, p.Meta())
+	}
+	if metas[0] == nil || metas[0] != metas[1] {
+		t.Errorf("providers sharing a config have different configs: %p %p", metas[0], metas[1])
+	}
+
+	diags := newProvider(shared).Configure(context.TODO(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"org_name":   "emulator",
+		"base_url":   "example.com",
+		"api_token":  "other-token",
+		"http_proxy": server.URL,
+	}))
+	if !diags.HasError() {
+		t.Error("expected configuring a provider sharing a config with other settings to fail")
+	}
+
+	p := Provider()
+	diags = p.Configure(context.TODO(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"org_name":   "emulator",
+		"base_url":   "example.com",
+		"api_token":  "token",
+		"http_proxy": server.URL,
+	}))
+	if diags.HasError() {
+		t.Fatalf("failed to configure the provider: %+v", diags)
+	}
+	if p.Meta() == metas[0] {
+		t.Error("providers not sharing a config have the same config")
+	}
+}
+
+func TestProviderServerMarkdownDescriptions(t *testing.T) {
+	kind := schema.DescriptionKind
+	schema.DescriptionKind = schema.StringMarkdown
+	defer func() { schema.DescriptionKind = kind }()
+
+	server, err := ProviderServer(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create the provider server: %v", err)
+	}
+	resp, err := server().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("failed to get the schema: %v", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("unexpected diagnostic of the schema: %s: %s", d.Summary, d.Detail)
+	}
+}
+
+// TestProviderServerConfigure configures the plugin SDK and the plugin
+// framework providers through the mux server, the way terraform does.
+func TestProviderServerConfigure(t *testing.T) {
+	server := emulator.NewServer()
+	defer server.Close()
+
+	shared := &sharedConfig{}
+	mux, err := tf5muxserver.NewMuxServer(context.Background(), providerServers(shared)...)
+	if err != nil {
+		t.Fatalf("failed to create the provider server: %v", err)
+	}
+	providerServer := mux.ProviderServer()
+	resp, err := providerServer.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("failed to get the schema: %v", err)
+	}
+	configType := resp.Provider.ValueType()
+	values := map[string]tftypes.Value{}
+	for name, attrType := range configType.(tftypes.Object).AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["org_name"] = tftypes.NewValue(tftypes.String, "emulator")
+	values["base_url"] = tftypes.NewValue(tftypes.String, "example.com")
+	values["api_token"] = tftypes.NewValue(tftypes.String, "token")
+	values["http_proxy"] = tftypes.NewValue(tftypes.String, server.URL)
+	values["max_retries"] = tftypes.NewValue(tftypes.Number, 3)
+	config, err := tfprotov5.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	if err != nil {
+		t.Fatalf("failed to encode the configuration: %v", err)
+	}
+
+	prepared, err := providerServer.PrepareProviderConfig(context.Background(), &tfprotov5.PrepareProviderConfigRequest{Config: &config})
+	if err != nil {
+		t.Fatalf("failed to prepare the configuration: %v", err)
+	}
+	for _, d := range prepared.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Errorf("unexpected error preparing the configuration: %s: %s", d.Summary, d.Detail)
+		}
+	}
+	configured, err := providerServer.ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{Config: &config})
+	if err != nil {
+		t.Fatalf("failed to configure the provider: %v", err)
+	}
+	for _, d := range configured.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Errorf("unexpected error configuring the provider: %s: %s", d.Summary, d.Detail)
+		}
+	}
+	if shared.config == nil || shared.config.retryCount != 3 {
+		t.Errorf("expected the shared config to be loaded with the configuration, got %+v", shared.config)
+	}
+}
+
+func TestProviderServerFunctions(t *testing.T) {
+	server, err := ProviderServer(context.Background())
+	if err != nil {
+		t.Fatalf("failed to create the provider server: %v", err)
+	}
+	functionServer, ok := server().(tfprotov5.FunctionServer)
+	if !ok {
+		t.Fatal("expected the provider server to serve functions")
+	}
+	// terraform gets the functions with the schema before it calls them
+	providerSchema, err := server().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("failed to get the schema: %v", err)
+	}
+	for _, name := range []string{"format_expression", "lint_expression"} {
+		if providerSchema.Functions[name] == nil {
+			t.Errorf("function %s isn't served", name)
+		}
+	}
+
+	call := func(name string, args ...string) *tfprotov5.CallFunctionResponse {
+		arguments := make([]*tfprotov5.DynamicValue, len(args))
+		for i, arg := range args {
+			value, err := tfprotov5.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, arg))
+			if err != nil {
+				t.Fatalf("failed to create the argument: %v", err)
+			}
+			arguments[i] = &value
+		}
+		resp, err := functionServer.CallFunction(context.Background(), &tfprotov5.CallFunctionRequest{Name: name, Arguments: arguments})
+		if err != nil {
+			t.Fatalf("failed to call %s: %v", name, err)
+		}
+		return resp
+	}
+
+	resp := call("format_expression", `user.a==1 and user.b gt 2`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error of format_expression: %s", resp.Error.Text)
+	}
+	value, err := resp.Result.Unmarshal(tftypes.String)
+	if err != nil {
+		t.Fatalf("failed to read the result: %v", err)
+	}
+	var formatted string
+	_ = value.As(&formatted)
+	if formatted != `user.a == 1 AND user.b > 2` {
+		t.Errorf("expected the formatted expression, got %q", formatted)
+	}
+	if resp := call("format_expression", `user.a ==`); resp.Error == nil || resp.Error.FunctionArgument == nil || *resp.Error.FunctionArgument != 0 {
+		t.Errorf("expected an error of an expression that doesn't parse, got %+v", resp.Error)
+	}
+
+	resp = call("lint_expression", `String.len(user.login, 1)`, "group_rule")
+	if resp.Error != nil {
+		t.Fatalf("unexpected error of lint_expression: %s", resp.Error.Text)
+	}
+	resultType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"valid":    tftypes.Bool,
+		"errors":   tftypes.List{ElementType: tftypes.String},
+		"warnings": tftypes.List{ElementType: tftypes.String},
+	}}
+	value, err = resp.Result.Unmarshal(resultType)
+	if err != nil {
+		t.Fatalf("failed to read the result: %v", err)
+	}
+	var result map[string]tftypes.Value
+	_ = value.As(&result)
+	var valid bool
+	var errs []tftypes.Value
+	_ = result["valid"].As(&valid)
+	_ = result["errors"].As(&errs)
+	if valid || len(errs) != 1 {
+		t.Errorf("expected an error of the wrong number of arguments, got %v", result)
+	}
+	if resp := call("lint_expression", `user.a`, "unknown"); resp.Error == nil {
+		t.Error("expected an error of an unknown context")
+	}
+}
@@ -3,7 +3,9 @@ package okta
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/emulator"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
 )
@@ -35,6 +38,35 @@ func logSweptResource(kind, id, nameOrLabel string) {
 	sweeperLogger.Warn(fmt.Sprintf("sweeper found dangling %q %q %q", kind, id, nameOrLabel))
 }
 
+// sweepResource deletes a dangling object the sweeper found. On a dry run,
+// ENV var OKTA_ACC_TEST_SWEEP_DRY_RUN not blank, the object is only listed.
+func sweepResource(kind, id, nameOrLabel string, del func() error) error {
+	if os.Getenv("OKTA_ACC_TEST_SWEEP_DRY_RUN") != "" {
+		sweeperLogger.Warn(fmt.Sprintf("sweeper would delete dangling %q %q %q", kind, id, nameOrLabel))
+		return nil
+	}
+	if err := del(); err != nil {
+		return err
+	}
+	logSweptResource(kind, id, nameOrLabel)
+	return nil
+}
+
+// sweepPrefix is the prefix of the names of the objects the sweepers delete,
+// ENV var OKTA_ACC_TEST_SWEEP_PREFIX or testResourcePrefix.
+func sweepPrefix() string {
+	if prefix := os.Getenv("OKTA_ACC_TEST_SWEEP_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return testResourcePrefix
+}
+
+// isSweepable tells if the name has the sweep prefix. Matching ignores case,
+// the same as the API's q searches the sweepers list by.
+func isSweepable(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), strings.ToLower(sweepPrefix()))
+}
+
 type testClient struct {
 	oktaClient    *sdk.Client
 	apiSupplement *sdk.APISupplement
@@ -43,6 +75,109 @@ type testClient struct {
 
 var testResourcePrefix = "testAcc"
 
+// testSweeper sweeps the objects of the resources. Dependencies are the
+// sweepers that have to run first, e.g. apps before the policies assigned
+// to them.
+type testSweeper struct {
+	name         string
+	resources    []string
+	dependencies []string
+	sweep        func(*testClient) error
+}
+
+// testSweepers are run in order by TestRunForcedSweeper. Every resource is
+// either swept by one of them or listed in unsweptResources.
+var testSweepers = []testSweeper{
+	{name: "okta_*_app", resources: []string{appAutoLogin, appBasicAuth, appBookmark, appOAuth, appSaml, appSecurePasswordStore, appSharedCredentials, appSwa, appThreeField}, sweep: sweepTestApps},
+	{name: appSignOnPolicy, dependencies: []string{"okta_*_app"}, sweep: sweepAccessPolicies},
+	{name: authServer, sweep: sweepAuthServers},
+	{name: behavior, sweep: sweepBehaviors},
+	{name: captcha, sweep: sweepCaptchas},
+	{name: domain, sweep: sweepDomains},
+	{name: emailCustomization, sweep: sweepEmailCustomization},
+	{name: eventHook, sweep: sweepEventHooks},
+	{name: groupRule, sweep: sweepGroupRules},
+	{name: "okta_*_idp", resources: []string{idpOidc, idpSaml, idpSocial}, sweep: sweepTestIdps},
+	{name: inlineHook, sweep: sweepInlineHooks},
+	{name: resourceSet, sweep: sweepResourceSets},
+	{name: adminRoleCustom, dependencies: []string{resourceSet}, sweep: sweepCustomRoles},
+	{name: group, dependencies: []string{groupRule}, sweep: sweepGroups},
+	{name: groupSchemaProperty, sweep: sweepGroupCustomSchema},
+	{name: networkZone, sweep: sweepNetworkZones},
+	{name: policyRuleIdpDiscovery, sweep: sweepPolicyRuleIdpDiscovery},
+	{name: policyRuleMfa, sweep: sweepMfaPolicyRules},
+	{name: policyRulePassword, sweep: sweepPolicyRulePasswords},
+	{name: policyRuleProfileEnrollment, sweep: sweepProfileEnrollmentPolicyRules},
+	{name: policyRuleSignOn, sweep: sweepSignOnPolicyRules},
+	{name: policyMfa, sweep: sweepMfaPolicies},
+	{name: policyPassword, sweep: sweepPasswordPolicies},
+	{name: policyProfileEnrollment, sweep: sweepProfileEnrollmentPolicies},
+	{name: policySignOn, sweep: sweepSignOnPolicies},
+	{name: trustedOrigin, sweep: sweepTrustedOrigins},
+	{name: user, sweep: sweepUsers},
+	{name: linkDefinition, dependencies: []string{user}, sweep: sweepLinkDefinitions},
+	{name: userSchemaProperty, sweep: sweepUserCustomSchema},
+	{name: userType, dependencies: []string{user}, sweep: sweepUserTypes},
+}
+
+// unsweptResources are the resources without a sweeper of their own and why.
+var unsweptResources = map[string]string{
+	adminRoleCustomAssignments:    "swept with " + resourceSet,
+	adminRoleTargets:              "swept with " + user + " and " + group,
+	appActiveSigningKey:           "swept with okta_*_app",
+	appCSR:                        "swept with okta_*_app",
+	appFeatures:                   "swept with okta_*_app",
+	appGroupAssignment:            "swept with okta_*_app",
+	appGroupAssignments:           "swept with okta_*_app",
+	appOAuthAPIScope:              "swept with okta_*_app",
+	appOAuthPostLogoutRedirectURI: "swept with okta_*_app",
+	appOAuthRedirectURI:           "swept with okta_*_app",
+	appOAuthSecret:                "swept with okta_*_app",
+	appProvisioningConnection:     "swept with okta_*_app",
+	appSamlAppSettings:            "swept with okta_*_app",
+	appSignOnPolicyRule:           "swept with " + appSignOnPolicy,
+	appSigningKey:                 "swept with okta_*_app",
+	appUser:                       "swept with okta_*_app",
+	appUserBaseSchemaProperty:     "swept with okta_*_app",
+	appUserSchemaProperty:         "swept with okta_*_app",
+	authServerClaim:               "swept with " + authServer,
+	authServerClaimDefault:        "swept with " + authServer,
+	authServerDefault:             "the default authorization server is updated in place",
+	authServerPolicy:              "swept with " + authServer,
+	authServerPolicyRule:          "swept with " + authServer,
+	authServerScope:               "swept with " + authServer,
+	authenticator:                 "authenticators can't be deleted, only deactivated",
+	brand:                         "brands are updated in place",
+	captchaOrgWideSettings:        "org settings, nothing to sweep",
+	domainCertificate:             "swept with " + domain,
+	domainVerification:            "swept with " + domain,
+	emailSender:                   "email senders can't be listed",
+	emailSenderVerification:       "verifies an " + emailSender,
+	eventHookVerification:         "verifies an " + eventHook,
+	factor:                        "org factors are activated in place",
+	factorTotp:                    "TOTP factor profiles can't be listed",
+	groupMemberships:              "swept with " + group,
+	groupRole:                     "swept with " + group,
+	idpSamlKey:                    "swept with okta_*_idp",
+	linkValue:                     "swept with " + user,
+	orgConfiguration:              "org settings, nothing to sweep",
+	orgSupport:                    "org settings, nothing to sweep",
+	policyMfaDefault:              "the default policy is updated in place",
+	policyPasswordDefault:         "the default policy is updated in place",
+	policyProfileEnrollmentApps:   "swept with " + policyProfileEnrollment,
+	profileMapping:                "swept with okta_*_app and okta_*_idp",
+	rateLimiting:                  "org settings, nothing to sweep",
+	roleSubscription:              "role subscriptions are updated in place",
+	securityNotificationEmails:    "org settings, nothing to sweep",
+	templateSms:                   "custom SMS templates are all named Custom, the prefix can't tell them apart",
+	theme:                         "themes can't be deleted, they're updated in place",
+	threatInsightSettings:         "org settings, nothing to sweep",
+	userAdminRoles:                "swept with " + user,
+	userBaseSchemaProperty:        "the base schema is updated in place",
+	userFactorQuestion:            "swept with " + user,
+	userGroupMemberships:          "swept with " + user,
+}
+
 // TestMain overridden main testing function. Package level BeforeAll and AfterAll.
 // It also delineates between acceptance tests and unit tests
 func TestMain(m *testing.M) {
@@ -55,30 +190,9 @@ func TestMain(m *testing.M) {
 	// NOTE: Don't run sweepers if we are playing back VCR as nothing should be
 	// going over the wire
 	if os.Getenv("OKTA_VCR_TF_ACC") != "play" {
-		setupSweeper(adminRoleCustom, sweepCustomRoles)
-		setupSweeper("okta_*_app", sweepTestApps)
-		setupSweeper(authServer, sweepAuthServers)
-		setupSweeper(behavior, sweepBehaviors)
-		setupSweeper(emailCustomization, sweepEmailCustomization)
-		setupSweeper(groupRule, sweepGroupRules)
-		setupSweeper("okta_*_idp", sweepTestIdps)
-		setupSweeper(inlineHook, sweepInlineHooks)
-		setupSweeper(group, sweepGroups)
-		setupSweeper(groupSchemaProperty, sweepGroupCustomSchema)
-		setupSweeper(linkDefinition, sweepLinkDefinitions)
-		setupSweeper(networkZone, sweepNetworkZones)
-		setupSweeper(policyMfa, sweepMfaPolicies)
-		setupSweeper(policyPassword, sweepPasswordPolicies)
-		setupSweeper(policyRuleIdpDiscovery, sweepPolicyRuleIdpDiscovery)
-		setupSweeper(policyRuleMfa, sweepMfaPolicyRules)
-		setupSweeper(policyRulePassword, sweepPolicyRulePasswords)
-		setupSweeper(policyRuleSignOn, sweepSignOnPolicyRules)
-		setupSweeper(policySignOn, sweepAccessPolicies)
-		// setupSweeper(policySignOn, sweepSignOnPolicies)
-		setupSweeper(resourceSet, sweepResourceSets)
-		setupSweeper(user, sweepUsers)
-		setupSweeper(userSchemaProperty, sweepUserCustomSchema)
-		setupSweeper(userType, sweepUserTypes)
+		for _, s := range testSweepers {
+			setupSweeper(s)
+		}
 	}
 
 	resource.TestMain(m)
@@ -101,42 +215,131 @@ func TestRunForcedSweeper(t *testing.T) {
 		return
 	}
 
-	sweepCustomRoles(testClient)
-	sweepTestApps(testClient)
-	sweepAuthServers(testClient)
-	sweepBehaviors(testClient)
-	sweepEmailCustomization(testClient)
-	sweepGroupRules(testClient)
-	sweepTestIdps(testClient)
-	sweepInlineHooks(testClient)
-	sweepGroups(testClient)
-	sweepGroupCustomSchema(testClient)
-	sweepLinkDefinitions(testClient)
-	sweepNetworkZones(testClient)
-	sweepMfaPolicies(testClient)
-	sweepPasswordPolicies(testClient)
-	sweepPolicyRuleIdpDiscovery(testClient)
-	sweepMfaPolicyRules(testClient)
-	sweepPolicyRulePasswords(testClient)
-	sweepSignOnPolicyRules(testClient)
-	sweepAccessPolicies(testClient)
-	sweepSignOnPolicies(testClient)
-	sweepResourceSets(testClient)
-	sweepUsers(testClient)
-	sweepUserCustomSchema(testClient)
-	sweepUserTypes(testClient)
+	for _, s := range testSweepers {
+		if err := s.sweep(testClient); err != nil {
+			t.Errorf("sweeper %s: %v", s.name, err)
+		}
+	}
+}
+
+func TestSweepers(t *testing.T) {
+	swept := map[string]string{}
+	for _, s := range testSweepers {
+		resources := s.resources
+		if len(resources) == 0 {
+			resources = []string{s.name}
+		}
+		for _, name := range resources {
+			if other, ok := swept[name]; ok {
+				t.Errorf("resource %s is swept by both %s and %s", name, other, s.name)
+			}
+			swept[name] = s.name
+		}
+		for _, dependency := range s.dependencies {
+			if !hasTestSweeper(dependency) {
+				t.Errorf("sweeper %s depends on unknown sweeper %s", s.name, dependency)
+			}
+		}
+	}
+	resources := Provider().ResourcesMap
+	var missing []string
+	for name := range resources {
+		_, isSwept := swept[name]
+		_, isUnswept := unsweptResources[name]
+		switch {
+		case isSwept && isUnswept:
+			t.Errorf("resource %s has a sweeper and is listed as unswept", name)
+		case !isSwept && !isUnswept:
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		t.Errorf("resource %s has no sweeper and isn't listed as unswept", name)
+	}
+	for name := range unsweptResources {
+		if _, ok := resources[name]; !ok {
+			t.Errorf("unswept resource %s isn't a resource of the provider", name)
+		}
+	}
+}
+
+func TestSweepDryRunAndPrefix(t *testing.T) {
+	server := emulator.NewServer()
+	defer server.Close()
+	ctx, client, err := sdk.NewClient(context.TODO(),
+		sdk.WithOrgUrl(server.URL),
+		sdk.WithToken("token"),
+		sdk.WithCache(false),
+		sdk.WithTestingDisableHttpsCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create the client: %v", err)
+	}
+	ids := map[string]string{}
+	for _, name := range []string{"testAcc_1", "TestAcc_2", "custom_1", "other"} {
+		group, _, err := client.Group.CreateGroup(ctx, sdk.Group{Profile: &sdk.GroupProfile{Name: name}})
+		if err != nil {
+			t.Fatalf("failed to create group %s: %v", name, err)
+		}
+		ids[name] = group.Id
+	}
+	exists := func(name string) bool {
+		_, ok := server.Get("/api/v1/groups/" + ids[name])
+		return ok
+	}
+	testClient := &testClient{oktaClient: client}
+
+	t.Setenv("OKTA_ACC_TEST_SWEEP_DRY_RUN", "1")
+	if err := sweepGroups(testClient); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	for name := range ids {
+		if !exists(name) {
+			t.Errorf("expected the dry run to keep group %s", name)
+		}
+	}
+
+	t.Setenv("OKTA_ACC_TEST_SWEEP_DRY_RUN", "")
+	if err := sweepGroups(testClient); err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	for name, swept := range map[string]bool{"testAcc_1": true, "TestAcc_2": true, "custom_1": false, "other": false} {
+		if exists(name) == swept {
+			t.Errorf("expected group %s to be swept %t", name, swept)
+		}
+	}
+
+	t.Setenv("OKTA_ACC_TEST_SWEEP_PREFIX", "custom_")
+	if err := sweepGroups(testClient); err != nil {
+		t.Fatalf("sweep failed: %v", err)
+	}
+	if exists("custom_1") || !exists("other") {
+		t.Errorf("expected only group custom_1 to be swept by its prefix")
+	}
+}
+
+func hasTestSweeper(name string) bool {
+	for _, s := range testSweepers {
+		if s.name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Sets up sweeper to clean up dangling resources
-func setupSweeper(resourceType string, del func(*testClient) error) {
-	resource.AddTestSweepers(resourceType, &resource.Sweeper{
-		Name: resourceType,
+func setupSweeper(s testSweeper) {
+	sweep := s.sweep
+	resource.AddTestSweepers(s.name, &resource.Sweeper{
+		Name:         s.name,
+		Dependencies: s.dependencies,
 		F: func(_ string) error {
 			client, apiSupplement, v3Client, err := sharedTestClients()
 			if err != nil {
 				return err
 			}
-			return del(&testClient{oktaClient: client, apiSupplement: apiSupplement, oktaV3Client: v3Client})
+			return sweep(&testClient{oktaClient: client, apiSupplement: apiSupplement, oktaV3Client: v3Client})
 		},
 	})
 }
@@ -164,12 +367,13 @@ func sharedTestClients() (*sdk.Client, *sdk.APISupplement, *okta.APIClient, erro
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	orgURL := fmt.Sprintf("https://%v.%v", c.orgName, c.domain)
+	orgURL, disableHTTPS := c.orgURL()
 	_, client, err := sdk.NewClient(
 		context.Background(),
 		sdk.WithOrgUrl(orgURL),
 		sdk.WithToken(c.apiToken),
 		sdk.WithRateLimitMaxRetries(20),
+		sdk.WithTestingDisableHttpsCheck(disableHTTPS),
 	)
 	if err != nil {
 		return client, nil, nil, err
@@ -180,8 +384,12 @@ func sharedTestClients() (*sdk.Client, *sdk.APISupplement, *okta.APIClient, erro
 		okta.WithCache(false),
 		okta.WithToken(c.apiToken),
 		okta.WithRateLimitMaxRetries(20),
+		okta.WithTestingDisableHttpsCheck(disableHTTPS),
 	}
 	config := okta.NewConfiguration(setters...)
+	if u, err := url.Parse(orgURL); err == nil && u.Port() != "" {
+		config.Host = u.Host
+	}
 	v3Client := okta.NewAPIClient(config)
 
 	return client, api, v3Client, nil
@@ -194,38 +402,43 @@ func sweepCustomRoles(client *testClient) error {
 		return err
 	}
 	for _, role := range customRoles.Roles {
-		if !strings.HasPrefix(role.Label, "testAcc_") {
+		if !isSweepable(role.Label) {
+			continue
+		}
+		err := sweepResource("custom role", role.Id, role.Label, func() error {
 			_, err := client.apiSupplement.DeleteCustomRole(context.Background(), role.Id)
-			if err != nil {
-				errorList = append(errorList, err)
-				continue
-			}
-			logSweptResource("custom role", role.Id, role.Label)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
 		}
 	}
 	return condenseError(errorList)
 }
 
 func sweepTestApps(client *testClient) error {
-	appList, err := listApps(context.Background(), client.oktaClient, &appFilters{LabelPrefix: testResourcePrefix}, defaultPaginationLimit)
+	appList, err := listApps(context.Background(), client.oktaClient, &appFilters{LabelPrefix: sweepPrefix()}, defaultPaginationLimit)
 	if err != nil {
 		return err
 	}
 	var warnings []string
 	for _, app := range appList {
 		warn := fmt.Sprintf("failed to sweep an application, there may be dangling resources. ID %s, label %s", app.Id, app.Label)
-		_, err := client.oktaClient.Application.DeactivateApplication(context.Background(), app.Id)
+		err := sweepResource("app", app.Id, app.Name, func() error {
+			_, err := client.oktaClient.Application.DeactivateApplication(context.Background(), app.Id)
+			if err != nil {
+				warnings = append(warnings, warn)
+			}
+			resp, err := client.oktaClient.Application.DeleteApplication(context.Background(), app.Id)
+			if is404(resp) {
+				warnings = append(warnings, warn)
+				return nil
+			}
+			return err
+		})
 		if err != nil {
-			warnings = append(warnings, warn)
-		}
-		resp, err := client.oktaClient.Application.DeleteApplication(context.Background(), app.Id)
-		if is404(resp) {
-			warnings = append(warnings, warn)
-			continue
-		} else if err != nil {
 			return err
 		}
-		logSweptResource("app", app.Id, app.Name)
 	}
 	if len(warnings) > 0 {
 		return fmt.Errorf("sweep failures: %s", strings.Join(warnings, ", "))
@@ -234,44 +447,98 @@ func sweepTestApps(client *testClient) error {
 }
 
 func sweepAuthServers(client *testClient) error {
-	servers, _, err := client.oktaClient.AuthorizationServer.ListAuthorizationServers(context.Background(), &query.Params{Q: testResourcePrefix})
+	servers, _, err := client.oktaClient.AuthorizationServer.ListAuthorizationServers(context.Background(), &query.Params{Q: sweepPrefix()})
 	if err != nil {
 		return err
 	}
 	for _, s := range servers {
-		if _, err := client.oktaClient.AuthorizationServer.DeactivateAuthorizationServer(context.Background(), s.Id); err != nil {
+		err := sweepResource("authorization server", s.Id, s.Name, func() error {
+			if _, err := client.oktaClient.AuthorizationServer.DeactivateAuthorizationServer(context.Background(), s.Id); err != nil {
+				return err
+			}
+			_, err := client.oktaClient.AuthorizationServer.DeleteAuthorizationServer(context.Background(), s.Id)
 			return err
-		}
-		if _, err := client.oktaClient.AuthorizationServer.DeleteAuthorizationServer(context.Background(), s.Id); err != nil {
+		})
+		if err != nil {
 			return err
 		}
-		logSweptResource("authorization server", s.Id, s.Name)
 	}
 	return nil
 }
 
 func sweepBehaviors(client *testClient) error {
 	var errorList []error
-	behaviors, _, err := client.apiSupplement.ListBehaviors(context.Background(), &query.Params{Q: testResourcePrefix})
+	behaviors, _, err := client.apiSupplement.ListBehaviors(context.Background(), &query.Params{Q: sweepPrefix()})
 	if err != nil {
 		return err
 	}
 	for _, b := range behaviors {
-		if _, err := client.apiSupplement.DeleteBehavior(context.Background(), b.ID); err != nil {
+		err := sweepResource("behavior", b.ID, b.Name, func() error {
+			_, err := client.apiSupplement.DeleteBehavior(context.Background(), b.ID)
+			return err
+		})
+		if err != nil {
 			errorList = append(errorList, err)
+		}
+	}
+	return condenseError(errorList)
+}
+
+func sweepCaptchas(client *testClient) error {
+	var errorList []error
+	captchas, _, err := client.oktaV3Client.CAPTCHAApi.ListCaptchaInstances(context.Background()).Execute()
+	if err != nil {
+		return err
+	}
+	for _, c := range captchas {
+		if !isSweepable(c.GetName()) {
 			continue
 		}
-		logSweptResource("behavior", b.ID, b.Name)
+		err := sweepResource("captcha", c.GetId(), c.GetName(), func() error {
+			_, err := client.oktaV3Client.CAPTCHAApi.DeleteCaptchaInstance(context.Background(), c.GetId()).Execute()
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
+		}
 	}
 	return condenseError(errorList)
 }
 
+// sweepDomains sweeps the custom domains whose name starts with the prefix,
+// e.g. testacc.example.com. The example.com domains of TestAccOktaDomain and
+// TestAccDataSourceOktaDomain_read don't and aren't swept, those tests delete
+// them.
+func sweepDomains(client *testClient) error {
+	var errorList []error
+	domains, _, err := client.oktaClient.Domain.ListDomains(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, d := range domains.Domains {
+		if !isSweepable(d.Domain) {
+			continue
+		}
+		err := sweepResource("domain", d.Id, d.Domain, func() error {
+			_, err := client.oktaClient.Domain.DeleteDomain(context.Background(), d.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
+		}
+	}
+	return condenseError(errorList)
+}
+
+// sweepEmailCustomization sweeps the customizations of all the email
+// templates, a customization can't carry the prefix.
 func sweepEmailCustomization(client *testClient) error {
 	ctx := context.Background()
 	brands, _, err := client.oktaV3Client.CustomizationApi.ListBrands(ctx).Execute()
 	if err != nil {
 		return err
 	}
+	var errorList []error
 	for _, brand := range brands {
 		templates, resp, err := client.oktaV3Client.CustomizationApi.ListEmailTemplates(ctx, brand.GetId()).Limit(int32(defaultPaginationLimit)).Execute()
 		if err != nil {
@@ -287,11 +554,50 @@ func sweepEmailCustomization(client *testClient) error {
 		}
 
 		for _, template := range templates {
-			_, _ = client.oktaV3Client.CustomizationApi.DeleteAllCustomizations(context.Background(), brand.GetId(), template.GetName()).Execute()
+			customizations, _, err := client.oktaV3Client.CustomizationApi.ListEmailCustomizations(ctx, brand.GetId(), template.GetName()).Execute()
+			if err != nil || len(customizations) == 0 {
+				continue
+			}
+			languages := make([]string, len(customizations))
+			for i, customization := range customizations {
+				languages[i] = customization.GetLanguage()
+			}
+			err = sweepResource("email customization", brand.GetId()+"/"+template.GetName(), strings.Join(languages, ","), func() error {
+				_, err := client.oktaV3Client.CustomizationApi.DeleteAllCustomizations(ctx, brand.GetId(), template.GetName()).Execute()
+				return err
+			})
+			if err != nil {
+				errorList = append(errorList, err)
+			}
 		}
 	}
+	return condenseError(errorList)
+}
 
-	return nil
+func sweepEventHooks(client *testClient) error {
+	var errorList []error
+	hooks, _, err := client.oktaClient.EventHook.ListEventHooks(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if !isSweepable(hook.Name) {
+			continue
+		}
+		err := sweepResource("event hook", hook.Id, hook.Name, func() error {
+			if hook.Status == statusActive {
+				if _, _, err := client.oktaClient.EventHook.DeactivateEventHook(context.Background(), hook.Id); err != nil {
+					return err
+				}
+			}
+			_, err := client.oktaClient.EventHook.DeleteEventHook(context.Background(), hook.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
+		}
+	}
+	return condenseError(errorList)
 }
 
 func sweepGroupRules(client *testClient) error {
@@ -303,39 +609,47 @@ func sweepGroupRules(client *testClient) error {
 	}
 
 	for _, s := range rules {
-		if s.Status == statusActive {
-			if _, err := client.oktaClient.Group.DeactivateGroupRule(context.Background(), s.Id); err != nil {
-				errorList = append(errorList, err)
-				continue
-			}
+		if !isSweepable(s.Name) {
+			continue
 		}
-		if _, err := client.oktaClient.Group.DeleteGroupRule(context.Background(), s.Id, nil); err != nil {
+		err := sweepResource("group rule", s.Id, s.Name, func() error {
+			if s.Status == statusActive {
+				if _, err := client.oktaClient.Group.DeactivateGroupRule(context.Background(), s.Id); err != nil {
+					return err
+				}
+			}
+			_, err := client.oktaClient.Group.DeleteGroupRule(context.Background(), s.Id, nil)
+			return err
+		})
+		if err != nil {
 			errorList = append(errorList, err)
-			continue
 		}
-		logSweptResource("group rule", s.Id, s.Name)
 	}
 	return condenseError(errorList)
 }
 
 func sweepTestIdps(client *testClient) error {
-	providers, _, err := client.oktaClient.IdentityProvider.ListIdentityProviders(context.Background(), &query.Params{Q: "testAcc_"})
+	providers, _, err := client.oktaClient.IdentityProvider.ListIdentityProviders(context.Background(), &query.Params{Q: sweepPrefix()})
 	if err != nil {
 		return err
 	}
 	for _, idp := range providers {
-		_, err := client.oktaClient.IdentityProvider.DeleteIdentityProvider(context.Background(), idp.Id)
+		err := sweepResource("identity provider", idp.Id, idp.Name, func() error {
+			_, err := client.oktaClient.IdentityProvider.DeleteIdentityProvider(context.Background(), idp.Id)
+			return err
+		})
 		if err != nil {
 			return err
 		}
-		logSweptResource("identity provider", idp.Id, idp.Name)
 
 		if idp.Type == saml2Idp {
-			_, err := client.oktaClient.IdentityProvider.DeleteIdentityProviderKey(context.Background(), idp.Protocol.Credentials.Trust.Kid)
+			err := sweepResource("saml identity provider key", idp.Id, idp.Protocol.Credentials.Trust.Kid, func() error {
+				_, err := client.oktaClient.IdentityProvider.DeleteIdentityProviderKey(context.Background(), idp.Protocol.Credentials.Trust.Kid)
+				return err
+			})
 			if err != nil {
 				return err
 			}
-			logSweptResource("saml identity provider key", idp.Id, idp.Protocol.Credentials.Trust.Kid)
 		}
 	}
 	return nil
@@ -348,20 +662,21 @@ func sweepInlineHooks(client *testClient) error {
 		return err
 	}
 	for _, hook := range hooks {
-		if !strings.HasPrefix(hook.Name, testResourcePrefix) {
+		if !isSweepable(hook.Name) {
 			continue
 		}
-		if hook.Status == statusActive {
-			_, _, err = client.oktaClient.InlineHook.DeactivateInlineHook(context.Background(), hook.Id)
-			if err != nil {
-				errorList = append(errorList, err)
+		err := sweepResource("inline hook", hook.Id, hook.Name, func() error {
+			if hook.Status == statusActive {
+				if _, _, err := client.oktaClient.InlineHook.DeactivateInlineHook(context.Background(), hook.Id); err != nil {
+					return err
+				}
 			}
-		}
-		_, err = client.oktaClient.InlineHook.DeleteInlineHook(context.Background(), hook.Id)
+			_, err := client.oktaClient.InlineHook.DeleteInlineHook(context.Background(), hook.Id)
+			return err
+		})
 		if err != nil {
 			errorList = append(errorList, err)
 		}
-		logSweptResource("inline hook", hook.Id, hook.Name)
 	}
 	return condenseError(errorList)
 }
@@ -369,17 +684,22 @@ func sweepInlineHooks(client *testClient) error {
 func sweepGroups(client *testClient) error {
 	var errorList []error
 	// Should never need to deal with pagination, limit is 10,000 by default
-	groups, _, err := client.oktaClient.Group.ListGroups(context.Background(), &query.Params{Q: testResourcePrefix})
+	groups, _, err := client.oktaClient.Group.ListGroups(context.Background(), &query.Params{Q: sweepPrefix()})
 	if err != nil {
 		return err
 	}
 
 	for _, s := range groups {
-		if _, err := client.oktaClient.Group.DeleteGroup(context.Background(), s.Id); err != nil {
-			errorList = append(errorList, err)
+		if !isSweepable(s.Profile.Name) {
 			continue
 		}
-		logSweptResource("group", s.Id, s.Profile.Name)
+		err := sweepResource("group", s.Id, s.Profile.Name, func() error {
+			_, err := client.oktaClient.Group.DeleteGroup(context.Background(), s.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
+		}
 	}
 	return condenseError(errorList)
 }
@@ -390,13 +710,16 @@ func sweepGroupCustomSchema(client *testClient) error {
 		return err
 	}
 	for key := range schema.Definitions.Custom.Properties {
-		if strings.HasPrefix(key, testResourcePrefix) {
+		if !isSweepable(key) {
+			continue
+		}
+		err := sweepResource("group schema property", key, key, func() error {
 			custom := buildCustomGroupSchema(key, nil)
-			_, _, err = client.oktaClient.GroupSchema.UpdateGroupSchema(context.Background(), *custom)
-			if err != nil {
-				return err
-			}
-			logSweptResource("update group schema", key, key)
+			_, _, err := client.oktaClient.GroupSchema.UpdateGroupSchema(context.Background(), *custom)
+			return err
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -409,12 +732,15 @@ func sweepLinkDefinitions(client *testClient) error {
 		return err
 	}
 	for _, object := range linkedObjects {
-		if strings.HasPrefix(object.Primary.Name, testResourcePrefix) {
-			if _, err := client.oktaClient.LinkedObject.DeleteLinkedObjectDefinition(context.Background(), object.Primary.Name); err != nil {
-				errorList = append(errorList, err)
-				continue
-			}
-			logSweptResource("linked object definition", object.Primary.Name, object.Primary.Title)
+		if !isSweepable(object.Primary.Name) {
+			continue
+		}
+		err := sweepResource("linked object definition", object.Primary.Name, object.Primary.Title, func() error {
+			_, err := client.oktaClient.LinkedObject.DeleteLinkedObjectDefinition(context.Background(), object.Primary.Name)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
 		}
 	}
 	return condenseError(errorList)
@@ -427,12 +753,15 @@ func sweepNetworkZones(client *testClient) error {
 		return err
 	}
 	for _, zone := range zones {
-		if strings.HasPrefix(zone.Name, testResourcePrefix) {
-			if _, err := client.oktaClient.NetworkZone.DeleteNetworkZone(context.Background(), zone.Id); err != nil {
-				errorList = append(errorList, err)
-				continue
-			}
-			logSweptResource("network zone", zone.Id, zone.Name)
+		if !isSweepable(zone.Name) {
+			continue
+		}
+		err := sweepResource("network zone", zone.Id, zone.Name, func() error {
+			_, err := client.oktaClient.NetworkZone.DeleteNetworkZone(context.Background(), zone.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
 		}
 	}
 	return condenseError(errorList)
@@ -450,6 +779,10 @@ func sweepAccessPolicies(client *testClient) error {
 	return sweepPolicyByType(sdk.AccessPolicyType, client)
 }
 
+func sweepProfileEnrollmentPolicies(client *testClient) error {
+	return sweepPolicyByType(sdk.ProfileEnrollmentPolicyType, client)
+}
+
 func sweepPolicyRuleIdpDiscovery(client *testClient) error {
 	return sweepPolicyRulesByType(sdk.IdpDiscoveryType, client)
 }
@@ -462,6 +795,10 @@ func sweepPolicyRulePasswords(client *testClient) error {
 	return sweepPolicyRulesByType(sdk.PasswordPolicyType, client)
 }
 
+func sweepProfileEnrollmentPolicyRules(client *testClient) error {
+	return sweepPolicyRulesByType(sdk.ProfileEnrollmentPolicyType, client)
+}
+
 func sweepSignOnPolicyRules(client *testClient) error {
 	return sweepPolicyRulesByType(sdk.SignOnPolicyType, client)
 }
@@ -477,12 +814,36 @@ func sweepResourceSets(client *testClient) error {
 		return err
 	}
 	for _, b := range resourceSets.ResourceSets {
-		if !strings.HasPrefix(b.Label, "testAcc_") {
-			if _, err := client.apiSupplement.DeleteResourceSet(context.Background(), b.Id); err != nil {
-				errorList = append(errorList, err)
-				continue
-			}
-			logSweptResource("resource set", b.Id, b.Label)
+		if !isSweepable(b.Label) {
+			continue
+		}
+		err := sweepResource("resource set", b.Id, b.Label, func() error {
+			_, err := client.apiSupplement.DeleteResourceSet(context.Background(), b.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
+		}
+	}
+	return condenseError(errorList)
+}
+
+func sweepTrustedOrigins(client *testClient) error {
+	var errorList []error
+	origins, _, err := client.oktaClient.TrustedOrigin.ListOrigins(context.Background(), &query.Params{Q: sweepPrefix()})
+	if err != nil {
+		return err
+	}
+	for _, origin := range origins {
+		if !isSweepable(origin.Name) {
+			continue
+		}
+		err := sweepResource("trusted origin", origin.Id, origin.Name, func() error {
+			_, err := client.oktaClient.TrustedOrigin.DeleteOrigin(context.Background(), origin.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
 		}
 	}
 	return condenseError(errorList)
@@ -490,7 +851,7 @@ func sweepResourceSets(client *testClient) error {
 
 func sweepUsers(client *testClient) error {
 	var errorList []error
-	users, resp, err := client.oktaClient.User.ListUsers(context.Background(), &query.Params{Limit: 200, Q: testResourcePrefix})
+	users, resp, err := client.oktaClient.User.ListUsers(context.Background(), &query.Params{Limit: 200, Q: sweepPrefix()})
 	if err != nil {
 		return err
 	}
@@ -504,15 +865,16 @@ func sweepUsers(client *testClient) error {
 	}
 
 	for _, u := range users {
-		if err := ensureUserDelete(context.Background(), u.Id, u.Status, client.oktaClient); err != nil {
-			errorList = append(errorList, err)
-			continue
-		}
 		var label string
 		for k, v := range *u.Profile {
 			label += fmt.Sprintf("%s:%+v, ", k, v)
 		}
-		logSweptResource("user", u.Id, label)
+		err := sweepResource("user", u.Id, label, func() error {
+			return ensureUserDelete(context.Background(), u.Id, u.Status, client.oktaClient)
+		})
+		if err != nil {
+			errorList = append(errorList, err)
+		}
 	}
 	return condenseError(errorList)
 }
@@ -529,13 +891,16 @@ func sweepUserCustomSchema(client *testClient) error {
 			return err
 		}
 		for key := range schema.Definitions.Custom.Properties {
-			if strings.HasPrefix(key, testResourcePrefix) {
+			if !isSweepable(key) {
+				continue
+			}
+			err := sweepResource("custom schema", typeSchemaID, key, func() error {
 				custom := buildCustomUserSchema(key, nil)
-				_, _, err = client.oktaClient.UserSchema.UpdateUserProfile(context.Background(), typeSchemaID, *custom)
-				if err != nil {
-					return err
-				}
-				logSweptResource("custom schema", typeSchemaID, "-")
+				_, _, err := client.oktaClient.UserSchema.UpdateUserProfile(context.Background(), typeSchemaID, *custom)
+				return err
+			})
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -546,12 +911,15 @@ func sweepUserTypes(client *testClient) error {
 	userTypeList, _, _ := client.oktaClient.UserType.ListUserTypes(context.Background())
 	var errorList []error
 	for _, ut := range userTypeList {
-		if strings.HasPrefix(ut.Name, testResourcePrefix) {
-			if _, err := client.oktaClient.UserType.DeleteUserType(context.Background(), ut.Id); err != nil {
-				errorList = append(errorList, err)
-				continue
-			}
-			logSweptResource("user type", ut.Id, ut.Name)
+		if !isSweepable(ut.Name) {
+			continue
+		}
+		err := sweepResource("user type", ut.Id, ut.Name, func() error {
+			_, err := client.oktaClient.UserType.DeleteUserType(context.Background(), ut.Id)
+			return err
+		})
+		if err != nil {
+			errorList = append(errorList, err)
 		}
 	}
 	return condenseError(errorList)
@@ -565,12 +933,15 @@ func sweepPolicyByType(t string, client *testClient) error {
 	}
 	for _, _policy := range policies {
 		policy := _policy.(*sdk.Policy)
-		if strings.HasPrefix(policy.Name, testResourcePrefix) {
-			_, err = client.oktaClient.Policy.DeletePolicy(ctx, policy.Id)
-			if err != nil {
-				return err
-			}
-			logSweptResource("policy: "+t, policy.Id, policy.Name)
+		if !isSweepable(policy.Name) {
+			continue
+		}
+		err := sweepResource("policy: "+t, policy.Id, policy.Name, func() error {
+			_, err := client.oktaClient.Policy.DeletePolicy(ctx, policy.Id)
+			return err
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -591,12 +962,15 @@ func sweepPolicyRulesByType(ruleType string, client *testClient) error {
 		// Tests have always used default policy, I don't really think that is necessarily a good idea but
 		// leaving for now, that means we only delete the rules and not the policy, we can keep it around.
 		for i := range rules {
-			if strings.HasPrefix(rules[i].Name, testResourcePrefix) {
-				_, err = client.oktaClient.Policy.DeletePolicyRule(ctx, policy.Id, rules[i].Id)
-				if err != nil {
-					return err
-				}
-				logSweptResource("policy rule type: "+ruleType, policy.Id+"/"+rules[i].Id, rules[i].Name)
+			if !isSweepable(rules[i].Name) {
+				continue
+			}
+			err := sweepResource("policy rule type: "+ruleType, policy.Id+"/"+rules[i].Id, rules[i].Name, func() error {
+				_, err := client.oktaClient.Policy.DeletePolicyRule(ctx, policy.Id, rules[i].Id)
+				return err
+			})
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path"
 	"reflect"
 	"strings"
@@ -24,6 +25,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/okta/okta-sdk-golang/v3/okta"
+	"github.com/okta/terraform-provider-okta/okta/internal/emulator"
+	"github.com/okta/terraform-provider-okta/okta/internal/transport"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
@@ -259,6 +262,34 @@ func TestProviderValidate(t *testing.T) {
 	}
 }
 
+func TestProviderValidatePrivateKeys(t *testing.T) {
+	for _, key := range []string{"OKTA_ACCESS_TOKEN", "OKTA_API_TOKEN", "OKTA_API_PRIVATE_KEY", "OKTA_API_CLIENT_ID", "OKTA_API_SCOPES"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	if !Provider().Schema["private_keys"].Sensitive {
+		t.Error("expected private_keys to be sensitive")
+	}
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		expectError bool
+	}{
+		{"private_keys pass", map[string]interface{}{"private_keys": []interface{}{"key"}}, false},
+		{"private_keys fail 1", map[string]interface{}{"private_keys": []interface{}{"key"}, "access_token": "accessToken"}, true},
+		{"private_keys fail 2", map[string]interface{}{"private_keys": []interface{}{"key"}, "api_token": "apiToken"}, true},
+	}
+	for _, test := range tests {
+		err := Provider().Validate(terraform.NewResourceConfigRaw(test.config))
+		if test.expectError && err == nil {
+			t.Errorf("test %q: expected error but received none", test.name)
+		}
+		if !test.expectError && err != nil {
+			t.Errorf("test %q: did not expect error but received error: %+v", test.name, err)
+		}
+	}
+}
+
 // NOTE: Our VCR set up is inspired by terraform-provider-google
 var providerConfigsLock = sync.RWMutex{}
 var providerConfigs map[string]*Config
@@ -330,6 +361,10 @@ func oktaResourceTest(t *testing.T, c resource.TestCase) {
 				mgr.SetCurrentCassette(cassette)
 				c.ProviderFactories = providerFactoriesForTest(mgr)
 				c.CheckDestroy = nil
+				if os.Getenv("OKTA_VCR_LINT") != "" {
+					played := *mgr
+					defer writeCassetteLint(t, &played)
+				}
 				fmt.Printf("=== VCR PLAY CASSETTE %q for %s\n", cassette, t.Name())
 				resource.Test(t, c)
 			}
@@ -340,6 +375,33 @@ func oktaResourceTest(t *testing.T, c resource.TestCase) {
 	resource.Test(t, c)
 }
 
+// oktaEmulatorResourceTest runs the test case against the local Okta API
+// emulator rather than an org or VCR cassettes, see okta/internal/emulator. The
+// emulator only covers users, groups, apps, app grants, group rules, policies
+// and authorization servers. Terraform still runs the test, it is skipped when
+// there isn't a terraform binary.
+func oktaEmulatorResourceTest(t *testing.T, c resource.TestCase) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") == "" {
+		if _, err := exec.LookPath("terraform"); err != nil {
+			t.Skipf("%q test needs a terraform binary on the PATH or at TF_ACC_TERRAFORM_PATH, skipping test", t.Name())
+			return
+		}
+	}
+	server := emulator.NewServer()
+	defer server.Close()
+
+	t.Setenv("TF_ACC", "1")
+	t.Setenv("OKTA_VCR_TF_ACC", "")
+	t.Setenv("OKTA_ORG_NAME", "emulator")
+	t.Setenv("OKTA_BASE_URL", "example.com")
+	t.Setenv("OKTA_HTTP_PROXY", server.URL)
+	t.Setenv("OKTA_API_TOKEN", "token")
+	for _, env := range []string{"OKTA_ACCESS_TOKEN", "OKTA_API_CLIENT_ID", "OKTA_API_PRIVATE_KEY", "OKTA_API_PRIVATE_KEY_ID", "OKTA_API_SCOPES"} {
+		t.Setenv(env, "")
+	}
+	resource.Test(t, c)
+}
+
 // providerFactoriesForTest Returns the overriden the provider factories used by
 // the resource test case given the state of the VCR manager.
 func providerFactoriesForTest(mgr *vcrManager) map[string]func() (*schema.Provider, error) {
@@ -351,6 +413,8 @@ func providerFactoriesForTest(mgr *vcrManager) map[string]func() (*schema.Provid
 				config, _diag := getCachedConfig(ctx, d, oldConfigureContextFunc, mgr)
 				config.orgName = mgr.CurrentCassette
 				config.oktaClient.GetConfig().Okta.Client.OrgUrl = fmt.Sprintf("https://%v.%v", config.orgName, config.domain)
+				config.v3Client.GetConfig().Okta.Client.OrgUrl = fmt.Sprintf("https://%v.%v", config.orgName, config.domain)
+				config.v3Client.GetConfig().Host = fmt.Sprintf("%v.%v", config.orgName, config.domain)
 				return config, _diag
 			}
 
@@ -379,61 +443,35 @@ func getCachedConfig(ctx context.Context, d *schema.ResourceData, configureFunc
 	}
 
 	config := c.(*Config)
-	transport := config.oktaClient.GetConfig().HttpClient.Transport
-
-	rec, err := recorder.NewAsMode(mgr.CassettePath(), mgr.VCRMode(), transport)
+	rec, err := recorder.NewAsMode(mgr.CassettePath(), mgr.VCRMode(), config.roundTripper)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
 
-	// Defines how VCR will match requests to responses.
+	var lint *cassetteLint
+	if mgr.IsPlaying() && os.Getenv("OKTA_VCR_LINT") != "" {
+		lint, err = newCassetteLint(mgr)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
 	rec.SetMatcher(func(r *http.Request, i cassette.Request) bool {
-		// Default matcher compares method and URL only
-		if !cassette.DefaultMatcher(r, i) {
-			return false
-		}
-		// TODO: there might be header inform would could to inspect to make this more precise
-		if r.Body == nil {
-			return true
-		}
-
-		var b bytes.Buffer
-		if _, err := b.ReadFrom(r.Body); err != nil {
-			log.Printf("[DEBUG] Failed to read request body from cassette: %v", err)
-			return false
-		}
-		r.Body = io.NopCloser(&b)
-		reqBody := b.String()
-		// If body matches identically, we are done
-		if reqBody == i.Body {
-			return true
-		}
-
-		// JSON might be the same, but reordered. Try parsing json and comparing
-		contentType := r.Header.Get("Content-Type")
-		if strings.Contains(contentType, "application/json") {
-			var reqJson, cassetteJson interface{}
-			if err := json.Unmarshal([]byte(reqBody), &reqJson); err != nil {
-				log.Printf("[DEBUG] Failed to unmarshall request json: %v", err)
-				return false
-			}
-			if err := json.Unmarshal([]byte(i.Body), &cassetteJson); err != nil {
-				log.Printf("[DEBUG] Failed to unmarshall cassette json: %v", err)
-				return false
-			}
-			return reflect.DeepEqual(reqJson, cassetteJson)
+		matched, loose := matchCassetteRequest(r, i)
+		if matched && lint != nil {
+			lint.matched(i, loose)
 		}
-
-		return true
+		return matched
 	})
 
 	rec.AddSaveFilter(func(i *cassette.Interaction) error {
-		authHeader := "Authorization"
-		if auth, ok := firstHeaderValue(authHeader, i.Request.Headers); ok {
-			i.Request.Headers.Del(authHeader)
-			parts := strings.Split(auth, " ")
-			i.Request.Headers.Set("Authorization", fmt.Sprintf("%s REDACTED", parts[0]))
-		}
+		// the same secrets are redacted as in the logs, e.g. the token and
+		// client assertion of the private key flow and client secrets
+		redactor := transport.OktaSecrets
+		i.Request.Headers = redactor.Headers(i.Request.Headers)
+		i.Request.URL = redactor.URL(i.Request.URL)
+		i.Request.Form = redactor.Values(i.Request.Form)
+		i.Request.Body = string(redactor.Body(i.Request.Headers.Get("Content-Type"), []byte(i.Request.Body)))
+		i.Response.Body = string(redactor.Body(i.Response.Headers.Get("Content-Type"), []byte(i.Response.Body)))
 
 		// save disk space, clean up what gets written to disk
 		deleteResponseHeaders := []string{"duration", "Content-Security-Policy", "Cache-Control", "Expect-Ct", "Expires", "P3p", "Pragma", "Public-Key-Pins-Report-Only", "Server", "Set-Cookie", "Strict-Transport-Security", "Vary"}
@@ -463,13 +501,75 @@ func getCachedConfig(ctx context.Context, d *schema.ResourceData, configureFunc
 		return nil
 	})
 
-	config.oktaClient.GetConfig().HttpClient.Transport = rec
+	if lint != nil {
+		useRecorder(config, &lintTransport{rec: rec, lint: lint})
+	} else {
+		useRecorder(config, rec)
+	}
 	providerConfigsLock.Lock()
 	providerConfigs[mgr.TestAndCassetteName()] = config
 	providerConfigsLock.Unlock()
 	return config, nil
 }
 
+// matchCassetteRequest defines how VCR will match requests to responses. A
+// match is loose if it only falls back on the method and URL because the
+// bodies can't be compared.
+func matchCassetteRequest(r *http.Request, i cassette.Request) (matched, loose bool) {
+	// Default matcher compares method and URL only
+	if !cassette.DefaultMatcher(r, i) {
+		return false, false
+	}
+	// TODO: there might be header inform would could to inspect to make this more precise
+	if r.Body == nil {
+		return true, i.Body != ""
+	}
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(r.Body); err != nil {
+		log.Printf("[DEBUG] Failed to read request body from cassette: %v", err)
+		return false, false
+	}
+	r.Body = io.NopCloser(&b)
+	reqBody := b.String()
+	// If body matches identically, we are done
+	if reqBody == i.Body {
+		return true, false
+	}
+
+	// JSON might be the same, but reordered. Try parsing json and comparing
+	contentType := r.Header.Get("Content-Type")
+	if strings.Contains(contentType, "application/json") {
+		var reqJson, cassetteJson interface{}
+		if err := json.Unmarshal([]byte(reqBody), &reqJson); err != nil {
+			log.Printf("[DEBUG] Failed to unmarshall request json: %v", err)
+			return false, false
+		}
+		if err := json.Unmarshal([]byte(i.Body), &cassetteJson); err != nil {
+			log.Printf("[DEBUG] Failed to unmarshall cassette json: %v", err)
+			return false, false
+		}
+		// the secrets of the request were redacted when it was saved
+		transport.OktaSecrets.JSON(reqJson)
+		transport.OktaSecrets.JSON(cassetteJson)
+		return reflect.DeepEqual(reqJson, cassetteJson), false
+	}
+
+	return true, true
+}
+
+// useRecorder has the v2 client, its API supplement and the v3 client make
+// their calls through the one recorder, so that a cassette holds all of the
+// calls of a test and a test plays back without an org.
+func useRecorder(config *Config, rec http.RoundTripper) {
+	config.roundTripper = rec
+	config.oktaClient.GetConfig().HttpClient.Transport = rec
+	config.supplementClient = &sdk.APISupplement{
+		RequestExecutor: config.oktaClient.CloneRequestExecutor(),
+	}
+	config.v3Client.GetConfig().HTTPClient.Transport = transport.NewCoalescingTransport(rec)
+}
+
 func replaceHeaderValues(currentValues []string, oldValue, newValue string) []string {
 	result := []string{}
 	for _, text := range currentValues {
@@ -478,13 +578,6 @@ func replaceHeaderValues(currentValues []string, oldValue, newValue string) []st
 	return result
 }
 
-func firstHeaderValue(name string, headers http.Header) (string, bool) {
-	if vals, ok := headers[name]; ok && len(vals) > 0 {
-		return vals[0], true
-	}
-	return "", false
-}
-
 // closeRecorder closes the VCR recorder to save the cassette file
 func closeRecorder(t *testing.T, vcr *vcrManager) {
 	providerConfigsLock.RLock()
@@ -613,3 +706,44 @@ type vcrManager struct {
 	CurrentCassette string
 	VCRModeName     string
 }
+
+func TestUseRecorder(t *testing.T) {
+	cassettePath := path.Join(t.TempDir(), "cassette")
+	interactions := []string{
+		"/api/v1/users/me", `{"id":"00u1"}`,
+		"/.well-known/okta-organization", `{"id":"00o1","pipeline":"idx"}`,
+		"/api/v1/groups/00g1", `{"id":"00g1"}`,
+	}
+	yaml := "---\nversion: 1\ninteractions:\n"
+	for i := 0; i < len(interactions); i += 2 {
+		yaml += fmt.Sprintf("- request:\n    body: \"\"\n    url: https://test.okta.com%s\n    method: GET\n  response:\n    body: '%s'\n    headers:\n      Content-Type:\n      - application/json\n    status: 200 OK\n    code: 200\n", interactions[i], interactions[i+1])
+	}
+	if err := os.WriteFile(cassettePath+".yaml", []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		orgName:     "test",
+		domain:      "okta.com",
+		accessToken: "accessToken",
+	}
+	if err := config.loadAndValidate(context.TODO()); err != nil {
+		t.Fatalf("did not expect error but received error: %+v", err)
+	}
+	rec, err := recorder.NewAsMode(cassettePath, recorder.ModeReplaying, config.roundTripper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	useRecorder(config, rec)
+
+	// every client path plays back from the cassette without an org
+	if user, _, err := config.oktaClient.User.GetUser(context.TODO(), "me"); err != nil || user.Id != "00u1" {
+		t.Errorf("expected the v2 client to play back the user, got %+v", err)
+	}
+	if org, _, err := config.supplementClient.GetWellKnownOktaOrganization(context.TODO()); err != nil || org.Id != "00o1" {
+		t.Errorf("expected the API supplement to play back the org, got %+v", err)
+	}
+	if group, _, err := config.v3Client.GroupApi.GetGroup(context.TODO(), "00g1").Execute(); err != nil || group.GetId() != "00g1" {
+		t.Errorf("expected the v3 client to play back the group, got %+v", err)
+	}
+}
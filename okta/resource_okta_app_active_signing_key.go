@@ -0,0 +1,112 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourceAppActiveSigningKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppActiveSigningKeyCreate,
+		ReadContext:   resourceAppActiveSigningKeyRead,
+		UpdateContext: resourceAppActiveSigningKeyUpdate,
+		// An application always signs with a key, the key stays active.
+		DeleteContext: resourceFuncNoOp,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Application ID",
+			},
+			"kid": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the key the application signs with",
+			},
+		},
+	}
+}
+
+func resourceAppActiveSigningKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	if err := setAppSigningKid(ctx, m, appID, d.Get("kid").(string)); err != nil {
+		return diag.Errorf("failed to set signing key of application %s: %v", appID, err)
+	}
+	d.SetId(appID)
+	return resourceAppActiveSigningKeyRead(ctx, d, m)
+}
+
+func resourceAppActiveSigningKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	app, resp, err := getRawApp(ctx, m, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get application %s: %v", d.Id(), err)
+	}
+	if app == nil {
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("app_id", d.Id())
+	credentials, _ := app["credentials"].(map[string]interface{})
+	signing, _ := credentials["signing"].(map[string]interface{})
+	kid, _ := signing["kid"].(string)
+	_ = d.Set("kid", kid)
+	return nil
+}
+
+func resourceAppActiveSigningKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := setAppSigningKid(ctx, m, d.Id(), d.Get("kid").(string)); err != nil {
+		return diag.Errorf("failed to set signing key of application %s: %v", d.Id(), err)
+	}
+	return resourceAppActiveSigningKeyRead(ctx, d, m)
+}
+
+// setAppSigningKid sets the signing key of the application. The application
+// is updated as it is returned by Okta, the typed applications of the SDK
+// don't hold the settings of every sign on mode.
+func setAppSigningKid(ctx context.Context, m interface{}, appID, kid string) error {
+	app, _, err := getRawApp(ctx, m, appID)
+	if err != nil {
+		return err
+	}
+	credentials, _ := app["credentials"].(map[string]interface{})
+	if credentials == nil {
+		credentials = map[string]interface{}{}
+		app["credentials"] = credentials
+	}
+	signing, _ := credentials["signing"].(map[string]interface{})
+	if signing == nil {
+		signing = map[string]interface{}{}
+		credentials["signing"] = signing
+	}
+	signing["kid"] = kid
+	re := getRequestExecutor(m)
+	req, err := re.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/apps/%s", appID), app)
+	if err != nil {
+		return err
+	}
+	_, err = re.Do(ctx, req, nil)
+	return err
+}
+
+func getRawApp(ctx context.Context, m interface{}, appID string) (map[string]interface{}, *sdk.Response, error) {
+	re := getRequestExecutor(m)
+	req, err := re.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/apps/%s", appID), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var app map[string]interface{}
+	resp, err := re.Do(ctx, req, &app)
+	if err != nil {
+		return nil, resp, err
+	}
+	return app, resp, nil
+}
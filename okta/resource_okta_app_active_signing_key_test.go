@@ -0,0 +1,38 @@
+package okta
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestAccResourceOktaAppActiveSigningKey_crud(t *testing.T) {
+	mgr := newFixtureManager(appActiveSigningKey, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", appActiveSigningKey)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      createCheckResourceDestroy(appSaml, createDoesAppExist(sdk.NewSamlApplication())),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttrPair(resourceName, "kid", fmt.Sprintf("%s.first", appSigningKey), "kid"),
+			},
+			{
+				Config: updatedConfig,
+				Check:  resource.TestCheckResourceAttrPair(resourceName, "kid", fmt.Sprintf("%s.second", appSigningKey), "kid"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
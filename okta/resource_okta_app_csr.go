@@ -0,0 +1,208 @@
+package okta
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourceAppCSR() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppCSRCreate,
+		ReadContext:   resourceAppCSRRead,
+		UpdateContext: resourceAppCSRUpdate,
+		DeleteContext: resourceAppCSRDelete,
+		Importer:      createNestedResourceImporter([]string{"app_id", "id"}),
+		Schema: buildSchema(map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Application ID",
+			},
+			"subject": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Subject of the certificate",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"common_name": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"country_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"state_or_province_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"locality_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"organization_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"organizational_unit_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"dns_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "DNS names of the subject alternative name extension of the certificate",
+			},
+			"certificate": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: stringIsPEMCertificate,
+				Description:      "PEM encoded certificate signed from the CSR, published as a key of the application",
+			},
+			"csr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "PEM encoded CSR",
+			},
+		}, appKeyComputedSchema),
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			// A CSR is published once, a new certificate needs a new CSR.
+			if old, _ := d.GetChange("certificate"); old.(string) != "" && d.HasChange("certificate") {
+				return d.ForceNew("certificate")
+			}
+			return nil
+		},
+	}
+}
+
+func resourceAppCSRCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	csr, _, err := getOktaClientFromMetadata(m).Application.GenerateCsrForApplication(ctx, appID, buildCSRMetadata(d))
+	if err != nil {
+		return diag.Errorf("failed to generate CSR of application %s: %v", appID, err)
+	}
+	d.SetId(csr.Id)
+	_ = d.Set("csr", csrToPEM(csr.Csr))
+	if _, ok := d.GetOk("certificate"); ok {
+		if err := publishAppCSR(ctx, d, m); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceAppCSRRead(ctx, d, m)
+}
+
+// resourceAppCSRRead reads the CSR until it is published, then the key of the
+// certificate.
+func resourceAppCSRRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	client := getOktaClientFromMetadata(m)
+	if kid := d.Get("kid").(string); kid != "" {
+		key, resp, err := client.Application.GetApplicationKey(ctx, appID, kid)
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return diag.Errorf("failed to get key of application %s: %v", appID, err)
+		}
+		if key == nil {
+			d.SetId("")
+			return nil
+		}
+		if err := setAppKey(d, key); err != nil {
+			return diag.Errorf("failed to set key of application %s: %v", appID, err)
+		}
+		return nil
+	}
+	csr, resp, err := client.Application.GetCsrForApplication(ctx, appID, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get CSR of application %s: %v", appID, err)
+	}
+	if csr == nil {
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("csr", csrToPEM(csr.Csr))
+	_ = d.Set("kty", csr.Kty)
+	if csr.Created != nil {
+		_ = d.Set("created", csr.Created.UTC().String())
+	}
+	return nil
+}
+
+func resourceAppCSRUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("certificate") {
+		if err := publishAppCSR(ctx, d, m); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceAppCSRRead(ctx, d, m)
+}
+
+// resourceAppCSRDelete revokes the CSR if it isn't published, Okta doesn't
+// delete the keys of published certificates.
+func resourceAppCSRDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.Get("kid").(string) != "" {
+		return nil
+	}
+	appID := d.Get("app_id").(string)
+	resp, err := getOktaClientFromMetadata(m).Application.RevokeCsrFromApplication(ctx, appID, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to revoke CSR of application %s: %v", appID, err)
+	}
+	return nil
+}
+
+func publishAppCSR(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	appID := d.Get("app_id").(string)
+	key, _, err := getOktaClientFromMetadata(m).Application.PublishBinaryPemCert(ctx, appID, d.Id(), d.Get("certificate").(string))
+	if err != nil {
+		return fmt.Errorf("failed to publish certificate of CSR of application %s: %v", appID, err)
+	}
+	_ = d.Set("kid", key.Kid)
+	return nil
+}
+
+func buildCSRMetadata(d *schema.ResourceData) sdk.CsrMetadata {
+	subject := d.Get("subject.0").(map[string]interface{})
+	metadata := sdk.CsrMetadata{
+		Subject: &sdk.CsrMetadataSubject{
+			CommonName:             subject["common_name"].(string),
+			CountryName:            subject["country_name"].(string),
+			StateOrProvinceName:    subject["state_or_province_name"].(string),
+			LocalityName:           subject["locality_name"].(string),
+			OrganizationName:       subject["organization_name"].(string),
+			OrganizationalUnitName: subject["organizational_unit_name"].(string),
+		},
+	}
+	if dnsNames := convertInterfaceToStringArrNullable(d.Get("dns_names")); len(dnsNames) > 0 {
+		metadata.SubjectAltNames = &sdk.CsrMetadataSubjectAltNames{DnsNames: dnsNames}
+	}
+	return metadata
+}
+
+// csrToPEM returns the PEM encoding of the base64 encoded DER CSR returned by
+// Okta.
+func csrToPEM(csr string) string {
+	der, err := base64.StdEncoding.DecodeString(csr)
+	if err != nil {
+		return csr
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
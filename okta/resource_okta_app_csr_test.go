@@ -0,0 +1,57 @@
+package okta
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestCSRToPEM(t *testing.T) {
+	der := []byte{0x30, 0x82, 0x01, 0x0a}
+	block, _ := pem.Decode([]byte(csrToPEM(base64.StdEncoding.EncodeToString(der))))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" || string(block.Bytes) != string(der) {
+		t.Errorf("unexpected PEM block of the CSR: %+v", block)
+	}
+	if got := csrToPEM("not base64!"); got != "not base64!" {
+		t.Errorf("CSR which isn't base64 is changed to %q", got)
+	}
+}
+
+func TestAccResourceOktaAppCSR_crud(t *testing.T) {
+	mgr := newFixtureManager(appCSR, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	resourceName := fmt.Sprintf("%s.test", appCSR)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      createCheckResourceDestroy(appSaml, createDoesAppExist(sdk.NewSamlApplication())),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr(resourceName, "csr", regexp.MustCompile(`^-----BEGIN CERTIFICATE REQUEST-----`)),
+					resource.TestCheckResourceAttr(resourceName, "kty", "RSA"),
+					resource.TestCheckResourceAttr(resourceName, "kid", ""),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"subject", "dns_names"},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources[resourceName]
+					return fmt.Sprintf("%s/%s", rs.Primary.Attributes["app_id"], rs.Primary.ID), nil
+				},
+			},
+		},
+	})
+}
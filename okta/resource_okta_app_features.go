@@ -0,0 +1,318 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+const (
+	userProvisioning    = "USER_PROVISIONING"
+	inboundProvisioning = "INBOUND_PROVISIONING"
+)
+
+var provisioningStatusSchema = &schema.Schema{
+	Type:             schema.TypeString,
+	Optional:         true,
+	Computed:         true,
+	ValidateDiagFunc: stringInSlice([]string{provisioningEnabled, provisioningDisabled}),
+}
+
+var importScheduleSettingsResource = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"expression": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Cron expression of the import schedule",
+		},
+		"timezone": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "Timezone of the cron expression, e.g. America/New_York",
+		},
+	},
+}
+
+func resourceAppFeatures() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppFeaturesCreate,
+		ReadContext:   resourceAppFeaturesRead,
+		UpdateContext: resourceAppFeaturesUpdate,
+		DeleteContext: resourceFuncNoOp,
+		Importer:      createNestedResourceImporter([]string{"app_id", "name"}),
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Application ID",
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: stringInSlice([]string{userProvisioning, inboundProvisioning}),
+				Description:      "Name of the feature, USER_PROVISIONING or INBOUND_PROVISIONING",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the feature",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Description of the feature",
+			},
+			"create": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "Settings of users created in the app, USER_PROVISIONING only",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"lifecycle_create": withDescription(provisioningStatusSchema, "Whether Okta creates users in the app"),
+					},
+				},
+			},
+			"update": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "Settings of users updated in the app, USER_PROVISIONING only",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"lifecycle_deactivate": withDescription(provisioningStatusSchema, "Whether Okta deactivates users in the app when they're unassigned"),
+						"profile":              withDescription(provisioningStatusSchema, "Whether Okta pushes profile updates to the app"),
+						"password": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Computed:    true,
+							MaxItems:    1,
+							Description: "Password sync settings",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"status": withDescription(provisioningStatusSchema, "Whether Okta syncs passwords to the app"),
+									"seed": {
+										Type:             schema.TypeString,
+										Optional:         true,
+										Computed:         true,
+										ValidateDiagFunc: stringInSlice([]string{"OKTA", "RANDOM"}),
+										Description:      "Password synced to the app, the user's Okta password (OKTA) or a random one (RANDOM)",
+									},
+									"change": {
+										Type:             schema.TypeString,
+										Optional:         true,
+										Computed:         true,
+										ValidateDiagFunc: stringInSlice([]string{"CHANGE", "KEEP_EXISTING"}),
+										Description:      "Whether a synced password replaces the user's password in the app (CHANGE) or not (KEEP_EXISTING)",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"import_schedule": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "Schedule of imports of users from the app, INBOUND_PROVISIONING only",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"status": withDescription(provisioningStatusSchema, "Whether scheduled imports are enabled"),
+						"full_import": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "Schedule of full imports",
+							Elem:        importScheduleSettingsResource,
+						},
+						"incremental_import": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "Schedule of incremental imports",
+							Elem:        importScheduleSettingsResource,
+						},
+					},
+				},
+			},
+		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			config := d.GetRawConfig()
+			if config.IsNull() || !config.GetAttr("name").IsKnown() {
+				return nil
+			}
+			blocks := []string{"import_schedule"}
+			if config.GetAttr("name").AsString() == inboundProvisioning {
+				blocks = []string{"create", "update"}
+			}
+			for _, block := range blocks {
+				if v := config.GetAttr(block); v.IsKnown() && !v.IsNull() && v.LengthInt() > 0 {
+					return fmt.Errorf("'%s' can't be set for the %s feature", block, config.GetAttr("name").AsString())
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// withDescription returns a copy of the schema with the description.
+func withDescription(s *schema.Schema, description string) *schema.Schema {
+	c := *s
+	c.Description = description
+	return &c
+}
+
+func resourceAppFeaturesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID, name := d.Get("app_id").(string), d.Get("name").(string)
+	_, _, err := getOktaClientFromMetadata(m).Application.UpdateFeatureForApplication(ctx, appID, name, buildAppFeatureCapabilities(d))
+	if err != nil {
+		return diag.Errorf("failed to update %s feature of application %s: %v", name, appID, err)
+	}
+	d.SetId(fmt.Sprintf("%s/%s", appID, name))
+	return resourceAppFeaturesRead(ctx, d, m)
+}
+
+func resourceAppFeaturesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID, name := d.Get("app_id").(string), d.Get("name").(string)
+	feature, resp, err := getOktaClientFromMetadata(m).Application.GetFeatureForApplication(ctx, appID, name)
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get %s feature of application %s: %v", name, appID, err)
+	}
+	if feature == nil {
+		d.SetId("")
+		return nil
+	}
+	d.SetId(fmt.Sprintf("%s/%s", appID, name))
+	_ = d.Set("status", feature.Status)
+	_ = d.Set("description", feature.Description)
+	for k, v := range flattenAppFeatureCapabilities(feature.Capabilities) {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("failed to set %s of %s feature of application %s: %v", k, name, appID, err)
+		}
+	}
+	return nil
+}
+
+func resourceAppFeaturesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID, name := d.Get("app_id").(string), d.Get("name").(string)
+	_, _, err := getOktaClientFromMetadata(m).Application.UpdateFeatureForApplication(ctx, appID, name, buildAppFeatureCapabilities(d))
+	if err != nil {
+		return diag.Errorf("failed to update %s feature of application %s: %v", name, appID, err)
+	}
+	return resourceAppFeaturesRead(ctx, d, m)
+}
+
+// buildAppFeatureCapabilities returns the capabilities of the feature, only
+// the blocks of the feature are sent since Okta rejects the others.
+func buildAppFeatureCapabilities(d *schema.ResourceData) sdk.CapabilitiesObject {
+	var caps sdk.CapabilitiesObject
+	if d.Get("name").(string) == inboundProvisioning {
+		if raw, ok := d.GetOk("import_schedule.0"); ok {
+			schedule := raw.(map[string]interface{})
+			caps.ImportSettings = &sdk.CapabilitiesImportSettingsObject{
+				Schedule: &sdk.ImportScheduleObject{
+					Status:            schedule["status"].(string),
+					FullImport:        buildImportScheduleSettings(schedule["full_import"]),
+					IncrementalImport: buildImportScheduleSettings(schedule["incremental_import"]),
+				},
+			}
+		}
+		return caps
+	}
+	if raw, ok := d.GetOk("create.0"); ok {
+		create := raw.(map[string]interface{})
+		caps.Create = &sdk.CapabilitiesCreateObject{
+			LifecycleCreate: &sdk.LifecycleCreateSettingObject{Status: create["lifecycle_create"].(string)},
+		}
+	}
+	if raw, ok := d.GetOk("update.0"); ok {
+		update := raw.(map[string]interface{})
+		caps.Update = &sdk.CapabilitiesUpdateObject{
+			LifecycleDeactivate: &sdk.LifecycleDeactivateSettingObject{Status: update["lifecycle_deactivate"].(string)},
+			Profile:             &sdk.ProfileSettingObject{Status: update["profile"].(string)},
+		}
+		if passwords := update["password"].([]interface{}); len(passwords) > 0 && passwords[0] != nil {
+			password := passwords[0].(map[string]interface{})
+			caps.Update.Password = &sdk.PasswordSettingObject{
+				Status: password["status"].(string),
+				Seed:   password["seed"].(string),
+				Change: password["change"].(string),
+			}
+		}
+	}
+	return caps
+}
+
+func buildImportScheduleSettings(raw interface{}) *sdk.ImportScheduleSettings {
+	settings, ok := raw.([]interface{})
+	if !ok || len(settings) == 0 || settings[0] == nil {
+		return nil
+	}
+	s := settings[0].(map[string]interface{})
+	return &sdk.ImportScheduleSettings{
+		Expression: s["expression"].(string),
+		Timezone:   s["timezone"].(string),
+	}
+}
+
+func flattenAppFeatureCapabilities(caps *sdk.CapabilitiesObject) map[string]interface{} {
+	m := map[string]interface{}{
+		"create":          nil,
+		"update":          nil,
+		"import_schedule": nil,
+	}
+	if caps == nil {
+		return m
+	}
+	if caps.Create != nil && caps.Create.LifecycleCreate != nil {
+		m["create"] = []interface{}{map[string]interface{}{
+			"lifecycle_create": caps.Create.LifecycleCreate.Status,
+		}}
+	}
+	if u := caps.Update; u != nil {
+		update := map[string]interface{}{}
+		if u.LifecycleDeactivate != nil {
+			update["lifecycle_deactivate"] = u.LifecycleDeactivate.Status
+		}
+		if u.Profile != nil {
+			update["profile"] = u.Profile.Status
+		}
+		if u.Password != nil {
+			update["password"] = []interface{}{map[string]interface{}{
+				"status": u.Password.Status,
+				"seed":   u.Password.Seed,
+				"change": u.Password.Change,
+			}}
+		}
+		m["update"] = []interface{}{update}
+	}
+	if caps.ImportSettings != nil && caps.ImportSettings.Schedule != nil {
+		s := caps.ImportSettings.Schedule
+		m["import_schedule"] = []interface{}{map[string]interface{}{
+			"status":             s.Status,
+			"full_import":        flattenImportScheduleSettings(s.FullImport),
+			"incremental_import": flattenImportScheduleSettings(s.IncrementalImport),
+		}}
+	}
+	return m
+}
+
+func flattenImportScheduleSettings(s *sdk.ImportScheduleSettings) []interface{} {
+	if s == nil || s.Expression == "" {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"expression": s.Expression,
+		"timezone":   s.Timezone,
+	}}
+}
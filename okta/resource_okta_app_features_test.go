@@ -0,0 +1,49 @@
+package okta
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestAccResourceOktaAppFeatures_crud(t *testing.T) {
+	mgr := newFixtureManager(appFeatures, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	updatedConfig := mgr.GetFixtures("basic_updated.tf", t)
+	resourceName := fmt.Sprintf("%s.test", appFeatures)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      createCheckResourceDestroy(appSaml, createDoesAppExist(sdk.NewSamlApplication())),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", userProvisioning),
+					resource.TestCheckResourceAttr(resourceName, "create.0.lifecycle_create", provisioningEnabled),
+					resource.TestCheckResourceAttr(resourceName, "update.0.lifecycle_deactivate", provisioningEnabled),
+					resource.TestCheckResourceAttr(resourceName, "update.0.profile", provisioningEnabled),
+					resource.TestCheckResourceAttr(resourceName, "update.0.password.0.status", provisioningEnabled),
+					resource.TestCheckResourceAttr(resourceName, "update.0.password.0.seed", "RANDOM"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "create.0.lifecycle_create", provisioningDisabled),
+					resource.TestCheckResourceAttr(resourceName, "update.0.lifecycle_deactivate", provisioningDisabled),
+					resource.TestCheckResourceAttr(resourceName, "update.0.password.0.status", provisioningDisabled),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
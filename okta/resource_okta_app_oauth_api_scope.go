@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -18,17 +20,31 @@ func resourceAppOAuthAPIScope() *schema.Resource {
 		DeleteContext: resourceAppOAuthAPIScopeDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-				scopes, _, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, d.Id(), nil)
+				// The ID is the app ID, or the app ID and the ID of a custom
+				// authorization server separated by a slash.
+				appID, authServerID, _ := strings.Cut(d.Id(), "/")
+				d.SetId(appID)
+				_ = d.Set("app_id", appID)
+				_ = d.Set("auth_server_id", authServerID)
+				scopes, _, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, appID, nil)
 				if err != nil {
 					return nil, err
 				}
-				_ = d.Set("app_id", d.Id())
-				if len(scopes) > 0 {
+				var issuer string
+				if authServerID != "" {
+					issuer, err = getOAuthApiScopeIssuer(ctx, d, m)
+					if err != nil {
+						return nil, err
+					}
+				} else if len(scopes) > 0 {
 					// Assume issuer is the same for all granted scopes, taking the first
-					_ = d.Set("issuer", scopes[0].Issuer)
-				} else {
+					issuer = scopes[0].Issuer
+				}
+				scopes = filterOAuthApiScopes(scopes, issuer)
+				if len(scopes) == 0 {
 					return nil, errors.New("no application scope found")
 				}
+				_ = d.Set("issuer", issuer)
 				err = setOAuthApiScopes(d, scopes)
 				if err != nil {
 					return nil, err
@@ -37,6 +53,29 @@ func resourceAppOAuthAPIScope() *schema.Resource {
 			},
 		},
 
+		// Setting auth_server_id in place of the issuer of its authorization
+		// server, or the other way around, keeps the grants.
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			if d.Id() == "" || !d.HasChange("auth_server_id") {
+				return nil
+			}
+			if !d.NewValueKnown("auth_server_id") {
+				return d.ForceNew("auth_server_id")
+			}
+			oldIssuer, newIssuer := d.GetChange("issuer")
+			if authServerID := d.Get("auth_server_id").(string); authServerID != "" {
+				authServer, _, err := getOktaClientFromMetadata(m).AuthorizationServer.GetAuthorizationServer(ctx, authServerID)
+				if err != nil {
+					return fmt.Errorf("failed to get authorization server %s: %v", authServerID, err)
+				}
+				newIssuer = authServer.Issuer
+			}
+			if !isSameOAuthApiScopeIssuer(oldIssuer.(string), newIssuer.(string)) {
+				return d.ForceNew("auth_server_id")
+			}
+			return nil
+		},
+
 		Schema: map[string]*schema.Schema{
 			"app_id": {
 				Required:    true,
@@ -45,9 +84,20 @@ func resourceAppOAuthAPIScope() *schema.Resource {
 				ForceNew:    true,
 			},
 			"issuer": {
-				Required:    true,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Type:         schema.TypeString,
+				Description:  "The issuer of your Org Authorization Server, your Org URL, or of a custom authorization server.",
+				ExactlyOneOf: []string{"issuer", "auth_server_id"},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return isSameOAuthApiScopeIssuer(old, new)
+				},
+			},
+			"auth_server_id": {
+				Optional:    true,
 				Type:        schema.TypeString,
-				Description: "The issuer of your Org Authorization Server, your Org URL.",
+				Description: "ID of the custom authorization server whose scopes are granted, its issuer is the issuer of the grants. Changing it replaces the resource unless the issuer stays the same.",
 			},
 			"scopes": {
 				Type:     schema.TypeSet,
@@ -57,14 +107,35 @@ func resourceAppOAuthAPIScope() *schema.Resource {
 				},
 				Description: "Scopes of the application for which consent is granted.",
 			},
+			"keep_unmanaged_scopes": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave the scopes of the issuer granted outside of the resource alone, rather than revoking them. By default the granted scopes are exactly the configured ones.",
+			},
 		},
 	}
 }
 
+// resourceAppOAuthAPIScopeCreate takes over the configured scopes which are
+// already granted and grants the others.
 func resourceAppOAuthAPIScopeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	issuer, err := getOAuthApiScopeIssuer(ctx, d, m)
+	if err != nil {
+		return diag.Errorf("failed to get issuer of application scope consent grants: %v", err)
+	}
+	_ = d.Set("issuer", issuer)
 	scopes := convertInterfaceToStringSetNullable(d.Get("scopes"))
-	grantScopeList := getOAuthApiScopeList(scopes, d.Get("issuer").(string))
-	err := grantOAuthApiScopes(ctx, d, m, grantScopeList)
+	if err := checkAuthServerScopes(ctx, d, m, scopes); err != nil {
+		return diag.FromErr(err)
+	}
+	current, _, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, d.Get("app_id").(string), nil)
+	if err != nil {
+		return diag.Errorf("failed to get application scope consent grants: %v", err)
+	}
+	grantList, _ := getOAuthApiScopeUpdateLists(d, filterOAuthApiScopes(current, issuer))
+	grantScopeList := getOAuthApiScopeList(grantList, issuer)
+	err = grantOAuthApiScopes(ctx, d, m, grantScopeList)
 	if err != nil {
 		return diag.Errorf("failed to create application scope consent grant: %v", err)
 	}
@@ -72,12 +143,16 @@ func resourceAppOAuthAPIScopeCreate(ctx context.Context, d *schema.ResourceData,
 }
 
 func resourceAppOAuthAPIScopeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	scopes, _, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, d.Get("app_id").(string), nil)
-	if err != nil {
+	scopes, resp, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, d.Get("app_id").(string), nil)
+	if err := suppressErrorOn404(resp, err); err != nil {
 		return diag.Errorf("failed to get application scope consent grants: %v", err)
 	}
 
-	if scopes == nil {
+	scopes = filterOAuthApiScopes(scopes, d.Get("issuer").(string))
+	if d.Get("keep_unmanaged_scopes").(bool) {
+		scopes = managedOAuthApiScopes(d, scopes)
+	}
+	if len(scopes) == 0 {
 		d.SetId("")
 		return nil
 	}
@@ -95,8 +170,11 @@ func resourceAppOAuthAPIScopeUpdate(ctx context.Context, d *schema.ResourceData,
 	if err != nil {
 		return diag.Errorf("failed to get application scope consent grants: %v", err)
 	}
+	if err := checkAuthServerScopes(ctx, d, m, convertInterfaceToStringSetNullable(d.Get("scopes"))); err != nil {
+		return diag.FromErr(err)
+	}
 
-	grantList, revokeList := getOAuthApiScopeUpdateLists(d, scopes)
+	grantList, revokeList := getOAuthApiScopeUpdateLists(d, filterOAuthApiScopes(scopes, d.Get("issuer").(string)))
 	grantScopeList := getOAuthApiScopeList(grantList, d.Get("issuer").(string))
 	err = grantOAuthApiScopes(ctx, d, m, grantScopeList)
 	if err != nil {
@@ -110,7 +188,9 @@ func resourceAppOAuthAPIScopeUpdate(ctx context.Context, d *schema.ResourceData,
 
 	revokeListIds := make([]string, 0)
 	for _, scope := range revokeList {
-		revokeListIds = append(revokeListIds, scopeMap[scope])
+		if id, ok := scopeMap[scope]; ok {
+			revokeListIds = append(revokeListIds, id)
+		}
 	}
 	err = revokeOAuthApiScope(ctx, d, m, revokeListIds)
 	if err != nil {
@@ -128,7 +208,9 @@ func resourceAppOAuthAPIScopeDelete(ctx context.Context, d *schema.ResourceData,
 	revokeListIds := make([]string, 0)
 	scopes := convertInterfaceToStringSetNullable(d.Get("scopes"))
 	for _, scope := range scopes {
-		revokeListIds = append(revokeListIds, scopeMap[scope])
+		if id, ok := scopeMap[scope]; ok {
+			revokeListIds = append(revokeListIds, id)
+		}
 	}
 	err = revokeOAuthApiScope(ctx, d, m, revokeListIds)
 	if err != nil {
@@ -156,14 +238,14 @@ func getOAuthApiScopeList(scopeIds []string, issuer string) []*sdk.OAuth2ScopeCo
 	return result
 }
 
-// Fetches current granted application scopes and returns a map with names and IDs.
+// Fetches current granted application scopes of the issuer and returns a map with names and IDs.
 func getOAuthApiScopeIdMap(ctx context.Context, d *schema.ResourceData, m interface{}) (map[string]string, error) {
 	result := make(map[string]string)
 	currentScopes, resp, err := getOktaClientFromMetadata(m).Application.ListScopeConsentGrants(ctx, d.Get("app_id").(string), nil)
 	if err := suppressErrorOn404(resp, err); err != nil {
 		return nil, fmt.Errorf("failed to get application scope consent grants: %v", err)
 	}
-	for _, currentScope := range currentScopes {
+	for _, currentScope := range filterOAuthApiScopes(currentScopes, d.Get("issuer").(string)) {
 		result[currentScope.ScopeId] = currentScope.Id
 	}
 	return result, nil
@@ -181,11 +263,84 @@ func setOAuthApiScopes(d *schema.ResourceData, to []*sdk.OAuth2ScopeConsentGrant
 }
 
 // Grant a list of scopes to an OAuth application. For convenience this function takes a list of OAuth2ScopeConsentGrant structs.
+// A scope granted in the meantime isn't an error, the grant is taken over.
 func grantOAuthApiScopes(ctx context.Context, d *schema.ResourceData, m interface{}, scopeGrants []*sdk.OAuth2ScopeConsentGrant) error {
+	client := getOktaClientFromMetadata(m)
+	appID := d.Get("app_id").(string)
 	for _, scopeGrant := range scopeGrants {
-		_, _, err := getOktaClientFromMetadata(m).Application.GrantConsentToScope(ctx, d.Get("app_id").(string), *scopeGrant)
-		if err != nil {
-			return fmt.Errorf("failed to grant application api scope: %v", err)
+		_, resp, err := client.Application.GrantConsentToScope(ctx, appID, *scopeGrant)
+		if err == nil {
+			continue
+		}
+		if resp != nil && (resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusConflict) {
+			current, _, listErr := client.Application.ListScopeConsentGrants(ctx, appID, nil)
+			if listErr == nil && containsOAuthApiScope(filterOAuthApiScopes(current, scopeGrant.Issuer), scopeGrant.ScopeId) {
+				continue
+			}
+		}
+		return fmt.Errorf("failed to grant application api scope: %v", err)
+	}
+	return nil
+}
+
+func containsOAuthApiScope(grants []*sdk.OAuth2ScopeConsentGrant, scopeID string) bool {
+	for _, grant := range grants {
+		if grant.ScopeId == scopeID {
+			return true
+		}
+	}
+	return false
+}
+
+// filterOAuthApiScopes returns the grants of the issuer.
+func filterOAuthApiScopes(grants []*sdk.OAuth2ScopeConsentGrant, issuer string) []*sdk.OAuth2ScopeConsentGrant {
+	var result []*sdk.OAuth2ScopeConsentGrant
+	for _, grant := range grants {
+		if isSameOAuthApiScopeIssuer(grant.Issuer, issuer) {
+			result = append(result, grant)
+		}
+	}
+	return result
+}
+
+// isSameOAuthApiScopeIssuer tells if the issuers are the same, with or
+// without a trailing slash.
+func isSameOAuthApiScopeIssuer(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+// getOAuthApiScopeIssuer returns the issuer of the grants, the one of the
+// custom authorization server if it is set.
+func getOAuthApiScopeIssuer(ctx context.Context, d *schema.ResourceData, m interface{}) (string, error) {
+	authServerID, ok := d.GetOk("auth_server_id")
+	if !ok {
+		return d.Get("issuer").(string), nil
+	}
+	authServer, _, err := getOktaClientFromMetadata(m).AuthorizationServer.GetAuthorizationServer(ctx, authServerID.(string))
+	if err != nil {
+		return "", fmt.Errorf("failed to get authorization server %s: %v", authServerID, err)
+	}
+	return authServer.Issuer, nil
+}
+
+// checkAuthServerScopes checks the scopes exist on the custom authorization
+// server, if it is set.
+func checkAuthServerScopes(ctx context.Context, d *schema.ResourceData, m interface{}, scopes []string) error {
+	authServerID, ok := d.GetOk("auth_server_id")
+	if !ok {
+		return nil
+	}
+	authServerScopes, _, err := getOktaClientFromMetadata(m).AuthorizationServer.ListOAuth2Scopes(ctx, authServerID.(string), nil)
+	if err != nil {
+		return fmt.Errorf("failed to list scopes of authorization server %s: %v", authServerID, err)
+	}
+	names := make([]string, len(authServerScopes))
+	for i := range authServerScopes {
+		names[i] = authServerScopes[i].Name
+	}
+	for _, scope := range scopes {
+		if !contains(names, scope) {
+			return fmt.Errorf("scope %s doesn't exist on authorization server %s", scope, authServerID)
 		}
 	}
 	return nil
@@ -202,7 +357,9 @@ func revokeOAuthApiScope(ctx context.Context, d *schema.ResourceData, m interfac
 	return nil
 }
 
-// Diff function to identify which scope needs to be added or removed to the application
+// Diff function to identify which scope needs to be added or removed to the application.
+// With keep_unmanaged_scopes only the scopes removed from the resource are
+// revoked, the scopes granted outside of it are kept.
 func getOAuthApiScopeUpdateLists(d *schema.ResourceData, from []*sdk.OAuth2ScopeConsentGrant) (grantList, revokeList []string) {
 	desiredScopes := make([]string, 0)
 	currentScopes := make([]string, 0)
@@ -215,6 +372,33 @@ func getOAuthApiScopeUpdateLists(d *schema.ResourceData, from []*sdk.OAuth2Scope
 		currentScopes = append(currentScopes, currentScope.ScopeId)
 	}
 
-	// return scopes that should be added or removed
-	return splitTargets(desiredScopes, currentScopes)
+	grantList, revokeList = splitTargets(desiredScopes, currentScopes)
+	if !d.Get("keep_unmanaged_scopes").(bool) {
+		return grantList, revokeList
+	}
+	oldScopes, _ := d.GetChange("scopes")
+	managedScopes := convertInterfaceToStringSetNullable(oldScopes)
+	var managedRevokeList []string
+	for _, scope := range revokeList {
+		if contains(managedScopes, scope) {
+			managedRevokeList = append(managedRevokeList, scope)
+		}
+	}
+	return grantList, managedRevokeList
+}
+
+// managedOAuthApiScopes returns the grants of the scopes the resource
+// manages, all of them if it doesn't manage any yet, e.g. on import.
+func managedOAuthApiScopes(d *schema.ResourceData, grants []*sdk.OAuth2ScopeConsentGrant) []*sdk.OAuth2ScopeConsentGrant {
+	managed := convertInterfaceToStringSetNullable(d.Get("scopes"))
+	if len(managed) == 0 {
+		return grants
+	}
+	var result []*sdk.OAuth2ScopeConsentGrant
+	for _, grant := range grants {
+		if contains(managed, grant.ScopeId) {
+			result = append(result, grant)
+		}
+	}
+	return result
 }
@@ -7,6 +7,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/emulator"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
@@ -49,6 +52,198 @@ func TestAccAppOAuthApplication_apiScope(t *testing.T) {
 	})
 }
 
+func TestAccAppOAuthApplication_apiScopeAuthServer(t *testing.T) {
+	mgr := newFixtureManager(appOAuthAPIScope, t.Name())
+	config := mgr.GetFixtures("auth_server.tf", t)
+	resourceName := fmt.Sprintf("%s.test_app_scopes", appOAuthAPIScope)
+	dataSourceName := fmt.Sprintf("data.%s.test", appOAuthAPIScopes)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      createCheckResourceDestroy(appOAuth, createDoesAppExist(sdk.NewOpenIdConnectApplication())),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					ensureResourceExists(resourceName, apiScopeExists),
+					resource.TestCheckResourceAttrPair(resourceName, "issuer", fmt.Sprintf("%s.test", authServer), "issuer"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "scopes.*", "testAcc.read"),
+					resource.TestCheckResourceAttr(dataSourceName, "grants.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "grants.0.scope_id", "testAcc.read"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "grants.0.created_by_id"),
+				),
+			},
+		},
+	})
+}
+
+// TestAppOAuthAPIScopeTakeOver checks the resource takes over the scopes
+// which are already granted, keeping the others with keep_unmanaged_scopes
+// and revoking them without it, on the local Okta API emulator.
+func TestAppOAuthAPIScopeTakeOver(t *testing.T) {
+	server := emulator.NewServer()
+	defer server.Close()
+	p := Provider()
+	diags := p.Configure(context.TODO(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"org_name":   "emulator",
+		"base_url":   "example.com",
+		"api_token":  "token",
+		"http_proxy": server.URL,
+	}))
+	if diags.HasError() {
+		t.Fatalf("failed to configure the provider: %+v", diags)
+	}
+	ctx, m := context.Background(), p.Meta()
+	client := getOktaClientFromMetadata(m)
+
+	app, _, err := client.Application.CreateApplication(ctx, sdk.NewOpenIdConnectApplication(), nil)
+	if err != nil {
+		t.Fatalf("failed to create the app: %v", err)
+	}
+	appID := app.(*sdk.OpenIdConnectApplication).Id
+	const issuer = "https://emulator.example.com"
+	for _, grant := range getOAuthApiScopeList([]string{"okta.users.read", "okta.groups.read"}, issuer) {
+		if _, _, err := client.Application.GrantConsentToScope(ctx, appID, *grant); err != nil {
+			t.Fatalf("failed to grant the scope: %v", err)
+		}
+	}
+
+	r := resourceAppOAuthAPIScope()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id":                appID,
+		"issuer":                issuer,
+		"scopes":                []interface{}{"okta.users.read", "okta.users.manage"},
+		"keep_unmanaged_scopes": true,
+	})
+	if diags := r.CreateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to take over the grants: %+v", diags)
+	}
+	if got := convertInterfaceToStringSet(d.Get("scopes")); len(got) != 2 || contains(got, "okta.groups.read") {
+		t.Errorf("expected only the configured scopes to be taken over, got %v", got)
+	}
+	// removing a scope from the resource only revokes that scope
+	diff, err := r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"app_id":                appID,
+		"issuer":                issuer,
+		"scopes":                []interface{}{"okta.users.read"},
+		"keep_unmanaged_scopes": true,
+	}), m)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+	if _, diags := r.Apply(ctx, d.State(), diff, m); diags.HasError() {
+		t.Fatalf("failed to update the grants: %+v", diags)
+	}
+	grants, _, err := client.Application.ListScopeConsentGrants(ctx, appID, nil)
+	if err != nil {
+		t.Fatalf("failed to list the grants: %v", err)
+	}
+	var granted []string
+	for _, grant := range grants {
+		granted = append(granted, grant.ScopeId)
+	}
+	if len(granted) != 2 || !contains(granted, "okta.users.read") || !contains(granted, "okta.groups.read") {
+		t.Errorf("expected okta.users.manage to be revoked and the scope granted outside of the resource kept, got %v", granted)
+	}
+	// granting a scope granted in the meantime isn't an error
+	if err := grantOAuthApiScopes(ctx, d, m, getOAuthApiScopeList([]string{"okta.users.read"}, issuer)); err != nil {
+		t.Errorf("failed to grant a granted scope: %v", err)
+	}
+	// without keep_unmanaged_scopes the scope granted outside of the resource
+	// is drift, and is revoked
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id": appID,
+		"issuer": issuer,
+		"scopes": []interface{}{"okta.users.read"},
+	})
+	if diags := r.CreateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to take over the grants: %+v", diags)
+	}
+	if got := convertInterfaceToStringSet(d.Get("scopes")); len(got) != 2 || !contains(got, "okta.groups.read") {
+		t.Errorf("expected the scope granted outside of the resource to be read, got %v", got)
+	}
+	diff, err = r.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"app_id": appID,
+		"issuer": issuer,
+		"scopes": []interface{}{"okta.users.read"},
+	}), m)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+	if diff == nil {
+		t.Fatal("expected the scope granted outside of the resource to be a diff")
+	}
+	if _, diags := r.Apply(ctx, d.State(), diff, m); diags.HasError() {
+		t.Fatalf("failed to update the grants: %+v", diags)
+	}
+	grants, _, err = client.Application.ListScopeConsentGrants(ctx, appID, nil)
+	if err != nil {
+		t.Fatalf("failed to list the grants: %v", err)
+	}
+	if len(grants) != 1 || grants[0].ScopeId != "okta.users.read" {
+		t.Errorf("expected only okta.users.read to stay granted, got %+v", grants)
+	}
+
+	authServer, _, err := client.AuthorizationServer.CreateAuthorizationServer(ctx, sdk.AuthorizationServer{Name: "testAcc"})
+	if err != nil {
+		t.Fatalf("failed to create the authorization server: %v", err)
+	}
+	if _, _, err := client.AuthorizationServer.CreateOAuth2Scope(ctx, authServer.Id, sdk.OAuth2Scope{Name: "testAcc.read"}); err != nil {
+		t.Fatalf("failed to create the scope: %v", err)
+	}
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id":         appID,
+		"auth_server_id": authServer.Id,
+		"scopes":         []interface{}{"testAcc.read"},
+	})
+	if diags := r.CreateContext(ctx, d, m); diags.HasError() {
+		t.Fatalf("failed to grant the scope of the authorization server: %+v", diags)
+	}
+	if d.Get("issuer") != authServer.Issuer || len(convertInterfaceToStringSet(d.Get("scopes"))) != 1 {
+		t.Errorf("expected the scope of the authorization server's issuer %s, got %v %v", authServer.Issuer, d.Get("issuer"), d.Get("scopes"))
+	}
+
+	// the issuer of the authorization server in place of its ID, with or
+	// without a trailing slash, doesn't replace the resource
+	state := d.State()
+	for _, config := range []map[string]interface{}{
+		{"app_id": appID, "auth_server_id": authServer.Id, "scopes": []interface{}{"testAcc.read"}},
+		{"app_id": appID, "issuer": authServer.Issuer, "scopes": []interface{}{"testAcc.read"}},
+		{"app_id": appID, "issuer": authServer.Issuer + "/", "scopes": []interface{}{"testAcc.read"}},
+	} {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), m)
+		if err != nil {
+			t.Fatalf("failed to diff %v: %v", config, err)
+		}
+		if diff != nil && diff.RequiresNew() {
+			t.Errorf("expected %v not to replace the resource, got %v", config, diff)
+		}
+	}
+	other, _, err := client.AuthorizationServer.CreateAuthorizationServer(ctx, sdk.AuthorizationServer{Name: "testAccOther"})
+	if err != nil {
+		t.Fatalf("failed to create the authorization server: %v", err)
+	}
+	diff, err = r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"app_id": appID, "auth_server_id": other.Id, "scopes": []interface{}{"testAcc.read"},
+	}), m)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Errorf("expected another authorization server to replace the resource, got %v", diff)
+	}
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"app_id":         appID,
+		"auth_server_id": authServer.Id,
+		"scopes":         []interface{}{"testAcc.missing"},
+	})
+	if diags := r.CreateContext(ctx, d, m); !diags.HasError() {
+		t.Error("expected granting a missing scope of the authorization server to fail")
+	}
+}
+
 func apiScopeExists(id string) (bool, error) {
 	client := oktaClientForTest()
 	scopes, _, err := client.Application.ListScopeConsentGrants(context.Background(), id, nil)
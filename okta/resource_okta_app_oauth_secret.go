@@ -0,0 +1,126 @@
+package okta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func resourceAppOAuthSecret() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppOAuthSecretCreate,
+		ReadContext:   resourceAppOAuthSecretRead,
+		UpdateContext: resourceAppOAuthSecretUpdate,
+		DeleteContext: resourceAppOAuthSecretDelete,
+		Importer:      createNestedResourceImporter([]string{"app_id", "id"}),
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "OAuth application ID",
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "Client secret, generated by Okta if not set",
+			},
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          statusActive,
+				ValidateDiagFunc: stringInSlice([]string{statusActive, statusInactive}),
+				Description:      "Status of the client secret, ACTIVE or INACTIVE",
+			},
+			"secret_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hash of the client secret",
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp when the client secret was created",
+			},
+		},
+	}
+}
+
+func resourceAppOAuthSecretCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	client := getOktaClientFromMetadata(m)
+	secret, _, err := client.Application.CreateNewClientSecretForApplication(ctx, appID,
+		sdk.ClientSecretMetadata{ClientSecret: d.Get("secret").(string)})
+	if err != nil {
+		return diag.Errorf("failed to create client secret of OAuth application %s: %v", appID, err)
+	}
+	d.SetId(secret.Id)
+	// The secret is only returned when it is created.
+	_ = d.Set("secret", secret.ClientSecret)
+	if d.Get("status").(string) == statusInactive && secret.Status != statusInactive {
+		_, _, err = client.Application.DeactivateClientSecretForApplication(ctx, appID, secret.Id)
+		if err != nil {
+			return diag.Errorf("failed to deactivate client secret of OAuth application %s: %v", appID, err)
+		}
+	}
+	return resourceAppOAuthSecretRead(ctx, d, m)
+}
+
+func resourceAppOAuthSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	secret, resp, err := getOktaClientFromMetadata(m).Application.GetClientSecretForApplication(ctx, appID, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get client secret of OAuth application %s: %v", appID, err)
+	}
+	if secret == nil {
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("status", secret.Status)
+	_ = d.Set("secret_hash", secret.SecretHash)
+	if secret.Created != nil {
+		_ = d.Set("created", secret.Created.String())
+	}
+	return nil
+}
+
+func resourceAppOAuthSecretUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChange("status") {
+		return resourceAppOAuthSecretRead(ctx, d, m)
+	}
+	appID := d.Get("app_id").(string)
+	client := getOktaClientFromMetadata(m)
+	var err error
+	if d.Get("status").(string) == statusActive {
+		_, _, err = client.Application.ActivateClientSecretForApplication(ctx, appID, d.Id())
+	} else {
+		_, _, err = client.Application.DeactivateClientSecretForApplication(ctx, appID, d.Id())
+	}
+	if err != nil {
+		return diag.Errorf("failed to change status of client secret of OAuth application %s: %v", appID, err)
+	}
+	return resourceAppOAuthSecretRead(ctx, d, m)
+}
+
+// resourceAppOAuthSecretDelete deactivates the secret before deleting it,
+// Okta only deletes inactive secrets.
+func resourceAppOAuthSecretDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	client := getOktaClientFromMetadata(m)
+	if d.Get("status").(string) == statusActive {
+		_, resp, err := client.Application.DeactivateClientSecretForApplication(ctx, appID, d.Id())
+		if err := suppressErrorOn404(resp, err); err != nil {
+			return diag.Errorf("failed to deactivate client secret of OAuth application %s: %v", appID, err)
+		}
+	}
+	resp, err := client.Application.DeleteClientSecretForApplication(ctx, appID, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to delete client secret of OAuth application %s: %v", appID, err)
+	}
+	return nil
+}
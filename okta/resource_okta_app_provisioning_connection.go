@@ -0,0 +1,148 @@
+package okta
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// Statuses of provisioning connections and features of an application.
+const (
+	provisioningEnabled  = "ENABLED"
+	provisioningDisabled = "DISABLED"
+)
+
+func resourceAppProvisioningConnection() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppProvisioningConnectionCreate,
+		ReadContext:   resourceAppProvisioningConnectionRead,
+		UpdateContext: resourceAppProvisioningConnectionUpdate,
+		DeleteContext: resourceAppProvisioningConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Application ID",
+			},
+			"auth_scheme": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "TOKEN",
+				ValidateDiagFunc: stringInSlice([]string{"TOKEN", "OAUTH2"}),
+				Description:      "Authentication scheme of the provisioning connection, TOKEN or OAUTH2",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "API token the connection authenticates to the app with, required when auth_scheme is TOKEN",
+			},
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          statusActive,
+				ValidateDiagFunc: stringInSlice([]string{statusActive, statusInactive}),
+				Description:      "Whether the provisioning connection is enabled, ACTIVE or INACTIVE",
+			},
+		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			if d.Get("auth_scheme").(string) == "TOKEN" && d.NewValueKnown("token") && d.Get("token").(string) == "" {
+				return fmt.Errorf("'token' is required when 'auth_scheme' is TOKEN")
+			}
+			return nil
+		},
+	}
+}
+
+func resourceAppProvisioningConnectionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	if err := setAppProvisioningConnection(ctx, d, m); err != nil {
+		return diag.Errorf("failed to set provisioning connection of application %s: %v", appID, err)
+	}
+	d.SetId(appID)
+	return resourceAppProvisioningConnectionRead(ctx, d, m)
+}
+
+func resourceAppProvisioningConnectionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn, resp, err := getOktaClientFromMetadata(m).Application.GetDefaultProvisioningConnectionForApplication(ctx, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get provisioning connection of application %s: %v", d.Id(), err)
+	}
+	if conn == nil || conn.AuthScheme == "" || conn.AuthScheme == "UNKNOWN" {
+		d.SetId("")
+		return nil
+	}
+	_ = d.Set("app_id", d.Id())
+	_ = d.Set("auth_scheme", conn.AuthScheme)
+	if conn.Status == provisioningEnabled {
+		_ = d.Set("status", statusActive)
+	} else {
+		_ = d.Set("status", statusInactive)
+	}
+	return nil
+}
+
+func resourceAppProvisioningConnectionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("auth_scheme", "token") {
+		if err := setAppProvisioningConnection(ctx, d, m); err != nil {
+			return diag.Errorf("failed to set provisioning connection of application %s: %v", d.Id(), err)
+		}
+		return resourceAppProvisioningConnectionRead(ctx, d, m)
+	}
+	if d.HasChange("status") {
+		if err := setAppProvisioningConnectionStatus(ctx, d.Id(), d.Get("status").(string), m); err != nil {
+			return diag.Errorf("failed to change status of provisioning connection of application %s: %v", d.Id(), err)
+		}
+	}
+	return resourceAppProvisioningConnectionRead(ctx, d, m)
+}
+
+// resourceAppProvisioningConnectionDelete deactivates the connection, an
+// application's default provisioning connection can't be deleted.
+func resourceAppProvisioningConnectionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	resp, err := getOktaClientFromMetadata(m).Application.DeactivateDefaultProvisioningConnectionForApplication(ctx, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to deactivate provisioning connection of application %s: %v", d.Id(), err)
+	}
+	return nil
+}
+
+// setAppProvisioningConnection sets the auth scheme and token of the
+// application's default provisioning connection, activating it along the way
+// if its status is ACTIVE.
+func setAppProvisioningConnection(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	body := sdk.ProvisioningConnectionRequest{
+		Profile: &sdk.ProvisioningConnectionProfile{
+			AuthScheme: d.Get("auth_scheme").(string),
+			Token:      d.Get("token").(string),
+		},
+	}
+	active := d.Get("status").(string) == statusActive
+	conn, _, err := getOktaClientFromMetadata(m).Application.SetDefaultProvisioningConnectionForApplication(ctx,
+		d.Get("app_id").(string), body, &query.Params{Activate: boolPtr(active)})
+	if err != nil {
+		return err
+	}
+	if !active && conn != nil && conn.Status == provisioningEnabled {
+		return setAppProvisioningConnectionStatus(ctx, d.Get("app_id").(string), statusInactive, m)
+	}
+	return nil
+}
+
+func setAppProvisioningConnectionStatus(ctx context.Context, appID, status string, m interface{}) error {
+	client := getOktaClientFromMetadata(m)
+	if status == statusActive {
+		_, err := client.Application.ActivateDefaultProvisioningConnectionForApplication(ctx, appID)
+		return err
+	}
+	_, err := client.Application.DeactivateDefaultProvisioningConnectionForApplication(ctx, appID)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
 )
@@ -402,6 +403,7 @@ func resourceAppSaml() *schema.Resource {
 			Read:   schema.DefaultTimeout(1 * time.Hour),
 			Update: schema.DefaultTimeout(1 * time.Hour),
 		},
+		CustomizeDiff: validateAppSamlAttributeStatements,
 	}
 }
 
@@ -723,6 +725,27 @@ func tryCreateCertificate(ctx context.Context, d *schema.ResourceData, m interfa
 	return nil
 }
 
+// validateAppSamlAttributeStatements validates the Okta expressions of the
+// EXPRESSION attribute statements' values at plan time.
+func validateAppSamlAttributeStatements(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	statements, _ := d.Get("attribute_statements").([]interface{})
+	for i := range statements {
+		if d.Get(fmt.Sprintf("attribute_statements.%d.type", i)).(string) != "EXPRESSION" {
+			continue
+		}
+		values, _ := d.Get(fmt.Sprintf("attribute_statements.%d.values", i)).([]interface{})
+		for j, v := range values {
+			key := fmt.Sprintf("attribute_statements.%d.values.%d", i, j)
+			if s, ok := v.(string); ok && d.NewValueKnown(key) {
+				if err := expressionErrors(key, s, expression.SAMLAttribute); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func validateAppSaml(d *schema.ResourceData) error {
 	jwks, ok := d.GetOk("attribute_statements")
 	if !ok {
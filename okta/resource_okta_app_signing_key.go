@@ -0,0 +1,146 @@
+package okta
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/sdk"
+	"github.com/okta/terraform-provider-okta/sdk/query"
+)
+
+// appKeyComputedSchema is the schema of the computed attributes of an
+// application key credential.
+var appKeyComputedSchema = map[string]*schema.Schema{
+	"kid": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Key ID",
+	},
+	"kty": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Key type",
+	},
+	"use": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Acceptable usage of the certificate",
+	},
+	"created": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Created date",
+	},
+	"expires_at": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Expiration date",
+	},
+	"x5c": {
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "X.509 certificate chain",
+	},
+	"x5t_s256": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "X.509 certificate SHA-256 thumbprint",
+	},
+}
+
+func resourceAppSigningKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAppSigningKeyCreate,
+		ReadContext:   resourceAppSigningKeyRead,
+		// Okta doesn't delete application keys, they expire.
+		DeleteContext: resourceFuncNoOp,
+		Importer:      createNestedResourceImporter([]string{"app_id", "id"}),
+		Schema: buildSchema(map[string]*schema.Schema{
+			"app_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Application ID",
+			},
+			"years_valid": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				Default:          2,
+				ValidateDiagFunc: intBetween(2, 10),
+				Description:      "Number of years the generated certificate is valid",
+			},
+			"source_app_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"source_kid"},
+				Description:  "ID of the application the key is cloned from",
+			},
+			"source_kid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"source_app_id"},
+				Description:  "ID of the key cloned from the source application",
+			},
+		}, appKeyComputedSchema),
+	}
+}
+
+func resourceAppSigningKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	client := getOktaClientFromMetadata(m)
+	var (
+		key *sdk.JsonWebKey
+		err error
+	)
+	if sourceKid, ok := d.GetOk("source_kid"); ok {
+		key, _, err = client.Application.CloneApplicationKey(ctx, d.Get("source_app_id").(string), sourceKid.(string),
+			&query.Params{TargetAid: appID})
+		if err != nil {
+			return diag.Errorf("failed to clone key %s to application %s: %v", sourceKid, appID, err)
+		}
+	} else {
+		key, _, err = client.Application.GenerateApplicationKey(ctx, appID,
+			&query.Params{ValidityYears: int64(d.Get("years_valid").(int))})
+		if err != nil {
+			return diag.Errorf("failed to generate key of application %s: %v", appID, err)
+		}
+	}
+	d.SetId(key.Kid)
+	return resourceAppSigningKeyRead(ctx, d, m)
+}
+
+func resourceAppSigningKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	appID := d.Get("app_id").(string)
+	key, resp, err := getOktaClientFromMetadata(m).Application.GetApplicationKey(ctx, appID, d.Id())
+	if err := suppressErrorOn404(resp, err); err != nil {
+		return diag.Errorf("failed to get key of application %s: %v", appID, err)
+	}
+	if key == nil {
+		d.SetId("")
+		return nil
+	}
+	if err := setAppKey(d, key); err != nil {
+		return diag.Errorf("failed to set key of application %s: %v", appID, err)
+	}
+	return nil
+}
+
+// setAppKey sets the computed attributes of an application key credential.
+func setAppKey(d *schema.ResourceData, key *sdk.JsonWebKey) error {
+	_ = d.Set("kid", key.Kid)
+	_ = d.Set("kty", key.Kty)
+	_ = d.Set("use", key.Use)
+	_ = d.Set("x5t_s256", key.X5tS256)
+	if key.Created != nil {
+		_ = d.Set("created", key.Created.UTC().String())
+	}
+	if key.ExpiresAt != nil {
+		_ = d.Set("expires_at", key.ExpiresAt.UTC().String())
+	}
+	return d.Set("x5c", key.X5c)
+}
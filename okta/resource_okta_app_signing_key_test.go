@@ -0,0 +1,35 @@
+package okta
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/okta/terraform-provider-okta/sdk"
+)
+
+func TestAccResourceOktaAppSigningKey_clone(t *testing.T) {
+	mgr := newFixtureManager(appSigningKey, t.Name())
+	config := mgr.GetFixtures("basic.tf", t)
+	resourceName := fmt.Sprintf("%s.test", appSigningKey)
+	cloneName := fmt.Sprintf("%s.clone", appSigningKey)
+
+	oktaResourceTest(t, resource.TestCase{
+		PreCheck:          testAccPreCheck(t),
+		ErrorCheck:        testAccErrorChecks(t),
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      createCheckResourceDestroy(appSaml, createDoesAppExist(sdk.NewSamlApplication())),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "kid"),
+					resource.TestCheckResourceAttr(resourceName, "kty", "RSA"),
+					resource.TestCheckResourceAttr(resourceName, "x5c.#", "1"),
+					resource.TestCheckResourceAttrPair(cloneName, "kid", resourceName, "kid"),
+					resource.TestCheckResourceAttrPair(cloneName, "x5t_s256", resourceName, "x5t_s256"),
+				),
+			},
+		},
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 	"github.com/okta/terraform-provider-okta/sdk"
 )
 
@@ -57,6 +58,12 @@ func resourceAuthServerClaim() *schema.Resource {
 				Description: "Required when value_type is GROUPS",
 			},
 		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			if d.Get("value_type").(string) != "EXPRESSION" || !d.NewValueKnown("value") {
+				return nil
+			}
+			return expressionErrors("value", d.Get("value").(string), expression.Claim)
+		},
 	}
 }
 
@@ -35,6 +35,32 @@ func TestAccOktaGroup_crud(t *testing.T) {
 	})
 }
 
+// TestEmulatorOktaGroup_crud is TestAccOktaGroup_crud on the local Okta API
+// emulator.
+func TestEmulatorOktaGroup_crud(t *testing.T) {
+	resourceName := fmt.Sprintf("%s.test", group)
+	mgr := newFixtureManager(group, t.Name())
+	config := mgr.GetFixtures("okta_group.tf", t)
+	updatedConfig := mgr.GetFixtures("okta_group_updated.tf", t)
+
+	oktaEmulatorResourceTest(t, resource.TestCase{
+		ProviderFactories: testAccProvidersFactories,
+		CheckDestroy:      createCheckResourceDestroy(group, doesGroupExist),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "testAcc")),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", "testAccDifferent")),
+			},
+		},
+	})
+}
+
 func TestAccOktaGroup_customschema(t *testing.T) {
 	resourceName := fmt.Sprintf("%s.test", group)
 	mgr := newFixtureManager(group, t.Name())
@@ -127,12 +127,14 @@ func resourceIdpRead(ctx context.Context, d *schema.ResourceData, m interface{})
 	if idp.IssuerMode != "" {
 		_ = d.Set("issuer_mode", idp.IssuerMode)
 	}
-	mapping, _, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
-	if err != nil {
-		return diag.Errorf("failed to get identity provider profile mapping: %v", err)
-	}
-	if mapping != nil {
-		_ = d.Set("user_type_id", mapping.Target.Id)
+	if !skipForbiddenRead(ctx, m, permProfileMappings, "user_type_id") {
+		mapping, resp, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
+		if err := suppressErrorOnForbidden(ctx, "user_type_id", m, resp, err); err != nil {
+			return diag.Errorf("failed to get identity provider profile mapping: %v", err)
+		}
+		if mapping != nil {
+			_ = d.Set("user_type_id", mapping.Target.Id)
+		}
 	}
 	setMap := map[string]interface{}{
 		"scopes": convertStringSliceToSet(idp.Protocol.Scopes),
@@ -139,12 +139,14 @@ func resourceIdpSamlRead(ctx context.Context, d *schema.ResourceData, m interfac
 	if idp.IssuerMode != "" {
 		_ = d.Set("issuer_mode", idp.IssuerMode)
 	}
-	mapping, resp, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
-	if err := suppressErrorOn401("resource okta_idp_saml.user_type_id", m, resp, err); err != nil {
-		return diag.Errorf("failed to get SAML identity provider profile mapping: %v", err)
-	}
-	if mapping != nil {
-		_ = d.Set("user_type_id", mapping.Target.Id)
+	if !skipForbiddenRead(ctx, m, permProfileMappings, "user_type_id") {
+		mapping, resp, err := getProfileMappingBySourceID(ctx, idp.Id, "", m)
+		if err := suppressErrorOnForbidden(ctx, "user_type_id", m, resp, err); err != nil {
+			return diag.Errorf("failed to get SAML identity provider profile mapping: %v", err)
+		}
+		if mapping != nil {
+			_ = d.Set("user_type_id", mapping.Target.Id)
+		}
 	}
 	setMap := map[string]interface{}{
 		"subject_format": convertStringSliceToSet(idp.Policy.Subject.Format),
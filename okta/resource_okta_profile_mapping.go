@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 	"github.com/okta/terraform-provider-okta/sdk"
 	"github.com/okta/terraform-provider-okta/sdk/query"
 )
@@ -77,8 +78,9 @@ var mappingResource = &schema.Resource{
 			Description: "The mapping property key.",
 		},
 		"expression": {
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: stringIsExpression(expression.ProfileMapping),
 		},
 		"push_status": {
 			Type:     schema.TypeString,
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"reflect"
 	"time"
 
@@ -251,25 +250,23 @@ func listUserRoles(ctx context.Context, c *sdk.Client, userID string) (userOnlyR
 	return
 }
 
-func getRoles(ctx context.Context, id string, c *sdk.Client) ([]interface{}, error) {
+func getRoles(ctx context.Context, id string, m interface{}) ([]interface{}, error) {
 	roleTypes := make([]interface{}, 0)
-	roles, resp, err := listUserRoles(ctx, c, id)
-	if err != nil {
-		if resp != nil && resp.StatusCode == http.StatusForbidden {
-			// no-op
-		} else {
-			return nil, err
-		}
-	} else {
-		for _, role := range roles {
-			roleTypes = append(roleTypes, role.Type)
-		}
+	roles, resp, err := listUserRoles(ctx, getOktaClientFromMetadata(m), id)
+	if err := suppressErrorOnForbidden(ctx, "roles", m, resp, err); err != nil {
+		return nil, err
 	}
-	return roleTypes, err
+	for _, role := range roles {
+		roleTypes = append(roleTypes, role.Type)
+	}
+	return roleTypes, nil
 }
 
 func setRoles(ctx context.Context, d *schema.ResourceData, m interface{}) error {
-	roleTypes, err := getRoles(ctx, d.Id(), getOktaClientFromMetadata(m))
+	if skipForbiddenRead(ctx, m, permRoles, "roles") {
+		return nil
+	}
+	roleTypes, err := getRoles(ctx, d.Id(), m)
 	if err != nil {
 		return fmt.Errorf("failed to get roles: %v", err)
 	}
@@ -293,10 +290,16 @@ func listUserOnlyRoles(ctx context.Context, c *sdk.Client, userID string) (userO
 }
 
 // set all groups currently attached to the user
-func setAllGroups(ctx context.Context, d *schema.ResourceData, c *sdk.Client) error {
-	groupIDs, err := getGroupsForUser(ctx, d.Id(), c)
-	if err != nil {
-		return err
+func setAllGroups(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	if skipForbiddenRead(ctx, m, permGroups, "group_memberships") {
+		return nil
+	}
+	groupIDs, resp, err := getGroupsForUser(ctx, d.Id(), getOktaClientFromMetadata(m))
+	if err := suppressErrorOnForbidden(ctx, "group_memberships", m, resp, err); err != nil {
+		return fmt.Errorf("failed to list user groups: %v", err)
+	}
+	if groupIDs == nil {
+		return nil
 	}
 	gids := convertStringSliceToInterfaceSlice(groupIDs)
 	return setNonPrimitives(d, map[string]interface{}{
@@ -320,8 +323,11 @@ func getAdminRoles(ctx context.Context, id string, c *sdk.Client) ([]interface{}
 }
 
 func setAdminRoles(ctx context.Context, d *schema.ResourceData, m interface{}) error {
+	if skipForbiddenRead(ctx, m, permRoles, "admin_roles") {
+		return nil
+	}
 	roleTypes, resp, err := getAdminRoles(ctx, d.Id(), getOktaClientFromMetadata(m))
-	if err := suppressErrorOn403("setting admin roles", m, resp, err); err != nil {
+	if err := suppressErrorOnForbidden(ctx, "admin_roles", m, resp, err); err != nil {
 		return fmt.Errorf("failed to get admin roles: %v", err)
 	}
 
@@ -331,10 +337,12 @@ func setAdminRoles(ctx context.Context, d *schema.ResourceData, m interface{}) e
 	})
 }
 
-func getGroupsForUser(ctx context.Context, id string, c *sdk.Client) ([]string, error) {
+// getGroupsForUser returns the IDs of the user's groups, and the response of
+// the request that failed if any.
+func getGroupsForUser(ctx context.Context, id string, c *sdk.Client) ([]string, *sdk.Response, error) {
 	groups, response, err := c.User.ListUserGroups(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list user groups: %v", err)
+		return nil, response, err
 	}
 
 	groupIDs := make([]string, 0)
@@ -351,11 +359,11 @@ func getGroupsForUser(ctx context.Context, id string, c *sdk.Client) ([]string,
 		response, err = response.Next(ctx, &groups)
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to list user groups: %v", err)
+			return nil, response, err
 		}
 	}
 
-	return groupIDs, nil
+	return groupIDs, response, nil
 }
 
 func isCustomUserAttr(key string) bool {
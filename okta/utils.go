@@ -295,26 +295,6 @@ func v3suppressErrorOn404(resp *okta.APIResponse, err error) error {
 	return v3responseErr(resp, err)
 }
 
-// Useful shortcut for suppressing errors from Okta's SDK when a Org does not
-// have permission to access a feature.
-func suppressErrorOn401(what string, meta interface{}, resp *sdk.Response, err error) error {
-	if resp != nil && resp.StatusCode == http.StatusUnauthorized {
-		logger(meta).Warn(fmt.Sprintf("Suppressing %q on %q", "401 Unauthorized", what))
-		return nil
-	}
-	return responseErr(resp, err)
-}
-
-// Useful shortcut for suppressing errors from Okta's SDK when a Org does not
-// have permission to access a feature.
-func suppressErrorOn403(what string, meta interface{}, resp *sdk.Response, err error) error {
-	if resp != nil && resp.StatusCode == http.StatusForbidden {
-		logger(meta).Warn(fmt.Sprintf("Suppressing %q on %q", "403 Forbidden", what))
-		return nil
-	}
-	return responseErr(resp, err)
-}
-
 func getOktaClientFromMetadata(meta interface{}) *sdk.Client {
 	return meta.(*Config).oktaClient
 }
@@ -1,12 +1,16 @@
 package okta
 
 import (
+	"encoding/pem"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
 )
 
 func intBetween(min, max int) schema.SchemaValidateDiagFunc {
@@ -35,6 +39,19 @@ func intAtMost(max int) schema.SchemaValidateDiagFunc {
 	}
 }
 
+func stringInSlice(valid []string) schema.SchemaValidateDiagFunc {
+	return func(i interface{}, k cty.Path) diag.Diagnostics {
+		v, ok := i.(string)
+		if !ok {
+			return diag.Errorf("expected type of %v to be string", k)
+		}
+		if !contains(valid, v) {
+			return diag.Errorf("expected %v to be one of %v, got %s", k, valid, v)
+		}
+		return nil
+	}
+}
+
 func logoFileIsValid() schema.SchemaValidateDiagFunc {
 	return func(i interface{}, k cty.Path) diag.Diagnostics {
 		v, ok := i.(string)
@@ -65,3 +82,57 @@ func stringIsJSON(i interface{}, k cty.Path) diag.Diagnostics {
 	}
 	return nil
 }
+
+// stringIsExpression validates an Okta Expression Language expression of the
+// context. Syntax and type errors are errors, likely mistakes, e.g. unknown
+// functions, are warnings.
+func stringIsExpression(ctx expression.Context) schema.SchemaValidateDiagFunc {
+	return func(i interface{}, k cty.Path) diag.Diagnostics {
+		v, ok := i.(string)
+		if !ok {
+			return diag.Errorf("expected type of %v to be string", k)
+		}
+		var diags diag.Diagnostics
+		for _, p := range expression.Check(v, ctx) {
+			severity, summary := diag.Error, "Invalid Okta expression"
+			if p.Severity == expression.SeverityWarning {
+				severity, summary = diag.Warning, "Possibly incorrect Okta expression"
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity:      severity,
+				Summary:       summary,
+				Detail:        fmt.Sprintf("%s in %q", p, v),
+				AttributePath: k,
+			})
+		}
+		return diags
+	}
+}
+
+// expressionErrors returns the errors of an Okta Expression Language
+// expression of the context as a single error, for the attributes validated
+// by a CustomizeDiff. Warnings are left out, a CustomizeDiff can't return
+// them.
+func expressionErrors(attribute, v string, ctx expression.Context) error {
+	var errs []string
+	for _, p := range expression.Check(v, ctx) {
+		if p.Severity == expression.SeverityError {
+			errs = append(errs, p.String())
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s is an invalid Okta expression, %s in %q", attribute, strings.Join(errs, ", "), v)
+}
+
+func stringIsPEMCertificate(i interface{}, k cty.Path) diag.Diagnostics {
+	v, ok := i.(string)
+	if !ok {
+		return diag.Errorf("expected type of %v to be string", k)
+	}
+	if block, _ := pem.Decode([]byte(v)); block == nil || block.Type != "CERTIFICATE" {
+		return diag.Errorf("%v is not a PEM encoded certificate", k)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package okta
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/okta/terraform-provider-okta/okta/internal/expression"
+)
+
+func TestStringIsExpression(t *testing.T) {
+	validate := stringIsExpression(expression.GroupRule)
+	path := cty.GetAttrPath("expression_value")
+
+	if diags := validate(`String.startsWith(user.firstName, "andy")`, path); len(diags) != 0 {
+		t.Errorf("valid expression has diagnostics: %v", diags)
+	}
+	diags := validate(`user.department == "Engineering" AND`, path)
+	if len(diags) != 1 || diags[0].Severity != diag.Error || diags[0].Summary != "Invalid Okta expression" ||
+		diags[0].Detail != `unexpected end of expression at column 37 in "user.department == \"Engineering\" AND"` {
+		t.Errorf("unexpected diagnostics of an invalid expression: %v", diags)
+	}
+	diags = validate(`appuser.department == "Engineering"`, path)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !diags[0].AttributePath.Equals(path) {
+		t.Errorf("unexpected diagnostics of an expression with an unknown variable: %v", diags)
+	}
+}
+
+func TestAppSamlAttributeStatementsDiff(t *testing.T) {
+	cases := []struct {
+		values  []interface{}
+		typ     string
+		wantErr string
+	}{
+		{values: []interface{}{"user.firstName", "String.toUpperCase(user.lastName)"}, typ: "EXPRESSION"},
+		{values: []interface{}{"Articulate"}, typ: "EXPRESSION"},
+		{values: []interface{}{"user.firstName", "String.len(user.lastName"}, typ: "EXPRESSION",
+			wantErr: `attribute_statements.0.values.1 is an invalid Okta expression, expected ")", found end of expression at column 25`},
+		{values: []interface{}{"not an expression ("}, typ: "GROUP"},
+	}
+	r := resourceAppSaml()
+	for _, c := range cases {
+		raw := map[string]interface{}{
+			"label": "test",
+			"attribute_statements": []interface{}{
+				map[string]interface{}{"name": "attr", "type": c.typ, "values": c.values},
+			},
+		}
+		_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+		switch {
+		case c.wantErr == "" && err != nil:
+			t.Errorf("diff of %v: %v", c.values, err)
+		case c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)):
+			t.Errorf("diff of %v = %v, want %q", c.values, err, c.wantErr)
+		}
+	}
+}
+
+func TestStringIsPEMCertificate(t *testing.T) {
+	path := cty.GetAttrPath("certificate")
+	cert := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	if diags := stringIsPEMCertificate(cert, path); len(diags) != 0 {
+		t.Errorf("certificate has diagnostics: %v", diags)
+	}
+	csr := "-----BEGIN CERTIFICATE REQUEST-----\nMIIB\n-----END CERTIFICATE REQUEST-----\n"
+	for _, v := range []string{"MIIB", csr} {
+		if diags := stringIsPEMCertificate(v, path); len(diags) != 1 {
+			t.Errorf("%q isn't a certificate but has diagnostics %v", v, diags)
+		}
+	}
+}
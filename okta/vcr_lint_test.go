@@ -0,0 +1,300 @@
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dnaeon/go-vcr/cassette"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The cassette lint is a VCR play mode that reports how well the cassettes
+// still match the tests. ENV var OKTA_VCR_LINT names the file the lint is
+// appended to, a JSON object a line for each played cassette, see
+// .github/CONTRIBUTING.md#linting-vcr-cassettes.
+var (
+	cassetteLintsLock = sync.Mutex{}
+	cassetteLints     = map[string]*cassetteLint{}
+)
+
+// cassetteLint is the lint of a played cassette.
+type cassetteLint struct {
+	Test         string `json:"test"`
+	Cassette     string `json:"cassette"`
+	Interactions int    `json:"interactions"`
+	// Unused are the interactions the test never made.
+	Unused []lintInteraction `json:"unused"`
+	// Unmatched are the requests the test made the cassette has no
+	// interaction for.
+	Unmatched []lintInteraction `json:"unmatched"`
+	// Loose are the interactions matched on their method and URL only,
+	// because the bodies weren't the same and couldn't be compared as JSON.
+	Loose []lintInteraction `json:"loose"`
+	// Secrets are values left in the cassette that look like secrets.
+	Secrets []lintSecret `json:"secrets"`
+
+	lock     sync.Mutex
+	requests []cassette.Request
+	used     []bool
+}
+
+// lintInteraction is an interaction of the cassette, or a request without
+// one whose index is -1.
+type lintInteraction struct {
+	Index  int    `json:"index"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type lintSecret struct {
+	Index int `json:"index"`
+	// Kind of the secret, e.g. client_secret.
+	Kind string `json:"kind"`
+	// In is where the secret is, e.g. response.body.
+	In string `json:"in"`
+}
+
+// cassetteSecrets are the secrets the lint looks for in the bodies of
+// cassettes. A value of REDACTED has been scrubbed.
+var cassetteSecrets = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"client_secret", regexp.MustCompile(`"client_secret"\s*:\s*"([^"]+)"`)},
+	{"private_key", regexp.MustCompile(`(-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----)`)},
+	{"jwk_private_key", regexp.MustCompile(`"(?:d|p|q|dp|dq|qi)"\s*:\s*"([A-Za-z0-9_-]{16,})"`)},
+	{"access_token", regexp.MustCompile(`"(?:access_token|id_token|refresh_token)"\s*:\s*"([^"]+)"`)},
+	{"client_assertion", regexp.MustCompile(`client_assertion=([^&"\s]+)`)},
+}
+
+func newCassetteLint(mgr *vcrManager) (*cassetteLint, error) {
+	c, err := cassette.Load(mgr.CassettePath())
+	if err != nil {
+		return nil, err
+	}
+	lint := &cassetteLint{
+		Test:         mgr.Name,
+		Cassette:     mgr.CurrentCassette,
+		Interactions: len(c.Interactions),
+		Unused:       []lintInteraction{},
+		Unmatched:    []lintInteraction{},
+		Loose:        []lintInteraction{},
+		Secrets:      cassetteSecretsOf(c),
+		used:         make([]bool, len(c.Interactions)),
+	}
+	for _, i := range c.Interactions {
+		lint.requests = append(lint.requests, i.Request)
+	}
+
+	cassetteLintsLock.Lock()
+	cassetteLints[mgr.TestAndCassetteName()] = lint
+	cassetteLintsLock.Unlock()
+	return lint, nil
+}
+
+// matched marks the interaction of the request as used. The recorder hands
+// out the first unused interaction that matches, the same one is marked here.
+func (l *cassetteLint) matched(request cassette.Request, loose bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for index, r := range l.requests {
+		if l.used[index] || r.Method != request.Method || r.URL != request.URL || r.Body != request.Body {
+			continue
+		}
+		l.used[index] = true
+		if loose {
+			l.Loose = append(l.Loose, lintInteraction{Index: index, Method: r.Method, URL: r.URL})
+		}
+		return
+	}
+}
+
+func (l *cassetteLint) unmatched(req *http.Request) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	request := lintInteraction{Index: -1, Method: req.Method, URL: req.URL.String()}
+	for _, r := range l.Unmatched {
+		if r == request {
+			// the clients retry
+			return
+		}
+	}
+	l.Unmatched = append(l.Unmatched, request)
+}
+
+// done lists the interactions that weren't used.
+func (l *cassetteLint) done() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.Unused = []lintInteraction{}
+	for index, used := range l.used {
+		if !used {
+			l.Unused = append(l.Unused, lintInteraction{Index: index, Method: l.requests[index].Method, URL: l.requests[index].URL})
+		}
+	}
+}
+
+// cassetteSecretsOf returns the secrets left in the cassette's interactions.
+func cassetteSecretsOf(c *cassette.Cassette) []lintSecret {
+	secrets := []lintSecret{}
+	for index, i := range c.Interactions {
+		if auth := i.Request.Headers.Get("Authorization"); auth != "" && !strings.HasSuffix(auth, " REDACTED") {
+			secrets = append(secrets, lintSecret{Index: index, Kind: "authorization", In: "request.headers.Authorization"})
+		}
+		if proof := i.Request.Headers.Get("Dpop"); proof != "" && proof != "REDACTED" {
+			secrets = append(secrets, lintSecret{Index: index, Kind: "dpop", In: "request.headers.Dpop"})
+		}
+		for _, part := range []struct{ in, text string }{
+			{"request.url", i.Request.URL},
+			{"request.body", i.Request.Body},
+			{"response.body", i.Response.Body},
+		} {
+			for _, secret := range cassetteSecrets {
+				for _, match := range secret.pattern.FindAllStringSubmatch(part.text, -1) {
+					if match[1] != "REDACTED" {
+						secrets = append(secrets, lintSecret{Index: index, Kind: secret.kind, In: part.in})
+						break
+					}
+				}
+			}
+		}
+	}
+	return secrets
+}
+
+// lintTransport records the requests the recorder has no interaction for.
+type lintTransport struct {
+	rec  http.RoundTripper
+	lint *cassetteLint
+}
+
+func (t *lintTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rec.RoundTrip(req)
+	if errors.Is(err, cassette.ErrInteractionNotFound) {
+		t.lint.unmatched(req)
+	}
+	return resp, err
+}
+
+// writeCassetteLint appends the lint of the cassette the test played to the
+// OKTA_VCR_LINT file. It is deferred, a test that drifted from its cassette
+// fails. A cassette the provider wasn't configured for isn't linted.
+func writeCassetteLint(t *testing.T, mgr *vcrManager) {
+	cassetteLintsLock.Lock()
+	defer cassetteLintsLock.Unlock()
+	lint, ok := cassetteLints[mgr.TestAndCassetteName()]
+	if !ok {
+		return
+	}
+	delete(cassetteLints, mgr.TestAndCassetteName())
+
+	lint.done()
+	line, err := json.Marshal(lint)
+	if err != nil {
+		t.Errorf("failed to lint cassette %q: %v", mgr.CurrentCassette, err)
+		return
+	}
+	f, err := os.OpenFile(os.Getenv("OKTA_VCR_LINT"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Errorf("failed to write the lint of cassette %q: %v", mgr.CurrentCassette, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		t.Errorf("failed to write the lint of cassette %q: %v", mgr.CurrentCassette, err)
+	}
+	fmt.Printf("=== VCR LINT CASSETTE %q for %s: %d unused, %d unmatched, %d loose, %d secrets\n",
+		mgr.CurrentCassette, t.Name(), len(lint.Unused), len(lint.Unmatched), len(lint.Loose), len(lint.Secrets))
+}
+
+func TestCassetteLint(t *testing.T) {
+	mgr := &vcrManager{
+		Name:            t.Name(),
+		CassettesPath:   t.TempDir(),
+		CurrentCassette: "cassette",
+		VCRModeName:     "play",
+	}
+	interactions := []struct{ method, path, body, response string }{
+		{"GET", "/api/v1/users/me", "", `{"id":"00u1"}`},
+		{"POST", "/oauth2/v1/token", "grant_type=client_credentials", `{"access_token":"REDACTED"}`},
+		{"GET", "/api/v1/groups/00g1", "", `{"id":"00g1"}`},
+		{"GET", "/api/v1/apps/0oa1", "", `{"credentials":{"oauthClient":{"client_id":"0oa1","client_secret":"secret"}}}`},
+	}
+	yaml := "---\nversion: 1\ninteractions:\n"
+	for _, i := range interactions {
+		yaml += fmt.Sprintf("- request:\n    body: %q\n    url: https://test.okta.com%s\n    method: %s\n  response:\n    body: '%s'\n    status: 200 OK\n    code: 200\n", i.body, i.path, i.method, i.response)
+	}
+	if err := os.WriteFile(mgr.CassettePath()+".yaml", []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	report := path.Join(t.TempDir(), "lint.json")
+	t.Setenv("OKTA_VCR_LINT", report)
+
+	configure := func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		config := &Config{orgName: "test", domain: "okta.com", accessToken: "accessToken"}
+		return config, diag.FromErr(config.loadAndValidate(ctx))
+	}
+	config, diags := getCachedConfig(context.TODO(), nil, configure, mgr)
+	if diags.HasError() {
+		t.Fatalf("did not expect error but received error: %+v", diags)
+	}
+	defer func() {
+		providerConfigsLock.Lock()
+		delete(providerConfigs, mgr.TestAndCassetteName())
+		providerConfigsLock.Unlock()
+	}()
+
+	client := &http.Client{Transport: config.roundTripper}
+	requests := []struct{ method, path, body string }{
+		{"GET", "/api/v1/users/me", ""},
+		{"POST", "/oauth2/v1/token", "grant_type=client_credentials&scope=okta.users.read"},
+		{"GET", "/api/v1/groups/00g2", ""},
+		{"GET", "/api/v1/groups/00g2", ""},
+		{"GET", "/api/v1/apps/0oa1", ""},
+	}
+	for _, r := range requests {
+		req, _ := http.NewRequest(r.method, "https://test.okta.com"+r.path, strings.NewReader(r.body))
+		if r.body == "" {
+			req.Body = nil
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+	writeCassetteLint(t, mgr)
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lint cassetteLint
+	if err := json.Unmarshal(data, &lint); err != nil {
+		t.Fatalf("expected a JSON line, got %q: %v", data, err)
+	}
+	if lint.Interactions != 4 {
+		t.Errorf("expected 4 interactions, got %d", lint.Interactions)
+	}
+	if len(lint.Unused) != 1 || lint.Unused[0].Index != 2 {
+		t.Errorf("expected the group interaction to be unused, got %+v", lint.Unused)
+	}
+	if len(lint.Unmatched) != 1 || lint.Unmatched[0].URL != "https://test.okta.com/api/v1/groups/00g2" {
+		t.Errorf("expected the other group request to be unmatched once, got %+v", lint.Unmatched)
+	}
+	if len(lint.Loose) != 1 || lint.Loose[0].Index != 1 {
+		t.Errorf("expected the token request to match loosely, got %+v", lint.Loose)
+	}
+	if len(lint.Secrets) != 1 || lint.Secrets[0] != (lintSecret{Index: 3, Kind: "client_secret", In: "response.body"}) {
+		t.Errorf("expected the app's client secret, got %+v", lint.Secrets)
+	}
+}
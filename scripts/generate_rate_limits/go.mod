@@ -2,7 +2,10 @@ module github.com/okta/terraform-provider-okta/scripts/generate_rate_limits
 
 go 1.19
 
-require github.com/spf13/cobra v1.3.0
+require (
+	github.com/spf13/cobra v1.3.0
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"sort"
@@ -10,10 +12,17 @@ import (
 	"text/template"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
+const rlGoPath = "../../okta/internal/apimutex/rate_limit_lines.go"
+
 var (
 	rateLimitMappingsTxt string
+	inputFormat          string
+	mergeExisting        bool
+	reID                 = regexp.MustCompile(`{[^}]+}`)
+	reLine               = regexp.MustCompile(`^\s*"(.+)",\s*$`)
 	rootCmd              = &cobra.Command{
 		Use:   "go run main.go -h",
 		Short: "generate rate limits code",
@@ -24,36 +33,19 @@ var (
 				fmt.Fprintf(os.Stderr, "failed to read %q: %v\n", mappingsPath, err)
 				return
 			}
+			defer mappingsFile.Close()
 
-			lines := []string{}
-			scanner := bufio.NewScanner(mappingsFile)
-
-			reID := regexp.MustCompile(`{[^}]+}`)
-			for scanner.Scan() {
-				line := scanner.Text()
-				if strings.HasPrefix(line, "/api/v1/internal") ||
-					!(strings.HasPrefix(line, "/.well-known") ||
-						strings.HasPrefix(line, "/api/v1") ||
-						strings.HasPrefix(line, "/oauth2")) {
-					continue
-				}
-				// 0 path, 1 method, 3 bucket, 4 type
-				values := strings.Split(line, " ")
-				if len(values) < 5 {
-					fmt.Fprintf(os.Stderr, "unknown format of mapping line: %s\n", line)
-					return
-				}
-				if values[4] != "URL" {
-					continue
-				}
-				path := reID.ReplaceAllString(values[0], "ID")
-				lines = append(lines, fmt.Sprintf("%s %s %s", path, values[1], values[3]))
+			existing, err := readExistingLines(rlGoPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to read %q: %v\n", rlGoPath, err)
+				return
 			}
-			if err := scanner.Err(); err != nil {
+
+			lines, err := generateLines(inputFormat, mappingsFile, existing, mergeExisting)
+			if err != nil {
 				fmt.Fprintf(os.Stderr, "reading lines from %q failed: %v\n", mappingsPath, err)
 				return
 			}
-			sort.Strings(lines)
 
 			tmplPath := "rate_limit_lines.tmpl"
 			tmpl, err := os.ReadFile(tmplPath)
@@ -62,7 +54,6 @@ var (
 			}
 
 			t := template.Must(template.New("tmpl").Parse(string(tmpl)))
-			rlGoPath := "../../okta/internal/apimutex/rate_limit_lines.go"
 			rlGoFile, err := os.Create(rlGoPath)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "failed to open %q: %v\n", rlGoPath, err)
@@ -73,8 +64,221 @@ var (
 	}
 )
 
+// generateLines returns the sorted rate limit lines of the mappings input in
+// the format. The lines are merged into the existing lines if merge is set.
+// The OpenAPI spec only holds the operations missing from the existing lines,
+// so they are always merged.
+func generateLines(format string, r io.Reader, existing []string, merge bool) ([]string, error) {
+	var (
+		lines []string
+		err   error
+	)
+	switch format {
+	case "monolith":
+		lines, err = monolithLines(r)
+	case "csv":
+		lines, err = csvLines(r)
+	case "yaml":
+		lines, err = yamlLines(r)
+	case "openapi":
+		lines, err = openAPILines(r, existing)
+		merge = true
+	default:
+		err = fmt.Errorf("unknown format %q, expected one of monolith, csv, yaml, openapi", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if merge {
+		lines = mergeLines(existing, lines)
+	}
+	sort.Strings(lines)
+	return lines, nil
+}
+
+// monolithLines reads the rate limit mappings fixture from the Okta monolith,
+// lines of "PATH METHOD - BUCKET TYPE".
+func monolithLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !managementPath(line) {
+			continue
+		}
+		// 0 path, 1 method, 3 bucket, 4 type
+		values := strings.Split(line, " ")
+		if len(values) < 5 {
+			return nil, fmt.Errorf("unknown format of mapping line: %s", line)
+		}
+		if values[4] != "URL" {
+			continue
+		}
+		lines = append(lines, rateLimitLine(values[0], values[1], values[3]))
+	}
+	return lines, scanner.Err()
+}
+
+// csvLines reads the rate limit tables from Okta's public documentation as
+// CSV with method, path, and bucket columns. A header row is skipped.
+func csvLines(r io.Reader) ([]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	for i, record := range records {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("record %d should have method, path, and bucket columns: %v", i+1, record)
+		}
+		method, path, bucket := strings.TrimSpace(record[0]), strings.TrimSpace(record[1]), strings.TrimSpace(record[2])
+		if i == 0 && strings.EqualFold(method, "method") {
+			continue
+		}
+		if !managementPath(path) {
+			continue
+		}
+		lines = append(lines, rateLimitLine(path, method, bucket))
+	}
+	return lines, nil
+}
+
+// yamlLines reads a YAML, or JSON, list of method, path, and bucket objects.
+// It is the same format as the provider's max_api_capacity_buckets_file.
+func yamlLines(r io.Reader) ([]string, error) {
+	var mappings []struct {
+		Method string `yaml:"method"`
+		Path   string `yaml:"path"`
+		Bucket string `yaml:"bucket"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&mappings); err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	for i, mapping := range mappings {
+		if mapping.Method == "" || mapping.Path == "" || mapping.Bucket == "" {
+			return nil, fmt.Errorf("mapping %d requires method, path, and bucket", i+1)
+		}
+		if !managementPath(mapping.Path) {
+			continue
+		}
+		lines = append(lines, rateLimitLine(mapping.Path, mapping.Method, mapping.Bucket))
+	}
+	return lines, nil
+}
+
+// openAPILines reads the path list of Okta's OpenAPI spec, YAML or JSON. The
+// spec has no rate limit information, each operation not already mapped is
+// placed in the longest known bucket that prefixes its path.
+func openAPILines(r io.Reader, existing []string) ([]string, error) {
+	var spec struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, err
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("spec has no paths")
+	}
+
+	mapped := map[string]bool{}
+	bucketSet := map[string]bool{}
+	for _, line := range existing {
+		values := strings.Split(line, " ")
+		mapped[values[0]+" "+values[1]] = true
+		bucketSet[values[2]] = true
+	}
+	buckets := []string{}
+	for bucket := range bucketSet {
+		buckets = append(buckets, bucket)
+	}
+	// longest bucket first
+	sort.Slice(buckets, func(i, j int) bool {
+		return len(buckets[i]) > len(buckets[j])
+	})
+
+	lines := []string{}
+	for path, operations := range spec.Paths {
+		if !managementPath(path) {
+			continue
+		}
+		idPath := reID.ReplaceAllString(path, "ID")
+		for method := range operations {
+			method = strings.ToUpper(method)
+			switch method {
+			case "GET", "POST", "PUT", "PATCH", "DELETE":
+			default:
+				continue
+			}
+			if mapped[idPath+" "+method] {
+				continue
+			}
+			for _, bucket := range buckets {
+				prefix := reID.ReplaceAllString(bucket, "ID")
+				if idPath == prefix || strings.HasPrefix(idPath, strings.TrimSuffix(prefix, "/")+"/") {
+					lines = append(lines, rateLimitLine(path, method, bucket))
+					break
+				}
+			}
+		}
+	}
+	return lines, nil
+}
+
+// readExistingLines reads the lines of the currently generated go file.
+func readExistingLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if match := reLine.FindStringSubmatch(scanner.Text()); match != nil {
+			lines = append(lines, match[1])
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// mergeLines merges the new lines into the existing lines, new lines win for
+// the same path and method.
+func mergeLines(existing, lines []string) []string {
+	merged := map[string]string{}
+	for _, line := range append(existing, lines...) {
+		values := strings.Split(line, " ")
+		merged[values[0]+" "+values[1]] = line
+	}
+	result := []string{}
+	for _, line := range merged {
+		result = append(result, line)
+	}
+	return result
+}
+
+func managementPath(path string) bool {
+	return !strings.HasPrefix(path, "/api/v1/internal") &&
+		(strings.HasPrefix(path, "/.well-known") ||
+			strings.HasPrefix(path, "/api/v1") ||
+			strings.HasPrefix(path, "/oauth2"))
+}
+
+func rateLimitLine(path, method, bucket string) string {
+	return fmt.Sprintf("%s %s %s", reID.ReplaceAllString(path, "ID"), strings.ToUpper(method), bucket)
+}
+
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&rateLimitMappingsTxt, "mappings", "m", "", "path to Okta rate limit mappings fixture for ITs - any of the files in monolith source components/tests/api/webapp/src/test/resources/ratelimits")
+	rootCmd.PersistentFlags().StringVarP(&rateLimitMappingsTxt, "mappings", "m", "", "path to the rate limit mappings input, see --format")
+	rootCmd.PersistentFlags().StringVarP(&inputFormat, "format", "f", "monolith", "format of the mappings input: "+
+		"monolith, any of the files in monolith source components/tests/api/webapp/src/test/resources/ratelimits; "+
+		"csv, method,path,bucket rows from the rate limit tables in Okta's documentation; "+
+		"yaml, a list of method, path, and bucket objects; "+
+		"openapi, the path list of Okta's OpenAPI spec with buckets inferred from the existing mappings")
+	rootCmd.PersistentFlags().BoolVar(&mergeExisting, "merge", false, "merge the input into the existing mappings instead of replacing them, openapi input is always merged")
 }
 
 func Execute() error {
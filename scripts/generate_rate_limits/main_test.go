@@ -0,0 +1,143 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateLines(t *testing.T) {
+	existing := []string{
+		"/api/v1/apps GET /api/v1/apps",
+		"/api/v1/users/ID GET /api/v1/users/{id}",
+	}
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		merge    bool
+		expected []string
+		err      string
+	}{
+		{
+			name:   "monolith",
+			format: "monolith",
+			input: "/api/v1/groups GET - /api/v1/groups URL\n" +
+				"/api/v1/groups/{groupId} GET - /api/v1/groups/{id} URL\n" +
+				"/api/v1/internal/things GET - /api/v1/internal URL\n" +
+				"/api/v1/apps GET - /api/v1/apps ORG\n" +
+				"/login/things GET - /login URL\n",
+			expected: []string{
+				"/api/v1/groups GET /api/v1/groups",
+				"/api/v1/groups/ID GET /api/v1/groups/{id}",
+			},
+		},
+		{
+			name:   "monolith merged",
+			format: "monolith",
+			input:  "/api/v1/apps GET - /api/v1/apps/new URL\n",
+			merge:  true,
+			expected: []string{
+				"/api/v1/apps GET /api/v1/apps/new",
+				"/api/v1/users/ID GET /api/v1/users/{id}",
+			},
+		},
+		{
+			name:   "monolith invalid",
+			format: "monolith",
+			input:  "/api/v1/groups GET\n",
+			err:    "unknown format of mapping line",
+		},
+		{
+			name:   "csv",
+			format: "csv",
+			input:  "Method,Path,Bucket\nget,/api/v1/groups/{groupId},/api/v1/groups/{id}\nGET,/login,/login\n",
+			expected: []string{
+				"/api/v1/groups/ID GET /api/v1/groups/{id}",
+			},
+		},
+		{
+			name:   "csv missing column",
+			format: "csv",
+			input:  "GET,/api/v1/groups\n",
+			err:    "should have method, path, and bucket columns",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			input:  "- method: DELETE\n  path: /api/v1/groups/{groupId}\n  bucket: /api/v1/groups/{id}\n",
+			expected: []string{
+				"/api/v1/groups/ID DELETE /api/v1/groups/{id}",
+			},
+		},
+		{
+			name:   "yaml missing bucket",
+			format: "yaml",
+			input:  "- method: GET\n  path: /api/v1/groups\n",
+			err:    "mapping 1 requires method, path, and bucket",
+		},
+		{
+			name:   "openapi is always merged",
+			format: "openapi",
+			input: "paths:\n" +
+				"  /api/v1/apps:\n    get: {}\n    post: {}\n    parameters: []\n" +
+				"  /api/v1/apps/{appId}/users:\n    get: {}\n" +
+				"  /api/v1/users/{userId}:\n    get: {}\n    delete: {}\n" +
+				"  /api/v1/brands:\n    get: {}\n",
+			expected: []string{
+				"/api/v1/apps GET /api/v1/apps",
+				"/api/v1/apps POST /api/v1/apps",
+				"/api/v1/apps/ID/users GET /api/v1/apps",
+				"/api/v1/users/ID DELETE /api/v1/users/{id}",
+				"/api/v1/users/ID GET /api/v1/users/{id}",
+			},
+		},
+		{
+			name:   "openapi without paths",
+			format: "openapi",
+			input:  "openapi: 3.0.1\n",
+			err:    "spec has no paths",
+		},
+		{
+			name:   "unknown format",
+			format: "xml",
+			err:    `unknown format "xml"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines, err := generateLines(test.format, strings.NewReader(test.input), existing, test.merge)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("didn't expect error, got %v", err)
+			}
+			if !reflect.DeepEqual(lines, test.expected) {
+				t.Errorf("expected lines\n%s\ngot\n%s", strings.Join(test.expected, "\n"), strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+func TestReadExistingLines(t *testing.T) {
+	lines, err := readExistingLines(rlGoPath)
+	if err != nil {
+		t.Fatalf("didn't expect error, got %v", err)
+	}
+	if len(lines) == 0 {
+		t.Fatalf("expected the lines of %q", rlGoPath)
+	}
+	for _, line := range lines {
+		if len(strings.Split(line, " ")) != 3 {
+			t.Errorf("expected line %q to be PATH METHOD BUCKET", line)
+		}
+	}
+
+	if lines, err := readExistingLines("does-not-exist.go"); err != nil || lines != nil {
+		t.Errorf("expected no lines and no error for a missing file, got %v, %v", lines, err)
+	}
+}
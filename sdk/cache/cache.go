@@ -3,6 +3,8 @@ package cache
 import (
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type Cache interface {
@@ -15,6 +17,12 @@ type Cache interface {
 	Has(key string) bool
 }
 
+// KeyLister is implemented by caches that can list their keys. Invalidate
+// uses it to find every cached response related to a mutated resource.
+type KeyLister interface {
+	Keys() []string
+}
+
 func CreateCacheKey(req *http.Request) string {
 	s := req.URL.Scheme + "://" + req.URL.Host + req.URL.RequestURI()
 	return s
@@ -26,3 +34,35 @@ func CopyResponse(resp *http.Response) *http.Response {
 
 	return &c
 }
+
+// Invalidate deletes the cached responses a mutating request can make stale.
+// Those are the request's resource, its sub resources, and the resources and
+// list endpoints it is nested under, with any query string. Caches that can't
+// list their keys only have the resource and the paths above it deleted.
+func Invalidate(c Cache, req *http.Request) {
+	mutated := strings.TrimSuffix(req.URL.Path, "/")
+	base := req.URL.Scheme + "://" + req.URL.Host
+
+	lister, ok := c.(KeyLister)
+	if !ok {
+		c.Delete(CreateCacheKey(req))
+		for path := mutated; path != ""; path = path[:strings.LastIndex(path, "/")] {
+			c.Delete(base + path)
+		}
+		return
+	}
+	for _, key := range lister.Keys() {
+		u, err := url.Parse(key)
+		if err != nil || u.Scheme+"://"+u.Host != base {
+			continue
+		}
+		if related(strings.TrimSuffix(u.Path, "/"), mutated) {
+			c.Delete(key)
+		}
+	}
+}
+
+// related reports if one path is the same as, or nested under, the other.
+func related(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func cachedResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	key := "https://example.okta.com/api/v1/apps/0oa1"
+	require.False(t, c.Has(key))
+	require.Nil(t, c.Get(key))
+
+	c.Set(key, cachedResponse(`{"id":"0oa1"}`))
+	require.True(t, c.Has(key))
+	resp := c.Get(key)
+	require.NotNil(t, resp)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"0oa1"}`, string(body))
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	c.SetString("token", "value")
+	require.Equal(t, "value", c.GetString("token"))
+	keys := c.Keys()
+	sort.Strings(keys)
+	require.Equal(t, []string{key, "token"}, keys)
+
+	c.Delete(key)
+	require.False(t, c.Has(key))
+	c.Clear()
+	require.Empty(t, c.Keys())
+}
+
+func TestInvalidate(t *testing.T) {
+	fileCache, err := NewFileCache(t.TempDir(), 0)
+	require.NoError(t, err)
+	for name, c := range map[string]Cache{
+		"go":   NewGoCache(0, 0),
+		"file": fileCache,
+	} {
+		t.Run(name, func(t *testing.T) {
+			base := "https://example.okta.com"
+			related := []string{
+				"/api/v1/apps",
+				"/api/v1/apps?limit=200",
+				"/api/v1/apps/0oa1",
+				"/api/v1/apps/0oa1/groups",
+				"/api/v1/apps/0oa1/groups/00g1",
+			}
+			unrelated := []string{
+				"/api/v1/apps/0oa2",
+				"/api/v1/groups/00g1",
+			}
+			for _, path := range append(related, unrelated...) {
+				c.Set(base+path, cachedResponse(`{}`))
+			}
+
+			req := httptest.NewRequest(http.MethodPut, base+"/api/v1/apps/0oa1/groups/00g1", nil)
+			Invalidate(c, req)
+			for _, path := range related {
+				require.False(t, c.Has(base+path), "expected %s to be invalidated", path)
+			}
+			for _, path := range unrelated {
+				require.True(t, c.Has(base+path), "expected %s to be kept", path)
+			}
+
+			req = httptest.NewRequest(http.MethodDelete, base+"/api/v1/apps/0oa2", nil)
+			Invalidate(c, req)
+			require.False(t, c.Has(base+"/api/v1/apps/0oa2"))
+			require.True(t, c.Has(base+"/api/v1/groups/00g1"))
+		})
+	}
+}
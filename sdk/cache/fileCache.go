@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const fileCacheExt = ".cache"
+
+// FileCache is a Cache that keeps each entry in its own file of a directory.
+// It trades the speed of GoCache for not holding large responses in memory.
+// An entry file is the key on the first line followed by the value.
+type FileCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFileCache returns a file cache in the directory, which is created if it
+// doesn't exist. A ttl of zero or less never expires entries.
+func NewFileCache(dir string, ttl int32) (FileCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return FileCache{}, err
+	}
+	return FileCache{
+		dir: dir,
+		ttl: time.Duration(ttl) * time.Second,
+	}, nil
+}
+
+func (c FileCache) Get(key string) *http.Response {
+	value, found := c.read(key)
+	if !found {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(value)), nil)
+	if err != nil {
+		return nil
+	}
+	return resp
+}
+
+func (c FileCache) Set(key string, value *http.Response) {
+	cacheableResponse, err := httputil.DumpResponse(value, true)
+	if err != nil {
+		return
+	}
+	c.write(key, cacheableResponse)
+}
+
+func (c FileCache) GetString(key string) string {
+	value, _ := c.read(key)
+	return string(value)
+}
+
+func (c FileCache) SetString(key, value string) {
+	c.write(key, []byte(value))
+}
+
+func (c FileCache) Delete(key string) {
+	_ = os.Remove(c.path(key))
+}
+
+func (c FileCache) Clear() {
+	files, _ := filepath.Glob(filepath.Join(c.dir, "*"+fileCacheExt))
+	for _, file := range files {
+		_ = os.Remove(file)
+	}
+}
+
+func (c FileCache) Has(key string) bool {
+	_, found := c.read(key)
+	return found
+}
+
+// Keys returns the keys of the entries that haven't expired.
+func (c FileCache) Keys() []string {
+	files, _ := filepath.Glob(filepath.Join(c.dir, "*"+fileCacheExt))
+	keys := []string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		key, _, found := strings.Cut(string(data), "\n")
+		if !found {
+			continue
+		}
+		if _, ok := c.read(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (c FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+fileCacheExt)
+}
+
+func (c FileCache) read(key string) ([]byte, bool) {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if c.ttl > 0 && time.Since(info.ModTime()) > c.ttl {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	storedKey, value, found := bytes.Cut(data, []byte("\n"))
+	if !found || string(storedKey) != key {
+		return nil, false
+	}
+	return value, true
+}
+
+// write replaces the entry through a temporary file so that concurrent
+// readers never see a partial entry.
+func (c FileCache) write(key string, value []byte) {
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(append([]byte(key+"\n"), value...))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
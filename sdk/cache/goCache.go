@@ -70,3 +70,11 @@ func (c GoCache) Has(key string) bool {
 	_, found := c.rootLibrary.Get(key)
 	return found
 }
+
+func (c GoCache) Keys() []string {
+	keys := []string{}
+	for key := range c.rootLibrary.Items() {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,28 @@
+package sdk
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ConcurrencyLimitBackoff is the number of seconds to back off after a
+// concurrent rate limit violation. The concurrent limit frees up as soon as
+// in flight requests complete, so there is no reason to wait for reset.
+const ConcurrencyLimitBackoff = 1
+
+// IsConcurrencyLimitResponse reports if the response is a 429 for exceeding
+// Okta's concurrent rate limit rather than a per minute rate limit, see
+// https://developer.okta.com/docs/reference/rl-additional-limits/#concurrent-rate-limits
+// Both are E0000047 errors, but a per minute limit is only exceeded once its
+// x-rate-limit-remaining is down to zero, so a 429 with requests remaining is
+// for the concurrent limit. Its x-rate-limit-* headers must not be accounted
+// as the per minute limit. A concurrent limit 429 without requests remaining
+// can't be told apart and is treated as a per minute one, which waits longer
+// than needed but never too short.
+func IsConcurrencyLimitResponse(resp *http.Response) bool {
+	if !tooManyRequests(resp) {
+		return false
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-Rate-Limit-Remaining"))
+	return err == nil && remaining > 0
+}
@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rateLimitBody is the body of Okta's 429s, for both per minute and
+// concurrent rate limits.
+const rateLimitBody = `{"errorCode":"E0000047","errorSummary":"API call exceeded rate limit due to too many requests.","errorLink":"E0000047","errorId":"sampleId","errorCauses":[]}`
+
+func TestIsConcurrencyLimitResponse(t *testing.T) {
+	response := func(status int, remaining string) *http.Response {
+		header := http.Header{}
+		if remaining != "" {
+			header.Set("X-Rate-Limit-Remaining", remaining)
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(rateLimitBody)), Header: header}
+	}
+
+	require.True(t, IsConcurrencyLimitResponse(response(http.StatusTooManyRequests, "12")))
+	require.False(t, IsConcurrencyLimitResponse(response(http.StatusTooManyRequests, "0")), "per minute limit should be exhausted")
+	require.False(t, IsConcurrencyLimitResponse(response(http.StatusTooManyRequests, "")))
+	require.False(t, IsConcurrencyLimitResponse(response(http.StatusOK, "12")))
+	require.False(t, IsConcurrencyLimitResponse(nil))
+}
+
+func TestDoWithRetriesConcurrencyLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Date", time.Now().UTC().Format("Mon, 02 Jan 2006 15:04:05 GMT"))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// a reset a minute away would be waited on for a per minute limit
+			w.Header().Set("X-Rate-Limit-Limit", "600")
+			w.Header().Set("X-Rate-Limit-Remaining", "540")
+			w.Header().Set("X-Rate-Limit-Reset", fmt.Sprint(time.Now().Unix()+60))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(rateLimitBody))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	_, client, err := NewClient(context.Background(),
+		WithOrgUrl(server.URL),
+		WithToken("token"),
+		WithCache(false),
+		WithTestingDisableHttpsCheck(true),
+		WithRateLimitMaxBackOff(60),
+		WithRateLimitMaxRetries(2),
+	)
+	require.NoError(t, err)
+
+	re := client.CloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	require.NoError(t, err)
+	start := time.Now()
+	resp, err := re.doWithRetries(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	require.Less(t, time.Since(start), 10*time.Second, "should not wait for the rate limit reset")
+}
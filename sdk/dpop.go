@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// DPoPProofClaims are the claims of a DPoP proof, see RFC 9449 section 4.2.
+type DPoPProofClaims struct {
+	ID              string           `json:"jti"`
+	HTTPMethod      string           `json:"htm"`
+	HTTPURI         string           `json:"htu"`
+	IssuedAt        *jwt.NumericDate `json:"iat"`
+	Nonce           string           `json:"nonce,omitempty"`
+	AccessTokenHash string           `json:"ath,omitempty"`
+}
+
+// DPoPProver makes the DPoP proofs that bind the access tokens of the private
+// key flow to an ephemeral key of the provider process. It keeps the last
+// nonce of each server so that later proofs carry it.
+type DPoPProver struct {
+	signer jose.Signer
+	lock   sync.Mutex
+	nonces map[string]dpopNonce
+	seq    uint64
+}
+
+// maxDPoPNonces is the number of servers whose nonce the prover keeps. Each
+// custom authorization server has a token endpoint of its own, the least
+// recently set nonce is dropped beyond it.
+const maxDPoPNonces = 64
+
+type dpopNonce struct {
+	value string
+	// seq orders the nonces by when they were set
+	seq uint64
+}
+
+// NewDPoPProver returns a DPoP prover with a new P-256 key.
+func NewDPoPProver() (*DPoPProver, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	options := (&jose.SignerOptions{EmbedJWK: true}).WithType("dpop+jwt")
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, options)
+	if err != nil {
+		return nil, err
+	}
+	return &DPoPProver{
+		signer: signer,
+		nonces: map[string]dpopNonce{},
+	}, nil
+}
+
+// Proof returns a DPoP proof of a request with the method to the url. The
+// access token is empty for token requests, otherwise the proof carries its
+// hash.
+func (p *DPoPProver) Proof(method string, u *url.URL, accessToken string) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := DPoPProofClaims{
+		ID:         hex.EncodeToString(jti),
+		HTTPMethod: method,
+		HTTPURI:    u.Scheme + "://" + u.Host + u.EscapedPath(),
+		IssuedAt:   jwt.NewNumericDate(time.Now()),
+		Nonce:      p.Nonce(u),
+	}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		claims.AccessTokenHash = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	return jwt.Signed(p.signer).Claims(claims).CompactSerialize()
+}
+
+// Nonce returns the last nonce of the url's server.
+func (p *DPoPProver) Nonce(u *url.URL) string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.nonces[dpopNonceKey(u)].value
+}
+
+// SetNonce keeps the nonce of the url's server for later proofs.
+func (p *DPoPProver) SetNonce(u *url.URL, nonce string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	key := dpopNonceKey(u)
+	if _, ok := p.nonces[key]; !ok && len(p.nonces) >= maxDPoPNonces {
+		oldest := ""
+		for k, n := range p.nonces {
+			if oldest == "" || n.seq < p.nonces[oldest].seq {
+				oldest = k
+			}
+		}
+		delete(p.nonces, oldest)
+	}
+	p.seq++
+	p.nonces[key] = dpopNonce{value: nonce, seq: p.seq}
+}
+
+// dpopNonceKey returns the server of the url. Okta's authorization server and
+// resource server hand out nonces of their own, so the token endpoint is a
+// server separate from the rest of the org.
+func dpopNonceKey(u *url.URL) string {
+	if strings.HasSuffix(u.Path, "/v1/token") {
+		return u.Scheme + "://" + u.Host + u.Path
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,30 @@
+package sdk
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestDPoPProverNonces(t *testing.T) {
+	prover, err := NewDPoPProver()
+	if err != nil {
+		t.Fatalf("failed to create the prover: %v", err)
+	}
+	org, _ := url.Parse("https://test.okta.com/api/v1/users")
+	prover.SetNonce(org, "org")
+	for i := 0; i < maxDPoPNonces; i++ {
+		u, _ := url.Parse(fmt.Sprintf("https://test.okta.com/oauth2/aus%d/v1/token", i))
+		prover.SetNonce(u, fmt.Sprint(i))
+	}
+	if len(prover.nonces) != maxDPoPNonces {
+		t.Errorf("expected %d nonces, got %d", maxDPoPNonces, len(prover.nonces))
+	}
+	if nonce := prover.Nonce(org); nonce != "" {
+		t.Errorf("expected the oldest nonce to be dropped, got %q", nonce)
+	}
+	last, _ := url.Parse(fmt.Sprintf("https://test.okta.com/oauth2/aus%d/v1/token", maxDPoPNonces-1))
+	if nonce := prover.Nonce(last); nonce != fmt.Sprint(maxDPoPNonces-1) {
+		t.Errorf("expected the latest nonce to be kept, got %q", nonce)
+	}
+}
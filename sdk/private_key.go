@@ -0,0 +1,391 @@
+package sdk
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// PrivateKey is a private key of a service app used to sign client assertions
+// of the private key flow.
+type PrivateKey struct {
+	// Key is an *rsa.PrivateKey or an *ecdsa.PrivateKey
+	Key interface{}
+	// KeyID is the kid of the key, if it has one
+	KeyID     string
+	Algorithm jose.SignatureAlgorithm
+}
+
+// ReadPrivateKeys reads the private keys of source, which is either the keys
+// themselves or a path to a file of them, see ParsePrivateKeys.
+func ReadPrivateKeys(source, passphrase string) ([]PrivateKey, error) {
+	data := []byte(source)
+	if fileExists(source) {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		data = content
+	}
+	return ParsePrivateKeys(data, passphrase)
+}
+
+// ParsePrivateKeys parses the private keys of a JWK, a JWK set, or PEM blocks.
+// PEM blocks can be PKCS#1 RSA, SEC 1 EC, or PKCS#8 keys. Encrypted PKCS#8
+// keys, and legacy encrypted PEM blocks, are decrypted with the passphrase.
+func ParsePrivateKeys(data []byte, passphrase string) ([]PrivateKey, error) {
+	data = bytes.TrimSpace([]byte(strings.ReplaceAll(string(data), `\n`, "\n")))
+	if bytes.HasPrefix(data, []byte("{")) {
+		return parseJWKs(data)
+	}
+
+	var keys []PrivateKey
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+		key, err := parsePEMPrivateKey(block, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			// not a private key, e.g. a certificate in the same file
+			continue
+		}
+		privateKey, err := newPrivateKey(key, "", "")
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, privateKey)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("invalid private key")
+	}
+	return keys, nil
+}
+
+func parseJWKs(data []byte) ([]PrivateKey, error) {
+	var set jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil || len(set.Keys) == 0 {
+		var jwk jose.JSONWebKey
+		if err := json.Unmarshal(data, &jwk); err != nil {
+			return nil, fmt.Errorf("invalid private key JWK: %w", err)
+		}
+		set.Keys = []jose.JSONWebKey{jwk}
+	}
+	var keys []PrivateKey
+	for _, jwk := range set.Keys {
+		if jwk.IsPublic() || !jwk.Valid() {
+			return nil, fmt.Errorf("JWK %q is not a private key", jwk.KeyID)
+		}
+		privateKey, err := newPrivateKey(jwk.Key, jwk.KeyID, jwk.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, privateKey)
+	}
+	return keys, nil
+}
+
+func parsePEMPrivateKey(block *pem.Block, passphrase string) (interface{}, error) {
+	der := block.Bytes
+	//lint:ignore SA1019 legacy encrypted PEM keys are still common
+	if x509.IsEncryptedPEMBlock(block) {
+		if passphrase == "" {
+			return nil, errors.New("private key is encrypted, a passphrase is required")
+		}
+		var err error
+		//lint:ignore SA1019 legacy encrypted PEM keys are still common
+		der, err = x509.DecryptPEMBlock(block, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+		}
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(der)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(der)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(der)
+	case "ENCRYPTED PRIVATE KEY":
+		if passphrase == "" {
+			return nil, errors.New("private key is encrypted, a passphrase is required")
+		}
+		der, err := decryptPKCS8(der, []byte(passphrase))
+		if err != nil {
+			return nil, err
+		}
+		return x509.ParsePKCS8PrivateKey(der)
+	}
+	return nil, nil
+}
+
+func newPrivateKey(key interface{}, keyID, algorithm string) (PrivateKey, error) {
+	privateKey := PrivateKey{
+		Key:       key,
+		KeyID:     keyID,
+		Algorithm: jose.SignatureAlgorithm(algorithm),
+	}
+	if privateKey.Algorithm != "" {
+		return privateKey, nil
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		privateKey.Algorithm = jose.RS256
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			privateKey.Algorithm = jose.ES256
+		case elliptic.P384():
+			privateKey.Algorithm = jose.ES384
+		case elliptic.P521():
+			privateKey.Algorithm = jose.ES512
+		default:
+			return privateKey, fmt.Errorf("unsupported EC private key curve %s", k.Curve.Params().Name)
+		}
+	default:
+		return privateKey, fmt.Errorf("unsupported private key type %T, expected RSA or EC", key)
+	}
+	return privateKey, nil
+}
+
+// Signer returns a signer of the key, the key id is used for keys that don't
+// have their own.
+func (k PrivateKey) Signer(defaultKeyID string) (jose.Signer, error) {
+	keyID := k.KeyID
+	if keyID == "" {
+		keyID = defaultKeyID
+	}
+	var signerOptions *jose.SignerOptions
+	if keyID != "" {
+		signerOptions = (&jose.SignerOptions{}).WithHeader("kid", keyID)
+	}
+	return jose.NewSigner(jose.SigningKey{Algorithm: k.Algorithm, Key: k.Key}, signerOptions)
+}
+
+// public returns the public key of the key.
+func (k PrivateKey) public() interface{} {
+	switch key := k.Key.(type) {
+	case *rsa.PrivateKey:
+		return &key.PublicKey
+	case *ecdsa.PrivateKey:
+		return &key.PublicKey
+	}
+	return nil
+}
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts a PKCS#8 EncryptedPrivateKeyInfo of the PBES2 scheme
+// with PBKDF2, the scheme openssl uses by default.
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid encrypted private key: %w", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption %s, only PBES2 is supported", info.Algorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("invalid PBES2 parameters: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation %s, only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("invalid PBKDF2 parameters: %w", err)
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %s", kdf.PRF.Algorithm)
+	}
+
+	var keyLength int
+	var newCipher func(key []byte) (cipher.Block, error)
+	scheme := params.EncryptionScheme.Algorithm
+	switch {
+	case scheme.Equal(oidAES128CBC):
+		keyLength, newCipher = 16, aes.NewCipher
+	case scheme.Equal(oidAES192CBC):
+		keyLength, newCipher = 24, aes.NewCipher
+	case scheme.Equal(oidAES256CBC):
+		keyLength, newCipher = 32, aes.NewCipher
+	case scheme.Equal(oidDESEDE3CBC):
+		keyLength, newCipher = 24, des.NewTripleDESCipher
+	default:
+		return nil, fmt.Errorf("unsupported private key cipher %s", scheme)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("invalid cipher parameters: %w", err)
+	}
+
+	block, err := newCipher(pbkdf2.Key(passphrase, kdf.Salt, kdf.IterationCount, keyLength, prf))
+	if err != nil {
+		return nil, err
+	}
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid encrypted private key")
+	}
+	decrypted := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, data)
+
+	// PKCS#7 padding, a wrong passphrase almost always breaks it
+	padding := int(decrypted[len(decrypted)-1])
+	if padding == 0 || padding > block.BlockSize() || !bytes.Equal(decrypted[len(decrypted)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("failed to decrypt private key, the passphrase is incorrect")
+	}
+	return decrypted[:len(decrypted)-padding], nil
+}
+
+// FallbackSigner signs client assertions with the first of a list of private
+// keys. When Okta rejects an assertion with invalid_client, for example after
+// the key was rotated out of the service app, Resign moves on to the next key.
+type FallbackSigner struct {
+	lock    sync.Mutex
+	keys    []PrivateKey
+	signers []jose.Signer
+	current int
+}
+
+// NewFallbackSigner returns a fallback signer of the keys, the key id is used
+// for keys that don't have their own.
+func NewFallbackSigner(keys []PrivateKey, defaultKeyID string) (*FallbackSigner, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one private key is required")
+	}
+	s := &FallbackSigner{keys: keys}
+	for _, key := range keys {
+		signer, err := key.Signer(defaultKeyID)
+		if err != nil {
+			return nil, err
+		}
+		s.signers = append(s.signers, signer)
+	}
+	return s, nil
+}
+
+// Sign signs the payload with the current key.
+func (s *FallbackSigner) Sign(payload []byte) (*jose.JSONWebSignature, error) {
+	return s.signer().Sign(payload)
+}
+
+// Options returns the options of the current key's signer.
+func (s *FallbackSigner) Options() jose.SignerOptions {
+	return s.signer().Options()
+}
+
+// Current returns the index of the key in use.
+func (s *FallbackSigner) Current() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.current
+}
+
+// Resign marks the key that signed the rejected assertion as rejected and
+// signs the assertion's claims with the next key. It returns false when there
+// is no other key to try.
+func (s *FallbackSigner) Resign(assertion string) (string, bool) {
+	jws, err := jose.ParseSigned(assertion)
+	if err != nil {
+		return "", false
+	}
+	rejected := -1
+	for i, key := range s.keys {
+		if _, err := jws.Verify(key.public()); err == nil {
+			rejected = i
+			break
+		}
+	}
+
+	s.lock.Lock()
+	if rejected < 0 || (rejected == s.current && s.current == len(s.signers)-1) {
+		s.lock.Unlock()
+		return "", false
+	}
+	// concurrent rejections of the same key only move on once
+	if rejected == s.current {
+		s.current++
+	}
+	signer := s.signers[s.current]
+	s.lock.Unlock()
+
+	resigned, err := signer.Sign(jws.UnsafePayloadWithoutVerification())
+	if err != nil {
+		return "", false
+	}
+	compact, err := resigned.CompactSerialize()
+	if err != nil {
+		return "", false
+	}
+	return compact, true
+}
+
+func (s *FallbackSigner) signer() jose.Signer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.signers[s.current]
+}
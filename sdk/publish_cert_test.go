@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishBinaryPemCertSendsTheCertificate(t *testing.T) {
+	const cert = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	var body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, contentType = string(b), r.Header.Get("Content-Type")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kid":"kid1"}`))
+	}))
+	defer server.Close()
+
+	_, client, err := NewClient(context.Background(),
+		WithOrgUrl(server.URL),
+		WithToken("token"),
+		WithTestingDisableHttpsCheck(true),
+	)
+	require.NoError(t, err)
+
+	key, _, err := client.Application.PublishBinaryPemCert(context.Background(), "0oa1", "csr1", cert)
+	require.NoError(t, err)
+	require.Equal(t, "kid1", key.Kid)
+	require.Equal(t, cert, body)
+	require.Equal(t, "application/x-pem-file", contentType)
+}
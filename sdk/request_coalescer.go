@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// RequestCoalescer merges identical requests that are in flight at the same
+// time into a single http call. The first caller makes the call, the others
+// wait for it, and every caller gets its own copy of the response. Unlike the
+// response cache nothing outlives the call, so there is no risk of staleness.
+type RequestCoalescer struct {
+	lock  sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done chan struct{}
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// NewRequestCoalescer returns a request coalescer.
+func NewRequestCoalescer() *RequestCoalescer {
+	return &RequestCoalescer{
+		calls: map[string]*coalescedCall{},
+	}
+}
+
+// CoalesceKey returns the key under which the request is coalesced, GETs for
+// the same url with the same authorization and accept headers are identical.
+// Other requests have an empty key and are never coalesced.
+func CoalesceKey(req *http.Request) string {
+	if req.Method != http.MethodGet {
+		return ""
+	}
+	return req.URL.String() + "\n" + req.Header.Get("Authorization") + "\n" + req.Header.Get("Accept")
+}
+
+// Do calls fn for the first request of the key and has the requests of the
+// same key that arrive while it is in flight wait for its response. A waiting
+// request returns early if its context is done, and makes the call itself if
+// the first request's context was done instead. An empty key calls fn right
+// away.
+func (c *RequestCoalescer) Do(ctx context.Context, key string, fn func() (*http.Response, error)) (*http.Response, error) {
+	if key == "" {
+		return fn()
+	}
+	for {
+		c.lock.Lock()
+		call, inFlight := c.calls[key]
+		if !inFlight {
+			call = &coalescedCall{done: make(chan struct{})}
+			c.calls[key] = call
+			c.lock.Unlock()
+			c.call(key, call, fn)
+			return call.response()
+		}
+		c.lock.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			continue
+		}
+		return call.response()
+	}
+}
+
+func (c *RequestCoalescer) call(key string, call *coalescedCall, fn func() (*http.Response, error)) {
+	defer func() {
+		c.lock.Lock()
+		delete(c.calls, key)
+		c.lock.Unlock()
+		close(call.done)
+	}()
+	call.resp, call.err = fn()
+	if call.err != nil || call.resp == nil || call.resp.Body == nil {
+		return
+	}
+	call.body, call.err = io.ReadAll(call.resp.Body)
+	_ = call.resp.Body.Close()
+}
+
+// response returns a copy of the call's response with its own body.
+func (call *coalescedCall) response() (*http.Response, error) {
+	if call.resp == nil {
+		return nil, call.err
+	}
+	resp := *call.resp
+	resp.Header = call.resp.Header.Clone()
+	if call.resp.Body != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(call.body))
+	}
+	return &resp, call.err
+}
@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCoalescer(t *testing.T) {
+	coalescer := NewRequestCoalescer()
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func() (*http.Response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"0oa1"}`)),
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 5)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := coalescer.Do(context.Background(), "GET /api/v1/apps/0oa1", fn)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Header.Set("X-Caller", "changed")
+			bodies[i] = string(body)
+		}(i)
+		if i == 0 {
+			<-started
+		}
+	}
+	// let the other requests start waiting on the first one
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls), "expected identical requests to share one call")
+	for _, body := range bodies {
+		require.Equal(t, `{"id":"0oa1"}`, body, "expected every caller to get its own copy of the body")
+	}
+}
+
+func TestRequestCoalescerWaiterContext(t *testing.T) {
+	coalescer := NewRequestCoalescer()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = coalescer.Do(context.Background(), "key", func() (*http.Response, error) {
+			close(started)
+			<-release
+			return nil, context.Canceled
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := coalescer.Do(ctx, "key", func() (*http.Response, error) {
+		t.Fatal("didn't expect a canceled request to make the call")
+		return nil, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	// a waiter makes the call itself when the first request was canceled
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	resp, err := coalescer.Do(context.Background(), "key", func() (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Equal(t, "", CoalesceKey(&http.Request{Method: http.MethodPost}))
+}
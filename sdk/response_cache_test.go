@@ -0,0 +1,58 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/okta/terraform-provider-okta/sdk/cache"
+)
+
+func TestDoCachesGetsUntilMutation(t *testing.T) {
+	var gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&gets, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"0oa1"}`))
+	}))
+	defer server.Close()
+
+	_, client, err := NewClient(context.Background(),
+		WithOrgUrl(server.URL),
+		WithToken("token"),
+		WithCache(true),
+		WithCacheManager(cache.NewGoCache(0, 0)),
+		WithTestingDisableHttpsCheck(true),
+	)
+	require.NoError(t, err)
+
+	get := func() {
+		re := client.CloneRequestExecutor()
+		req, err := re.NewRequest(http.MethodGet, "/api/v1/apps/0oa1", nil)
+		require.NoError(t, err)
+		app := map[string]interface{}{}
+		_, err = re.Do(context.Background(), req, &app)
+		require.NoError(t, err)
+		require.Equal(t, "0oa1", app["id"])
+	}
+
+	for i := 0; i < 3; i++ {
+		get()
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&gets), "expected the app to be fetched once")
+
+	re := client.CloneRequestExecutor()
+	req, err := re.NewRequest(http.MethodPost, "/api/v1/apps/0oa1/lifecycle/deactivate", nil)
+	require.NoError(t, err)
+	_, err = re.Do(context.Background(), req, nil)
+	require.NoError(t, err)
+
+	get()
+	require.Equal(t, int32(2), atomic.LoadInt32(&gets), "expected the app to be fetched again after it was changed")
+}
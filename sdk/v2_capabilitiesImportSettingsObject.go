@@ -0,0 +1,13 @@
+package sdk
+
+type CapabilitiesImportSettingsObject struct {
+	Schedule *ImportScheduleObject `json:"schedule,omitempty"`
+}
+
+func NewCapabilitiesImportSettingsObject() *CapabilitiesImportSettingsObject {
+	return &CapabilitiesImportSettingsObject{}
+}
+
+func (a *CapabilitiesImportSettingsObject) IsApplicationInstance() bool {
+	return true
+}
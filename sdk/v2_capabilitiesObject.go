@@ -1,8 +1,9 @@
 package sdk
 
 type CapabilitiesObject struct {
-	Create *CapabilitiesCreateObject `json:"create,omitempty"`
-	Update *CapabilitiesUpdateObject `json:"update,omitempty"`
+	Create         *CapabilitiesCreateObject         `json:"create,omitempty"`
+	ImportSettings *CapabilitiesImportSettingsObject `json:"importSettings,omitempty"`
+	Update         *CapabilitiesUpdateObject         `json:"update,omitempty"`
 }
 
 func NewCapabilitiesObject() *CapabilitiesObject {
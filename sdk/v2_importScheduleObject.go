@@ -0,0 +1,15 @@
+package sdk
+
+type ImportScheduleObject struct {
+	FullImport        *ImportScheduleSettings `json:"fullImport,omitempty"`
+	IncrementalImport *ImportScheduleSettings `json:"incrementalImport,omitempty"`
+	Status            string                  `json:"status,omitempty"`
+}
+
+func NewImportScheduleObject() *ImportScheduleObject {
+	return &ImportScheduleObject{}
+}
+
+func (a *ImportScheduleObject) IsApplicationInstance() bool {
+	return true
+}
@@ -0,0 +1,14 @@
+package sdk
+
+type ImportScheduleSettings struct {
+	Expression string `json:"expression,omitempty"`
+	Timezone   string `json:"timezone,omitempty"`
+}
+
+func NewImportScheduleSettings() *ImportScheduleSettings {
+	return &ImportScheduleSettings{}
+}
+
+func (a *ImportScheduleSettings) IsApplicationInstance() bool {
+	return true
+}
@@ -3,9 +3,7 @@ package sdk
 import (
 	"bytes"
 	"context"
-	"crypto/x509"
 	"encoding/json"
-	"encoding/pem"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -25,7 +23,10 @@ import (
 	"gopkg.in/square/go-jose.v2/jwt"
 )
 
-const AccessTokenCacheKey = "OKTA_ACCESS_TOKEN"
+const (
+	AccessTokenCacheKey      = "OKTA_ACCESS_TOKEN"
+	AccessTokenScopeCacheKey = "OKTA_ACCESS_TOKEN_SCOPE"
+)
 
 type RequestExecutor struct {
 	httpClient        *http.Client
@@ -37,6 +38,7 @@ type RequestExecutor struct {
 	headerAccept      string
 	headerContentType string
 	freshCache        bool
+	coalescer         *RequestCoalescer
 }
 
 type ClientAssertionClaims struct {
@@ -160,6 +162,7 @@ func (a *PrivateKeyAuth) Authorize() error {
 		// occures before Okta server side expiry.
 		expiration := accessToken.ExpiresIn - 2
 		a.tokenCache.Set(AccessTokenCacheKey, accessToken.AccessToken, time.Second*time.Duration(expiration))
+		a.tokenCache.Set(AccessTokenScopeCacheKey, accessToken.Scope, time.Second*time.Duration(expiration))
 	}
 	return nil
 }
@@ -214,32 +217,19 @@ func (a *JWTAuth) Authorize() error {
 		// occures before Okta server side expiry.
 		expiration := accessToken.ExpiresIn - 2
 		a.tokenCache.Set(AccessTokenCacheKey, accessToken.AccessToken, time.Second*time.Duration(expiration))
+		a.tokenCache.Set(AccessTokenScopeCacheKey, accessToken.Scope, time.Second*time.Duration(expiration))
 	}
 	return nil
 }
 
+// CreateKeySigner returns a signer of the first key of the private key, see
+// ParsePrivateKeys for the accepted formats.
 func CreateKeySigner(privateKey, privateKeyID string) (jose.Signer, error) {
-	priv := []byte(strings.ReplaceAll(privateKey, `\n`, "\n"))
-
-	privPem, _ := pem.Decode(priv)
-	if privPem == nil {
-		return nil, errors.New("invalid private key")
-	}
-	if privPem.Type != "RSA PRIVATE KEY" {
-		return nil, fmt.Errorf("RSA private key is of the wrong type")
-	}
-
-	parsedKey, err := x509.ParsePKCS1PrivateKey(privPem.Bytes)
+	keys, err := ParsePrivateKeys([]byte(privateKey), "")
 	if err != nil {
 		return nil, err
 	}
-
-	var signerOptions *jose.SignerOptions
-	if privateKeyID != "" {
-		signerOptions = (&jose.SignerOptions{}).WithHeader("kid", privateKeyID)
-	}
-
-	return jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: parsedKey}, signerOptions)
+	return keys[0].Signer(privateKeyID)
 }
 
 func CreateClientAssertion(orgURL, clientID string, privateKeySinger jose.Signer) (clientAssertion string, err error) {
@@ -306,6 +296,7 @@ func getAccessTokenForPrivateKey(httpClient *http.Client, orgURL, clientAssertio
 func NewRequestExecutor(httpClient *http.Client, cache cache.Cache, config *config) *RequestExecutor {
 	re := RequestExecutor{
 		tokenCache: goCache.New(5*time.Minute, 10*time.Minute),
+		coalescer:  NewRequestCoalescer(),
 	}
 
 	re.httpClient = httpClient
@@ -336,6 +327,9 @@ func (re *RequestExecutor) NewRequest(method, url string, body interface{}) (*ht
 			buff = bytes.NewBuffer(v)
 		case *bytes.Buffer:
 			buff = v
+		case string:
+			// Certificates are published as they are, not as JSON.
+			buff = bytes.NewBufferString(v)
 		default:
 			buff = new(bytes.Buffer)
 			encoder := json.NewEncoder(buff)
@@ -408,6 +402,22 @@ func (re *RequestExecutor) NewRequest(method, url string, body interface{}) (*ht
 	return req, nil
 }
 
+// GrantedScopes returns the scopes Okta granted the access token of the
+// PrivateKey and JWT authorization modes, getting a token if there isn't one
+// yet. Other modes have no scopes.
+func (re *RequestExecutor) GrantedScopes() ([]string, error) {
+	mode := re.config.Okta.Client.AuthorizationMode
+	if mode != "PrivateKey" && mode != "JWT" {
+		return nil, nil
+	}
+	if _, err := re.NewRequest(http.MethodGet, "/", nil); err != nil {
+		return nil, err
+	}
+	scope, _ := re.tokenCache.Get(AccessTokenScopeCacheKey)
+	scopes, _ := scope.(string)
+	return strings.Fields(scopes), nil
+}
+
 func (re *RequestExecutor) AsBinary() *RequestExecutor {
 	re.binary = true
 	return re
@@ -431,7 +441,7 @@ func (re *RequestExecutor) RefreshNext() *RequestExecutor {
 func (re *RequestExecutor) Do(ctx context.Context, req *http.Request, v interface{}) (*Response, error) {
 	cacheKey := cache.CreateCacheKey(req)
 	if req.Method != http.MethodGet {
-		re.cache.Delete(cacheKey)
+		cache.Invalidate(re.cache, req)
 	}
 	inCache := re.cache.Has(cacheKey)
 	if re.freshCache {
@@ -440,7 +450,10 @@ func (re *RequestExecutor) Do(ctx context.Context, req *http.Request, v interfac
 		re.freshCache = false
 	}
 	if !inCache {
-		resp, err := re.doWithRetries(ctx, req)
+		// identical GETs in flight at the same time share one call
+		resp, err := re.coalescer.Do(ctx, CoalesceKey(req), func() (*http.Response, error) {
+			return re.doWithRetries(ctx, req)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -516,12 +529,19 @@ func (re *RequestExecutor) doWithRetries(ctx context.Context, req *http.Request)
 		if !tooManyRequests(resp) {
 			return nil
 		}
+		concurrencyLimited := IsConcurrencyLimitResponse(resp)
 		if err = tryDrainBody(resp.Body); err != nil {
 			return err
 		}
-		backoffDuration, err := Get429BackoffTime(resp)
-		if err != nil {
-			return err
+		var backoffDuration int64
+		if concurrencyLimited {
+			// the transport has fewer requests in flight, don't wait for reset
+			backoffDuration = ConcurrencyLimitBackoff
+		} else {
+			backoffDuration, err = Get429BackoffTime(resp)
+			if err != nil {
+				return err
+			}
 		}
 		if re.config.Okta.Client.RateLimit.MaxBackoff < backoffDuration {
 			backoffDuration = re.config.Okta.Client.RateLimit.MaxBackoff